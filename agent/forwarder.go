@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/logger"
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPollInterval interval used to check followed files for new entries
+const DefaultPollInterval = 250 * time.Millisecond
+
+// Forwarder forwards ndjson entries produced elsewhere, eg: by a sidecar container,
+// through the writer of a logger driver, reusing its sinks as they are
+type Forwarder struct {
+	PollInterval time.Duration
+
+	writer    io.Writer
+	close     func() error
+	forwarded atomic.Uint64
+	invalid   atomic.Uint64
+}
+
+// NewForwarder returns a forwarder writing entries to w
+func NewForwarder(w io.Writer) *Forwarder {
+	return &Forwarder{PollInterval: DefaultPollInterval, writer: w}
+}
+
+// NewDriverForwarder returns a forwarder writing entries to the sinks of the logger created by factory
+func NewDriverForwarder(ctx context.Context, factory logger.Factory, configuration logger.Configuration) (*Forwarder, error) {
+	log, err := factory.Create(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	w, ok := log.(interface{ Writer() io.Writer })
+	if !ok {
+		return nil, fmt.Errorf("logger driver %s doesn't expose its writer", configuration.Driver)
+	}
+
+	forwarder := NewForwarder(w.Writer())
+	if s, ok := log.(logger.Syncer); ok {
+		forwarder.close = s.Close
+	}
+
+	return forwarder, nil
+}
+
+// Forwarded number of entries forwarded
+func (f *Forwarder) Forwarded() uint64 {
+	return f.forwarded.Load()
+}
+
+// Invalid number of lines skipped for not being json
+func (f *Forwarder) Invalid() uint64 {
+	return f.invalid.Load()
+}
+
+// Forward forwards every line of r until EOF or ctx is done
+func (f *Forwarder) Forward(ctx context.Context, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		f.forward(scanner.Bytes())
+	}
+
+	return scanner.Err()
+}
+
+// ForwardFile forwards the entries of the file at path. when follow is set, it keeps tailing
+// the file until ctx is done, reopening it when rotated or truncated
+func (f *Forwarder) ForwardFile(ctx context.Context, path string, follow bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	var partial []byte
+	var offset int64
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		offset += int64(len(line))
+		partial = append(partial, line...)
+
+		if err == nil {
+			f.forward(partial)
+			partial = partial[:0]
+			continue
+		}
+
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if !follow {
+			f.forward(partial)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(f.pollInterval()):
+		}
+
+		reopened, err := reopenIfRotated(file, path, offset)
+		if err != nil {
+			return err
+		}
+
+		if reopened != nil {
+			_ = file.Close()
+			file = reopened
+			reader.Reset(file)
+			partial = partial[:0]
+			offset = 0
+		}
+	}
+}
+
+// Close closes the underlying logger, when created from a driver
+func (f *Forwarder) Close() error {
+	if f.close == nil {
+		return nil
+	}
+
+	return f.close()
+}
+
+func (f *Forwarder) forward(line []byte) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	if !json.Valid(line) {
+		f.invalid.Add(1)
+		return
+	}
+
+	entry := make([]byte, 0, len(line)+1)
+	entry = append(append(entry, line...), '\n')
+	if _, err := f.writer.Write(entry); err == nil {
+		f.forwarded.Add(1)
+	}
+}
+
+func (f *Forwarder) pollInterval() time.Duration {
+	if f.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+
+	return f.PollInterval
+}
+
+// reopenIfRotated returns the file now at path when file was renamed away or truncated, nil otherwise
+func reopenIfRotated(file *os.File, path string, offset int64) (*os.File, error) {
+	current, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := os.Stat(path)
+	if err != nil {
+		// rotated away and not recreated yet
+		return nil, nil
+	}
+
+	if os.SameFile(current, latest) && latest.Size() >= offset {
+		return nil, nil
+	}
+
+	return os.Open(path)
+}
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"github.com/pixie-sh/logger-go/logger"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestForward(t *testing.T) {
+	out := new(lockedBuffer)
+	forwarder := NewForwarder(out)
+
+	err := forwarder.Forward(context.Background(), strings.NewReader("{\"a\":1}\nnot json\n\n{\"b\":2}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", out.String())
+	assert.Equal(t, uint64(2), forwarder.Forwarded())
+	assert.Equal(t, uint64(1), forwarder.Invalid())
+}
+
+func TestForwardFileFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("{\"n\":1}\n{\"n\":"), 0o644))
+
+	out := new(lockedBuffer)
+	forwarder := NewForwarder(out)
+	forwarder.PollInterval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- forwarder.ForwardFile(ctx, path, true) }()
+
+	assert.Eventually(t, func() bool { return out.String() == "{\"n\":1}\n" }, time.Second, time.Millisecond)
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, _ = file.WriteString("2}\n")
+	_ = file.Close()
+	assert.Eventually(t, func() bool { return out.String() == "{\"n\":1}\n{\"n\":2}\n" }, time.Second, time.Millisecond)
+
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, os.WriteFile(path, []byte("{\"n\":3}\n"), 0o644))
+	assert.Eventually(t, func() bool { return strings.HasSuffix(out.String(), "{\"n\":3}\n") }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func TestDriverForwarder(t *testing.T) {
+	factory, err := logger.NewFactory(context.Background(), logger.DefaultFactoryConfiguration)
+	assert.NoError(t, err)
+
+	out := new(lockedBuffer)
+	forwarder, err := NewDriverForwarder(context.Background(), factory, logger.Configuration{
+		Driver: logger.JSONLoggerDriver,
+		Values: map[string]any{"Writer": out},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, forwarder.Forward(context.Background(), strings.NewReader("{\"a\":1}\n")))
+	assert.NoError(t, forwarder.Close())
+	assert.Equal(t, "{\"a\":1}\n", out.String())
+}
@@ -0,0 +1,60 @@
+// pixieagent forwards ndjson entries, from stdin or a tailed file, through a configured logger driver.
+//
+//	pixieagent -config sink.json -file /var/log/app.log -follow
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/pixie-sh/logger-go/agent"
+	"github.com/pixie-sh/logger-go/logger"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	configPath := flag.String("config", "", "json file holding the logger configuration, driver and values")
+	path := flag.String("file", "", "file to forward, stdin when empty")
+	follow := flag.Bool("follow", false, "keep tailing the file")
+	flag.Parse()
+
+	if err := run(*configPath, *path, *follow); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath string, path string, follow bool) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	var configuration logger.Configuration
+	if err = json.Unmarshal(data, &configuration); err != nil {
+		return err
+	}
+
+	factory, err := logger.NewFactory(ctx, logger.DefaultFactoryConfiguration)
+	if err != nil {
+		return err
+	}
+
+	forwarder, err := agent.NewDriverForwarder(ctx, factory, configuration)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = forwarder.Close() }()
+
+	if path == "" {
+		return forwarder.Forward(ctx, os.Stdin)
+	}
+
+	return forwarder.ForwardFile(ctx, path, follow)
+}
@@ -0,0 +1,76 @@
+// pixielog renders the logger ndjson output, from stdin or files, as human readable text.
+//
+//	kubectl logs -f pod | pixielog -level WARN -field tenant_id=t1
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/pixie-sh/logger-go/logger"
+	"io"
+	"os"
+	"strings"
+)
+
+// fieldFlags repeatable key=value filter flag
+type fieldFlags map[string]string
+
+func (f fieldFlags) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f fieldFlags) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("expected key=value, got %s", value)
+	}
+	f[key] = val
+	return nil
+}
+
+func main() {
+	fields := fieldFlags{}
+	level := flag.String("level", "DEBUG", "most verbose level shown: FATAL, ERROR, WARN, LOG or DEBUG")
+	noColor := flag.Bool("no-color", false, "disable colored output")
+	flag.Var(fields, "field", "only show entries with field equal to value, key=value. repeatable")
+	flag.Parse()
+
+	maxLevel, err := logger.ParseLogLevel(*level)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	r := &renderer{out: os.Stdout, color: !*noColor && logger.IsTerminal(os.Stdout), maxLevel: maxLevel, fields: fields}
+	if flag.NArg() == 0 {
+		run(r, os.Stdin)
+		return
+	}
+
+	for _, path := range flag.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		run(r, file)
+		_ = file.Close()
+	}
+}
+
+func run(r *renderer, in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		r.render(scanner.Bytes())
+	}
+
+	if err := scanner.Err(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+	}
+}
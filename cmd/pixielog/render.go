@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/logger"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ansi colors per level
+var levelColors = map[string]string{
+	"FATAL": "\033[35m",
+	"ERROR": "\033[31m",
+	"WARN":  "\033[33m",
+	"LOG":   "\033[32m",
+	"DEBUG": "\033[36m",
+}
+
+const (
+	colorReset = "\033[0m"
+	colorDim   = "\033[2m"
+)
+
+// renderer renders ndjson entries as aligned human readable lines
+type renderer struct {
+	out      io.Writer
+	color    bool
+	maxLevel logger.LogLevelEnum
+	fields   map[string]string
+}
+
+// render writes the rendered line, non json lines are written untouched
+func (r *renderer) render(line []byte) {
+	if len(strings.TrimSpace(string(line))) == 0 {
+		return
+	}
+
+	entry, err := logger.ParseEntry(line, logger.LenientCompatibility)
+	if err != nil {
+		_, _ = fmt.Fprintln(r.out, string(line))
+		return
+	}
+
+	if !r.match(entry) {
+		return
+	}
+
+	level := strings.ToUpper(entry.Level)
+	paddedLevel := fmt.Sprintf("%-5s", level)
+	if r.color {
+		paddedLevel = levelColors[level] + paddedLevel + colorReset
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s/%s  %s", entry.Timestamp.Format(time.RFC3339Nano), paddedLevel, entry.App, entry.Scope, entry.Message)
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pair := key + "=" + fieldString(entry.Fields[key])
+		if r.color {
+			pair = colorDim + pair + colorReset
+		}
+		b.WriteString("  ")
+		b.WriteString(pair)
+	}
+
+	_, _ = fmt.Fprintln(r.out, b.String())
+}
+
+// match applies the level and field filters
+func (r *renderer) match(entry logger.ParsedEntry) bool {
+	if level, err := logger.ParseLogLevel(entry.Level); err == nil && level > r.maxLevel {
+		return false
+	}
+
+	for key, want := range r.fields {
+		value, ok := entry.Fields[key]
+		if !ok || fieldString(value) != want {
+			return false
+		}
+	}
+
+	return true
+}
+
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	out := new(bytes.Buffer)
+	r := &renderer{out: out, maxLevel: 1, fields: map[string]string{"tenant_id": "t1"}}
+
+	r.render([]byte(`{"timestamp":"2024-01-01T00:00:00Z","level":"WARN","app":"a","scope":"s","message":"slow","tenant_id":"t1","n":2}`))
+	r.render([]byte(`{"timestamp":"2024-01-01T00:00:00Z","level":"DEBUG","app":"a","scope":"s","message":"hidden","tenant_id":"t1"}`))
+	r.render([]byte(`{"timestamp":"2024-01-01T00:00:00Z","level":"ERROR","app":"a","scope":"s","message":"other","tenant_id":"t2"}`))
+	r.render([]byte(`plain text`))
+
+	assert.Equal(t, "2024-01-01T00:00:00Z WARN  a/s  slow  n=2  tenant_id=t1\nplain text\n", out.String())
+}
@@ -20,6 +20,15 @@ const DebugMode = "DEBUG_MODE"
 // LogLevel mode
 const LogLevel = "LOG_LEVEL"
 
+// Parser log output format, text, logfmt or json
+const Parser = "LOG_PARSER"
+
+// UTC forces utc timestamps
+const UTC = "LOG_UTC"
+
+// SplitStderr routes ERROR and FATAL entries of the global logger to stderr
+const SplitStderr = "LOG_SPLIT_STDERR"
+
 // IsDebugActive check if it's in debug mode
 func IsDebugActive() bool {
 	debugValue := os.Getenv(DebugMode)
@@ -28,11 +37,28 @@ func IsDebugActive() bool {
 		debugValue == "1"
 }
 
+// IsUTCForced check if timestamps must be logged in utc
+func IsUTCForced() bool {
+	value := strings.ToUpper(os.Getenv(UTC))
+	return value == "TRUE" || value == "1"
+}
+
+// IsStderrSplit check if ERROR and FATAL entries must go to stderr
+func IsStderrSplit() bool {
+	value := strings.ToUpper(os.Getenv(SplitStderr))
+	return value == "TRUE" || value == "1"
+}
+
 // EnvLogLevel get env log level
 func EnvLogLevel() string {
 	return os.Getenv(LogLevel)
 }
 
+// EnvParser log output format, text, logfmt or json. empty means auto detected
+func EnvParser() string {
+	return os.Getenv(Parser)
+}
+
 // EnvAppName app runtime name
 func EnvAppName() string {
 	return os.Getenv(AppName)
@@ -47,3 +73,9 @@ func EnvAppVersion() string {
 func EnvScope() string {
 	return os.Getenv(Scope)
 }
+
+// IsProdScope check if the scope refers to a production environment
+func IsProdScope(scope string) bool {
+	scope = strings.ToLower(scope)
+	return scope == "prod" || scope == "production" || strings.HasPrefix(scope, "prod-") || strings.HasPrefix(scope, "prod_")
+}
@@ -0,0 +1,55 @@
+// Package field typed logger.Field constructors, primitives stay unboxed until the entry is written.
+// combined with LogWith hot paths log without formatting nor child loggers
+//
+//	log.LogWith(logger.LOG, "request served", field.String("path", path), field.Duration("took", took))
+package field
+
+import (
+	"github.com/pixie-sh/logger-go/logger"
+	"time"
+)
+
+// String returns a string field
+func String(key string, value string) logger.Field {
+	return logger.String(key, value)
+}
+
+// Int returns an int field
+func Int(key string, value int) logger.Field {
+	return logger.Int(key, value)
+}
+
+// Int64 returns an int64 field
+func Int64(key string, value int64) logger.Field {
+	return logger.Int64(key, value)
+}
+
+// Float64 returns a float64 field
+func Float64(key string, value float64) logger.Field {
+	return logger.Float64(key, value)
+}
+
+// Bool returns a bool field
+func Bool(key string, value bool) logger.Field {
+	return logger.Bool(key, value)
+}
+
+// Err returns an error field under logger.ErrorField
+func Err(err error) logger.Field {
+	return logger.Err(err)
+}
+
+// Duration returns a time.Duration field
+func Duration(key string, value time.Duration) logger.Field {
+	return logger.Duration(key, value)
+}
+
+// Time returns a time.Time field
+func Time(key string, value time.Time) logger.Field {
+	return logger.Time(key, value)
+}
+
+// Any returns a field of any value
+func Any(key string, value any) logger.Field {
+	return logger.Any(key, value)
+}
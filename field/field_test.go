@@ -0,0 +1,38 @@
+package field
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/pixie-sh/logger-go/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLogWith(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log, _ := logger.NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", logger.LOG, nil)
+
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	log.With("k", "v").LogWith(logger.LOG, "typed",
+		String("user", "u1"),
+		Int("attempt", 2),
+		Bool("retry", true),
+		Duration("took", 1500*time.Millisecond),
+		Time("at", at),
+		Err(errors.New("boom")))
+	log.LogWith(logger.DEBUG, "suppressed", String("user", "u2"))
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "typed", entry["message"])
+	assert.Equal(t, "v", entry["k"])
+	assert.Equal(t, "u1", entry["user"])
+	assert.Equal(t, float64(2), entry["attempt"])
+	assert.Equal(t, true, entry["retry"])
+	assert.Equal(t, float64(1500*time.Millisecond), entry["took"])
+	assert.Equal(t, "2024-05-01T10:00:00Z", entry["at"])
+	assert.NotNil(t, entry[logger.ErrorField])
+}
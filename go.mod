@@ -3,12 +3,21 @@ module github.com/pixie-sh/logger-go
 go 1.21
 
 require (
+	github.com/goccy/go-json v0.10.3
+	github.com/klauspost/compress v1.17.11
 	github.com/mitchellh/mapstructure v1.5.0
-	github.com/stretchr/testify v1.8.4
+	github.com/stretchr/testify v1.9.0
+	go.opentelemetry.io/otel/log v0.5.0
+	golang.org/x/sys v0.20.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/otel v1.29.0 // indirect
+	go.opentelemetry.io/otel/metric v1.29.0 // indirect
+	go.opentelemetry.io/otel/trace v1.29.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/pixie-sh/logger-go/caller"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// combinedTimeFormat time format used by the combined log format
+const combinedTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogEntry single HTTP request access entry
+type AccessLogEntry struct {
+	RemoteAddr string
+	User       string
+	Time       time.Time
+	Method     string
+	URI        string
+	Proto      string
+	Status     int
+	Size       int64
+	Referer    string
+	UserAgent  string
+	Duration   time.Duration
+}
+
+// FormatCombined formats e in the Apache/NGINX combined log format
+func FormatCombined(e AccessLogEntry) string {
+	size := "-"
+	if e.Size > 0 {
+		size = strconv.FormatInt(e.Size, 10)
+	}
+
+	return fmt.Sprintf(`%s - %s [%s] "%s %s %s" %d %s "%s" "%s"`,
+		orDash(e.RemoteAddr),
+		orDash(e.User),
+		e.Time.Format(combinedTimeFormat),
+		e.Method, e.URI, e.Proto,
+		e.Status,
+		size,
+		orDash(e.Referer),
+		orDash(e.UserAgent),
+	)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// AccessLogger writes access entries either as combined log lines or as structured entries
+type AccessLogger struct {
+	writer io.Writer
+	log    Interface
+}
+
+// NewCombinedAccessLogger returns an access logger writing combined log format lines to w
+func NewCombinedAccessLogger(w io.Writer) *AccessLogger {
+	return &AccessLogger{writer: w}
+}
+
+// NewJSONAccessLogger returns an access logger emitting structured entries through log
+func NewJSONAccessLogger(log Interface) *AccessLogger {
+	return &AccessLogger{log: log}
+}
+
+// Log writes the access entry
+func (a *AccessLogger) Log(e AccessLogEntry) {
+	a.write(caller.Upper(), e)
+}
+
+func (a *AccessLogger) write(call caller.Ptr, e AccessLogEntry) {
+	if a.log == nil {
+		_, _ = io.WriteString(a.writer, FormatCombined(e)+"\n")
+		return
+	}
+
+	log := a.log.Clone().WithGroup(HTTPField).
+		With("remote_addr", e.RemoteAddr).
+		With("method", e.Method).
+		With("uri", e.URI).
+		With("proto", e.Proto).
+		With("status", e.Status).
+		With("size", e.Size).
+		With(DurationField, float64(e.Duration)/float64(time.Millisecond))
+
+	if e.User != "" {
+		log = log.With("user", e.User)
+	}
+
+	if e.Referer != "" {
+		log = log.With("referer", e.Referer)
+	}
+
+	if e.UserAgent != "" {
+		log = log.With("user_agent", e.UserAgent)
+	}
+
+	logPreformatted(log, LOG, call, fmt.Sprintf("%s %s %d", e.Method, e.URI, e.Status))
+}
+
+// Middleware logs an access entry for every request served by next
+func (a *AccessLogger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &accessRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		remoteAddr, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			remoteAddr = r.RemoteAddr
+		}
+
+		user, _, _ := r.BasicAuth()
+		a.write(caller.Self(), AccessLogEntry{
+			RemoteAddr: remoteAddr,
+			User:       user,
+			Time:       start,
+			Method:     r.Method,
+			URI:        r.RequestURI,
+			Proto:      r.Proto,
+			Status:     recorder.status,
+			Size:       recorder.size,
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+			Duration:   time.Since(start),
+		})
+	})
+}
+
+// accessRecorder captures the response status and size
+type accessRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int64
+}
+
+func (r *accessRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *accessRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the original writer to http.ResponseController
+func (r *accessRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
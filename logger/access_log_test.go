@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFormatCombined(t *testing.T) {
+	line := FormatCombined(AccessLogEntry{
+		RemoteAddr: "127.0.0.1",
+		User:       "frank",
+		Time:       time.Date(2000, 10, 10, 13, 55, 36, 0, time.FixedZone("", -7*3600)),
+		Method:     "GET",
+		URI:        "/apache_pb.gif",
+		Proto:      "HTTP/1.0",
+		Status:     200,
+		Size:       2326,
+		Referer:    "http://www.example.com/start.html",
+		UserAgent:  "Mozilla/4.08",
+	})
+
+	assert.Equal(t, `127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"`, line)
+	assert.Contains(t, FormatCombined(AccessLogEntry{Status: 204}), `204 - "-" "-"`)
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	combined := new(bytes.Buffer)
+	request := httptest.NewRequest(http.MethodPost, "/users?id=1", nil)
+	NewCombinedAccessLogger(combined).Middleware(handler).ServeHTTP(httptest.NewRecorder(), request)
+	assert.Contains(t, combined.String(), `"POST /users?id=1 HTTP/1.1" 201 5`)
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	NewJSONAccessLogger(baseLogger).Middleware(handler).ServeHTTP(httptest.NewRecorder(), request)
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "POST /users?id=1 201", entry["message"])
+	httpFields := entry[HTTPField].(map[string]any)
+	assert.Equal(t, float64(201), httpFields["status"])
+	assert.Equal(t, float64(5), httpFields["size"])
+	assert.Equal(t, "192.0.2.1", httpFields["remote_addr"])
+}
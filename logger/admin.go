@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// adminLevel level payload of the admin handler
+type adminLevel struct {
+	Level string `json:"level"`
+}
+
+// adminSinkStats stats of a writer of the logger writer chain
+type adminSinkStats struct {
+	Writer  string `json:"writer"`
+	Dropped uint64 `json:"dropped"`
+}
+
+// adminConfig configuration dump of the admin handler
+type adminConfig struct {
+	App                string             `json:"app"`
+	Scope              string             `json:"scope"`
+	UID                string             `json:"uid"`
+	Level              string             `json:"level"`
+	ScopeLevels        map[string]string  `json:"scopeLevels"`
+	Writer             string             `json:"writer"`
+	EntryID            bool               `json:"entryId"`
+	Sequence           bool               `json:"sequence"`
+	HostFields         bool               `json:"hostFields"`
+	TimestampPrecision TimestampPrecision `json:"timestampPrecision"`
+	UTC                bool               `json:"utc"`
+	LocalTime          bool               `json:"localTime"`
+	SchemaVersion      SchemaVersion      `json:"schemaVersion"`
+	Strict             bool               `json:"strict"`
+	SizeField          bool               `json:"sizeField"`
+	LevelFormat        LevelFormat        `json:"levelFormat"`
+	LevelNum           bool               `json:"levelNum"`
+}
+
+// NewAdminHandler returns an http.Handler controlling log at runtime, to mount under an admin prefix
+// with http.StripPrefix:
+//
+//	GET/PUT        /level          {"level":"DEBUG"}
+//	GET            /scopes         per scope overrides
+//	PUT/DELETE     /scopes/{scope} {"level":"DEBUG"}
+//	GET            /stats          dropped entries per writer
+//	GET            /config         configuration dump
+//
+// log levels become driven by log.Levels, created from LogLevel when missing,
+// so the handler must be created before log is used concurrently
+func NewAdminHandler(log *JsonLogger) http.Handler {
+	if log.Levels == nil {
+		log.Levels = NewLevelController(log.LogLevel)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Trim(r.URL.Path, "/")
+		switch {
+		case path == "level":
+			adminLevelHandler(w, r, log.Levels.Level, log.Levels.SetLevel)
+		case path == "scopes" && r.Method == http.MethodGet:
+			writeAdminJSON(w, levelNames(log.Levels.ScopeLevels()))
+		case strings.HasPrefix(path, "scopes/"):
+			scope := strings.TrimPrefix(path, "scopes/")
+			if r.Method == http.MethodDelete {
+				log.Levels.ResetScopeLevel(scope)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			adminLevelHandler(w, r,
+				func() LogLevelEnum { return log.Levels.LevelFor(scope) },
+				func(level LogLevelEnum) { log.Levels.SetScopeLevel(scope, level) })
+		case path == "stats" && r.Method == http.MethodGet:
+			writeAdminJSON(w, writerStats(log.writer))
+		case path == "config" && r.Method == http.MethodGet:
+			writeAdminJSON(w, adminConfig{
+				App:                log.App,
+				Scope:              log.Scope,
+				UID:                log.UID,
+				Level:              log.Levels.Level().String(),
+				ScopeLevels:        levelNames(log.Levels.ScopeLevels()),
+				Writer:             fmt.Sprintf("%T", log.writer),
+				EntryID:            log.EntryID,
+				Sequence:           log.Sequence,
+				HostFields:         log.HostFields,
+				TimestampPrecision: log.TimestampPrecision,
+				UTC:                log.UTC,
+				LocalTime:          log.LocalTime,
+				SchemaVersion:      log.SchemaVersion,
+				Strict:             log.Strict,
+				SizeField:          log.SizeField,
+				LevelFormat:        log.LevelFormat,
+				LevelNum:           log.LevelNum,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func adminLevelHandler(w http.ResponseWriter, r *http.Request, get func() LogLevelEnum, set func(LogLevelEnum)) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var body adminLevel
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		level, err := ParseLogLevel(body.Level)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		set(level)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	writeAdminJSON(w, adminLevel{Level: get().String()})
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func levelNames(levels map[string]LogLevelEnum) map[string]string {
+	names := make(map[string]string, len(levels))
+	for scope, level := range levels {
+		names[scope] = level.String()
+	}
+	return names
+}
+
+// writerStats collects the dropped counters along the writer chain
+func writerStats(w io.Writer) []adminSinkStats {
+	stats := []adminSinkStats{}
+	for w != nil {
+		switch writer := w.(type) {
+		case *FanoutWriter:
+			for _, sink := range writer.Sinks() {
+				stats = append(stats, writerStats(sink)...)
+			}
+			return stats
+		case interface{ Dropped() uint64 }:
+			stats = append(stats, adminSinkStats{Writer: fmt.Sprintf("%T", w), Dropped: writer.Dropped()})
+		}
+
+		u, ok := w.(interface{ Unwrap() io.Writer })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+
+	return stats
+}
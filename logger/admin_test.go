@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func adminRequest(t *testing.T, h http.Handler, method string, path string, body string) (int, map[string]any) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+
+	var out map[string]any
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec.Code, out
+}
+
+func TestAdminHandlerLevels(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log, _ := NewJsonLogger(context.Background(), buf, "TestApp", "payments", "TestUID", LOG, nil)
+	h := NewAdminHandler(log)
+
+	code, out := adminRequest(t, h, http.MethodGet, "/level", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "LOG", out["level"])
+
+	log.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	code, _ = adminRequest(t, h, http.MethodPut, "/level", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, code)
+	log.With("k", "v").Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	code, _ = adminRequest(t, h, http.MethodPut, "/scopes/payments", `{"level":"ERROR"}`)
+	assert.Equal(t, http.StatusOK, code)
+	buf.Reset()
+	log.Warn("hidden by scope override")
+	assert.Empty(t, buf.String())
+
+	_, out = adminRequest(t, h, http.MethodGet, "/scopes", "")
+	assert.Equal(t, "ERROR", out["payments"])
+
+	code, _ = adminRequest(t, h, http.MethodDelete, "/scopes/payments", "")
+	assert.Equal(t, http.StatusNoContent, code)
+	log.Warn("shown again")
+	assert.Contains(t, buf.String(), "shown again")
+
+	code, _ = adminRequest(t, h, http.MethodPut, "/level", `{"level":"verbose"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestAdminHandlerStatsAndConfig(t *testing.T) {
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "app", LogLevel: LOG}, JSONLoggerConfiguration{
+		Writer: new(bytes.Buffer),
+		Async:  &AsyncWriterConfiguration{},
+	})
+	assert.NoError(t, err)
+	defer func() { _ = log.Close() }()
+	h := NewAdminHandler(log)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "*logger.AsyncWriter")
+
+	code, out := adminRequest(t, h, http.MethodGet, "/config", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "app", out["app"])
+	assert.Equal(t, "LOG", out["level"])
+
+	code, _ = adminRequest(t, h, http.MethodGet, "/missing", "")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -0,0 +1,379 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/pixie-sh/logger-go/env"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BackpressurePolicy behavior of the async writer when its queue is full
+type BackpressurePolicy string
+
+// supported backpressure policies
+const (
+	// BlockPolicy blocks the caller up to BlockTimeout, then drops the entry
+	BlockPolicy BackpressurePolicy = "block"
+	// DropOldestPolicy evicts the oldest queued entry in favor of the new one
+	DropOldestPolicy BackpressurePolicy = "drop_oldest"
+	// DropNewPolicy drops the new entry
+	DropNewPolicy BackpressurePolicy = "drop_new"
+	// SpillPolicy writes the entry synchronously to the local SpillPath file
+	SpillPolicy BackpressurePolicy = "spill"
+)
+
+// async writer defaults
+const (
+	DefaultQueueSize         = 1024
+	DefaultBlockTimeout      = 100 * time.Millisecond
+	DefaultErrorFlushTimeout = time.Second
+	DefaultFlushTimeout      = 5 * time.Second
+)
+
+// AsyncWriterConfiguration async writer configuration
+type AsyncWriterConfiguration struct {
+	QueueSize    int                `toml:"queueSize" json:"queueSize" mapstructure:"queueSize"`
+	Policy       BackpressurePolicy `toml:"policy" json:"policy" mapstructure:"policy"`
+	BlockTimeout time.Duration      `toml:"blockTimeout" json:"blockTimeout" mapstructure:"blockTimeout"`
+	SpillPath    string             `toml:"spillPath" json:"spillPath" mapstructure:"spillPath"`
+
+	// ErrorFlushTimeout max time ERROR and FATAL entries wait for the entries queued up to them to be written,
+	// the entries queued afterwards are not waited for. defaults to DefaultErrorFlushTimeout
+	ErrorFlushTimeout time.Duration `toml:"errorFlushTimeout" json:"errorFlushTimeout" mapstructure:"errorFlushTimeout"`
+
+	// FlushTimeout max time Flush waits for the entries queued up to the call to be written, so a stuck destination,
+	// or a never ending stream of entries, can't hang the caller, eg: Fatal. defaults to DefaultFlushTimeout
+	FlushTimeout time.Duration `toml:"flushTimeout" json:"flushTimeout" mapstructure:"flushTimeout"`
+
+	// DropReportInterval when set, a WARN record "N entries dropped" is written every interval entries were dropped,
+	// under the global logger app and the async_writer scope
+	DropReportInterval time.Duration `toml:"dropReportInterval" json:"dropReportInterval" mapstructure:"dropReportInterval"`
+
+	// Encoder of the drop reports, defaults to JSONEncoder. json loggers set their own
+	Encoder Encoder
+}
+
+// AsyncWriter queues entries and writes them from a background goroutine,
+// so a slow destination doesn't block the logging goroutines
+type AsyncWriter struct {
+	dst          io.Writer
+	spill        io.Writer
+	policy       BackpressurePolicy
+	blockTimeout time.Duration
+	errorTimeout time.Duration
+	flushTimeout time.Duration
+	dropReport   time.Duration
+	encoder      Encoder
+
+	closeMu sync.RWMutex
+	closed  bool
+	queue   chan asyncEntry
+	done    chan struct{}
+
+	mu        sync.Mutex
+	idle      *sync.Cond
+	pending   int
+	queued    uint64
+	completed uint64
+	barriers  int
+
+	dropped atomic.Uint64
+
+	// reported dropped count already reported, owned by run
+	reported uint64
+}
+
+// NewAsyncWriter returns an async writer on top of dst, defaults to DropNewPolicy
+func NewAsyncWriter(dst io.Writer, cfg AsyncWriterConfiguration) (*AsyncWriter, error) {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+
+	if cfg.BlockTimeout <= 0 {
+		cfg.BlockTimeout = DefaultBlockTimeout
+	}
+
+	if cfg.Policy == "" {
+		cfg.Policy = DropNewPolicy
+	}
+
+	if cfg.ErrorFlushTimeout <= 0 {
+		cfg.ErrorFlushTimeout = DefaultErrorFlushTimeout
+	}
+
+	if cfg.FlushTimeout <= 0 {
+		cfg.FlushTimeout = DefaultFlushTimeout
+	}
+
+	w := &AsyncWriter{
+		dst:          dst,
+		policy:       cfg.Policy,
+		blockTimeout: cfg.BlockTimeout,
+		errorTimeout: cfg.ErrorFlushTimeout,
+		flushTimeout: cfg.FlushTimeout,
+		dropReport:   cfg.DropReportInterval,
+		encoder:      cfg.Encoder,
+		queue:        make(chan asyncEntry, cfg.QueueSize),
+		done:         make(chan struct{}),
+	}
+	w.idle = sync.NewCond(&w.mu)
+
+	switch cfg.Policy {
+	case BlockPolicy, DropOldestPolicy, DropNewPolicy:
+	case SpillPolicy:
+		spill, err := NewFileWriter(FileWriterConfiguration{Path: cfg.SpillPath})
+		if err != nil {
+			return nil, err
+		}
+		w.spill = spill
+	default:
+		return nil, fmt.Errorf("unknown backpressure policy %s", cfg.Policy)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// asyncEntry queued entry, leveled ones are written through WriteLevel
+type asyncEntry struct {
+	data    []byte
+	level   LogLevelEnum
+	leveled bool
+}
+
+// Write queues a copy of p, applying the backpressure policy when the queue is full
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.enqueue(asyncEntry{data: copyEntry(p)})
+}
+
+// WriteLevel queues a copy of p along with its level, for level aware destinations
+func (w *AsyncWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	return w.enqueue(asyncEntry{data: copyEntry(p), level: level, leveled: true})
+}
+
+// enqueue queues entry without copying it, entry data must not be modified afterwards
+func (w *AsyncWriter) enqueue(entry asyncEntry) (int, error) {
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	w.addPending(1)
+	select {
+	case w.queue <- entry:
+		return len(entry.data), nil
+	default:
+	}
+
+	switch w.policy {
+	case BlockPolicy:
+		timer := time.NewTimer(w.blockTimeout)
+		defer timer.Stop()
+
+		select {
+		case w.queue <- entry:
+			return len(entry.data), nil
+		case <-timer.C:
+		}
+	case DropOldestPolicy:
+		for {
+			select {
+			case <-w.queue:
+				w.dropped.Add(1)
+				w.addPending(-1)
+			default:
+			}
+
+			select {
+			case w.queue <- entry:
+				return len(entry.data), nil
+			default:
+			}
+		}
+	case SpillPolicy:
+		w.addPending(-1)
+		return w.spill.Write(entry.data)
+	}
+
+	w.dropped.Add(1)
+	w.addPending(-1)
+	return len(entry.data), nil
+}
+
+// Dropped number of entries dropped due to a full queue
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush waits, up to FlushTimeout, for the entries queued so far to be written and flushes the destination.
+// the entries queued meanwhile are not waited for
+func (w *AsyncWriter) Flush() error {
+	if pending := w.waitQueued(w.flushTimeout); pending > 0 {
+		return fmt.Errorf("async writer: flush timed out with %d entries pending", pending)
+	}
+
+	return flushWriter(w.dst)
+}
+
+// flushQueued waits, up to ErrorFlushTimeout, for the entries queued so far to be written, without
+// waiting for the ones queued meanwhile, then flushes the buffers of the destination
+func (w *AsyncWriter) flushQueued() {
+	if w.waitQueued(w.errorTimeout) == 0 {
+		flushBuffered(w.dst)
+	}
+}
+
+// waitQueued waits, up to timeout, for the entries queued so far to be written or dropped,
+// returning how many of them are still pending
+func (w *AsyncWriter) waitQueued(timeout time.Duration) uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	target := w.queued
+	expired := false
+	timer := time.AfterFunc(timeout, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		expired = true
+		w.idle.Broadcast()
+	})
+	defer timer.Stop()
+
+	w.barriers++
+	for w.completed < target && !expired {
+		w.idle.Wait()
+	}
+	w.barriers--
+
+	if w.completed >= target {
+		return 0
+	}
+	return target - w.completed
+}
+
+// Close stops accepting entries, drains the queue and closes the destination
+func (w *AsyncWriter) Close() error {
+	w.closeMu.Lock()
+	if w.closed {
+		w.closeMu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.closeMu.Unlock()
+
+	<-w.done
+
+	err := closeWriter(w.dst)
+	if w.spill != nil {
+		if spillErr := closeWriter(w.spill); err == nil {
+			err = spillErr
+		}
+	}
+
+	return err
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	var tick <-chan time.Time
+	if w.dropReport > 0 {
+		ticker := time.NewTicker(w.dropReport)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				w.reportDropped()
+				return
+			}
+
+			if entry.leveled {
+				_, _ = writeLevel(w.dst, entry.level, entry.data)
+			} else {
+				_, _ = w.dst.Write(entry.data)
+			}
+			w.addPending(-1)
+		case <-tick:
+			w.reportDropped()
+		}
+	}
+}
+
+// reportDropped writes the entries dropped since the last report, when reports are enabled
+func (w *AsyncWriter) reportDropped() {
+	dropped := w.dropped.Load()
+	if w.dropReport <= 0 || dropped == w.reported {
+		return
+	}
+
+	count := dropped - w.reported
+	w.reported = dropped
+
+	record, err := droppedRecord(w.encoder, "async_writer", time.Now(), count)
+	if err != nil {
+		return
+	}
+
+	_, _ = writeLevel(w.dst, WARN, record)
+}
+
+// droppedRecord WARN record reporting count dropped entries under scope, newline included,
+// encoded with encoder, JSONEncoder when nil
+func droppedRecord(encoder Encoder, scope string, now time.Time, count uint64) ([]byte, error) {
+	if encoder == nil {
+		encoder = JSONEncoder{}
+	}
+
+	record, err := encoder.Encode(map[string]any{
+		"timestamp":  now.Format(time.RFC3339Nano),
+		"level":      WARN.String(),
+		"app":        fmt.Sprintf("%s-%s", env.EnvAppName(), env.EnvAppVersion()),
+		"scope":      scope,
+		"message":    fmt.Sprintf("%d entries dropped", count),
+		DroppedField: count,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(record, '\n'), nil
+}
+
+// copyEntry returns a copy of p, queued entries outlive the caller buffer
+func copyEntry(p []byte) []byte {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	return entry
+}
+
+func (w *AsyncWriter) addPending(delta int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending += delta
+	if delta > 0 {
+		w.queued += uint64(delta)
+	} else {
+		w.completed += uint64(-delta)
+	}
+
+	if w.pending == 0 || (delta < 0 && w.barriers > 0) {
+		w.idle.Broadcast()
+	}
+}
+
+// Unwrap returns the underlying writer
+func (w *AsyncWriter) Unwrap() io.Writer {
+	return w.dst
+}
@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedWriter blocks writes until the gate is opened
+type gatedWriter struct {
+	gate chan struct{}
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// fillQueue writes entries until the worker is blocked and the queue is full
+func fillQueue(writer *AsyncWriter) {
+	_, _ = writer.Write([]byte("in flight\n"))
+	for len(writer.queue) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for n := 0; n < cap(writer.queue); n++ {
+		_, _ = writer.Write([]byte("queued\n"))
+	}
+}
+
+func TestAsyncWriterPolicies(t *testing.T) {
+	t.Run("drop_new", func(t *testing.T) {
+		dst := newGatedWriter()
+		writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{QueueSize: 2, Policy: DropNewPolicy})
+		assert.Nil(t, err)
+
+		fillQueue(writer)
+		_, _ = writer.Write([]byte("dropped\n"))
+		assert.Equal(t, uint64(1), writer.Dropped())
+
+		close(dst.gate)
+		assert.Nil(t, writer.Close())
+		assert.Equal(t, "in flight\nqueued\nqueued\n", dst.String())
+	})
+
+	t.Run("drop_oldest", func(t *testing.T) {
+		dst := newGatedWriter()
+		writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{QueueSize: 2, Policy: DropOldestPolicy})
+		assert.Nil(t, err)
+
+		fillQueue(writer)
+		_, _ = writer.Write([]byte("newest\n"))
+		assert.Equal(t, uint64(1), writer.Dropped())
+
+		close(dst.gate)
+		assert.Nil(t, writer.Flush())
+		assert.Equal(t, "in flight\nqueued\nnewest\n", dst.String())
+		assert.Nil(t, writer.Close())
+	})
+
+	t.Run("block", func(t *testing.T) {
+		dst := newGatedWriter()
+		writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{QueueSize: 1, Policy: BlockPolicy, BlockTimeout: 20 * time.Millisecond})
+		assert.Nil(t, err)
+
+		fillQueue(writer)
+		start := time.Now()
+		_, _ = writer.Write([]byte("timed out\n"))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+		assert.Equal(t, uint64(1), writer.Dropped())
+
+		close(dst.gate)
+		assert.Nil(t, writer.Close())
+	})
+
+	t.Run("spill", func(t *testing.T) {
+		spillPath := filepath.Join(t.TempDir(), "spill.log")
+		dst := newGatedWriter()
+		writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{QueueSize: 1, Policy: SpillPolicy, SpillPath: spillPath})
+		assert.Nil(t, err)
+
+		fillQueue(writer)
+		_, _ = writer.Write([]byte("spilled\n"))
+		assert.Zero(t, writer.Dropped())
+
+		close(dst.gate)
+		assert.Nil(t, writer.Close())
+		assert.Equal(t, "spilled\n", readFile(t, spillPath))
+	})
+
+	_, err := NewAsyncWriter(new(bytes.Buffer), AsyncWriterConfiguration{Policy: "yolo"})
+	assert.NotNil(t, err)
+}
+
+func TestAsyncWriterDropReport(t *testing.T) {
+	dst := newGatedWriter()
+	writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{QueueSize: 1, Policy: DropNewPolicy, DropReportInterval: time.Hour})
+	assert.Nil(t, err)
+
+	fillQueue(writer)
+	_, _ = writer.Write([]byte("dropped\n"))
+	_, _ = writer.Write([]byte("dropped\n"))
+
+	close(dst.gate)
+	assert.Nil(t, writer.Close())
+
+	lines := strings.Split(strings.TrimSpace(dst.String()), "\n")
+	assert.Len(t, lines, 3)
+
+	entry, err := ParseEntry([]byte(lines[2]), LenientCompatibility)
+	assert.Nil(t, err)
+	assert.Equal(t, "WARN", entry.Level)
+	assert.Equal(t, "2 entries dropped", entry.Message)
+	assert.Equal(t, float64(2), entry.Fields[DroppedField])
+}
+
+func TestAsyncWriterDropReportEncoder(t *testing.T) {
+	dst := newGatedWriter()
+	writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{
+		QueueSize:          1,
+		Policy:             DropNewPolicy,
+		DropReportInterval: time.Hour,
+		Encoder:            LogfmtEncoder{},
+	})
+	assert.Nil(t, err)
+
+	fillQueue(writer)
+	_, _ = writer.Write([]byte("dropped\n"))
+
+	close(dst.gate)
+	assert.Nil(t, writer.Close())
+
+	lines := strings.Split(strings.TrimSpace(dst.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[2], "dropped=1", "reports use the configured encoder")
+	assert.Contains(t, lines[2], "level=WARN")
+}
+
+func TestAsyncWriterFlushTimeout(t *testing.T) {
+	dst := newGatedWriter()
+	writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{FlushTimeout: 20 * time.Millisecond})
+	assert.Nil(t, err)
+
+	_, _ = writer.Write([]byte("stuck\n"))
+	start := time.Now()
+	assert.ErrorContains(t, writer.Flush(), "1 entries pending", "a stuck destination doesn't hang Flush")
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	close(dst.gate)
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, "stuck\n", dst.String())
+	assert.Nil(t, writer.Close())
+}
+
+// slowWriter takes delay per write
+type slowWriter struct {
+	syncWriter
+	delay time.Duration
+	mu    sync.Mutex
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.syncWriter.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.syncWriter.String()
+}
+
+func TestAsyncWriterErrorFlush(t *testing.T) {
+	dst := &slowWriter{delay: time.Millisecond}
+	writer, err := NewAsyncWriter(dst, AsyncWriterConfiguration{Policy: BlockPolicy, ErrorFlushTimeout: 5 * time.Second})
+	assert.Nil(t, err)
+	log, _ := NewJsonLogger(context.Background(), writer, "App", "Scope", "", DEBUG, nil)
+
+	// entries keep being queued faster than written, the queue never drains
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(500 * time.Microsecond):
+				log.Debug("load")
+			}
+		}
+	}()
+	stopLoad := sync.OnceFunc(func() { close(stop) })
+	defer time.AfterFunc(3*time.Second, stopLoad).Stop()
+
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	log.Error("failure")
+	elapsed := time.Since(start)
+
+	assert.Contains(t, dst.String(), "failure", "written before Error returns")
+	assert.Less(t, elapsed, 2*time.Second, "entries queued after the ERROR are not waited for")
+
+	stopLoad()
+	<-done
+	assert.Nil(t, writer.Close())
+
+	// a stuck destination delays ERROR entries up to the timeout
+	gated := newGatedWriter()
+	writer, err = NewAsyncWriter(gated, AsyncWriterConfiguration{ErrorFlushTimeout: 20 * time.Millisecond})
+	assert.Nil(t, err)
+	log, _ = NewJsonLogger(context.Background(), writer, "App", "Scope", "", DEBUG, nil)
+
+	start = time.Now()
+	log.Error("stuck")
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	close(gated.gate)
+	assert.Nil(t, writer.Close())
+	assert.Contains(t, gated.String(), "stuck")
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultBatchBytes bytes pending in a BatchWriter before it flushes
+const DefaultBatchBytes = 1 << 20
+
+// BatchSink destination receiving whole batches from a BatchWriter, eg: one api call per batch.
+// other destinations receive the batch entries in a single write
+type BatchSink interface {
+	WriteBatch(batch [][]byte) error
+}
+
+// BatchWriterConfiguration batching configuration, the first limit reached flushes the batch
+type BatchWriterConfiguration struct {
+	// MaxEntries defaults to DefaultBatchSize
+	MaxEntries int `toml:"maxEntries" json:"maxEntries" mapstructure:"maxEntries"`
+
+	// MaxBytes defaults to DefaultBatchBytes
+	MaxBytes int `toml:"maxBytes" json:"maxBytes" mapstructure:"maxBytes"`
+
+	// FlushInterval max time entries stay pending, defaults to DefaultBatchFlushInterval
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+
+	// DeadLetter spills the batches the destination fails to write to disk, replaying them once it recovers
+	DeadLetter DeadLetterConfiguration `toml:"deadLetter" json:"deadLetter" mapstructure:"deadLetter"`
+}
+
+// BatchWriter groups entries and writes them to any destination by entries, bytes and interval,
+// with a final flush on Close
+type BatchWriter struct {
+	dst   io.Writer
+	batch *batcher
+}
+
+// NewBatchWriter returns a batching writer on top of dst.
+// batches carry no entry level, so level aware destinations other than batch sinks are refused
+func NewBatchWriter(dst io.Writer, cfg BatchWriterConfiguration) (*BatchWriter, error) {
+	if _, ok := dst.(LevelWriter); ok {
+		if _, ok := dst.(BatchSink); !ok {
+			return nil, fmt.Errorf("batch writer can't keep the entry levels of %T, batch its destinations instead", dst)
+		}
+	}
+
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultBatchBytes
+	}
+
+	deadLetter, err := openDeadLetter(cfg.DeadLetter)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &BatchWriter{dst: dst}
+	w.batch = startBatcher(&batcher{
+		size:       cfg.MaxEntries,
+		maxBytes:   cfg.MaxBytes,
+		flushFn:    w.deliver,
+		deadLetter: deadLetter,
+	}, cfg.FlushInterval)
+	return w, nil
+}
+
+// Write queues the entry for the next batch
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the pending entries and flushes the destination
+func (w *BatchWriter) Flush() error {
+	if err := w.batch.flush(); err != nil {
+		return err
+	}
+
+	return flushWriter(w.dst)
+}
+
+// flushQueued writes the pending entries and drains the buffers of the destination, without syncing files
+func (w *BatchWriter) flushQueued() {
+	if err := w.batch.flush(); err == nil {
+		flushBuffered(w.dst)
+	}
+}
+
+// Close stops the periodic flush, writes the pending entries and closes the destination
+func (w *BatchWriter) Close() error {
+	return errors.Join(w.batch.close(), closeWriter(w.dst))
+}
+
+// Unwrap returns the destination
+func (w *BatchWriter) Unwrap() io.Writer {
+	return w.dst
+}
+
+func (w *BatchWriter) deliver(batch [][]byte) error {
+	if sink, ok := w.dst.(BatchSink); ok {
+		return sink.WriteBatch(batch)
+	}
+
+	_, err := w.dst.Write(bytes.Join(batch, nil))
+	return err
+}
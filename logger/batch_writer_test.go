@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type batchRecorder struct {
+	syncWriter
+	batches [][][]byte
+}
+
+func (r *batchRecorder) WriteBatch(batch [][]byte) error {
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func TestBatchWriter(t *testing.T) {
+	dst := &syncWriter{}
+	w, err := NewBatchWriter(dst, BatchWriterConfiguration{MaxEntries: 3, MaxBytes: 10, FlushInterval: time.Hour})
+	assert.Nil(t, err)
+
+	_, _ = w.Write([]byte("a\n"))
+	_, _ = w.Write([]byte("b\n"))
+	assert.Zero(t, dst.Len())
+
+	_, _ = w.Write([]byte("c\n"))
+	assert.Equal(t, "a\nb\nc\n", dst.String(), "flushed by entries")
+
+	_, _ = w.Write([]byte("0123456789\n"))
+	assert.Equal(t, "a\nb\nc\n0123456789\n", dst.String(), "flushed by bytes")
+
+	_, _ = w.Write([]byte("d\n"))
+	assert.Nil(t, w.Close())
+	assert.Equal(t, "a\nb\nc\n0123456789\nd\n", dst.String(), "flushed on close")
+	assert.Equal(t, 1, dst.closed)
+}
+
+func TestBatchWriterInterval(t *testing.T) {
+	dst := &batchRecorder{}
+	w, err := NewBatchWriter(dst, BatchWriterConfiguration{FlushInterval: 10 * time.Millisecond})
+	assert.Nil(t, err)
+	defer w.Close()
+
+	_, _ = w.Write([]byte("a\n"))
+	_, _ = w.Write([]byte("b\n"))
+	assert.Eventually(t, func() bool {
+		w.batch.flushMu.Lock()
+		defer w.batch.flushMu.Unlock()
+		return len(dst.batches) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	w.batch.flushMu.Lock()
+	defer w.batch.flushMu.Unlock()
+	assert.Equal(t, [][]byte{[]byte("a\n"), []byte("b\n")}, dst.batches[0])
+	assert.Zero(t, dst.Len(), "batch sinks receive the entries apart")
+}
+
+func TestFactoryBatchedOutput(t *testing.T) {
+	factory, _ := NewFactory(context.Background(), DefaultFactoryConfiguration)
+
+	buf := new(bytes.Buffer)
+	logger, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   JSONLoggerDriver,
+		Values: JSONLoggerConfiguration{
+			Writer: buf,
+			Batch:  &BatchWriterConfiguration{FlushInterval: time.Hour},
+		},
+	})
+	assert.Nil(t, err)
+
+	logger.Log("batched")
+	assert.Zero(t, buf.Len())
+
+	logger.Error("flushed")
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")), "ERROR entries flush the batch")
+
+	logger.Log("closed")
+	assert.Nil(t, logger.(Syncer).Close())
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestBatchWriterLevelAwareDestination(t *testing.T) {
+	_, err := NewBatchWriter(NewMultiWriter(), BatchWriterConfiguration{})
+	assert.NotNil(t, err, "batches would lose the entry levels")
+
+	factory, _ := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	_, err = factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   MultiLoggerDriver,
+		Values: map[string]any{
+			"batch":        map[string]any{"maxEntries": 10},
+			"destinations": []map[string]any{{"level": "log", "Writer": &syncWriter{}}},
+		},
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// batching defaults
+const (
+	DefaultBatchSize          = 100
+	DefaultBatchFlushInterval = time.Second
+)
+
+// batcher groups entries and hands them to flushFn when size entries are pending
+// or every interval. flushes never overlap, background flush errors go to the DiagnosticsHandler
+type batcher struct {
+	size    int
+	flushFn func(batch [][]byte) error
+
+	// maxBytes flushes once the pending entries reach it, unbounded when zero
+	maxBytes int
+
+	// deadLetter receives the batches flushFn fails to deliver, replayed once flushes succeed again
+	deadLetter *DeadLetterQueue
+
+	mu           sync.Mutex
+	pending      [][]byte
+	pendingBytes int
+	closed       bool
+
+	flushMu sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newBatcher(size int, interval time.Duration, flushFn func(batch [][]byte) error) *batcher {
+	return startBatcher(&batcher{size: size, flushFn: flushFn}, interval)
+}
+
+// newSinkBatcher batcher spilling undelivered batches to the dead letter directory of cfg, when set
+func newSinkBatcher(size int, interval time.Duration, cfg DeadLetterConfiguration, flushFn func(batch [][]byte) error) (*batcher, error) {
+	deadLetter, err := openDeadLetter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return startBatcher(&batcher{size: size, flushFn: flushFn, deadLetter: deadLetter}, interval), nil
+}
+
+// startBatcher applies the defaults to b and starts its periodic flush
+func startBatcher(b *batcher, interval time.Duration) *batcher {
+	if b.size <= 0 {
+		b.size = DefaultBatchSize
+	}
+
+	if interval <= 0 {
+		interval = DefaultBatchFlushInterval
+	}
+
+	b.stop = make(chan struct{})
+	b.done = make(chan struct{})
+
+	go b.run(interval)
+	return b
+}
+
+// add queues a copy of p, flushing synchronously when the batch is full in entries or bytes
+func (b *batcher) add(p []byte) error {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return io.ErrClosedPipe
+	}
+
+	b.pending = append(b.pending, entry)
+	b.pendingBytes += len(entry)
+	full := len(b.pending) >= b.size || (b.maxBytes > 0 && b.pendingBytes >= b.maxBytes)
+	b.mu.Unlock()
+
+	if full {
+		return b.flush()
+	}
+	return nil
+}
+
+// flush hands the pending entries to flushFn. with a dead letter queue, undelivered batches are spilled
+// to it, rejected ones moved aside, and the spilled ones replayed after a successful flush
+func (b *batcher) flush() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.pendingBytes = 0
+	b.mu.Unlock()
+
+	if b.deadLetter == nil {
+		if len(batch) == 0 {
+			return nil
+		}
+		return b.flushFn(batch)
+	}
+
+	delivered := len(batch) > 0
+	if len(batch) > 0 {
+		err := b.flushFn(batch)
+
+		var partial PartialDeliveryError
+		if errors.As(err, &partial) {
+			if len(partial.Rejected) > 0 {
+				if rejectErr := b.deadLetter.Reject(partial.Rejected, partial.RejectedErr); rejectErr != nil {
+					return errors.Join(err, rejectErr)
+				}
+			}
+			batch, err = partial.Undelivered, partial.Err
+		}
+
+		if err != nil && deliveryRejected(err) {
+			return b.deadLetter.Reject(batch, err)
+		}
+
+		if err != nil {
+			spillErr := b.deadLetter.Spill(batch)
+			if spillErr != nil {
+				return errors.Join(err, spillErr)
+			}
+
+			reportDiagnostic(fmt.Errorf("%d entries spilled to dead letter: %w", len(batch), err))
+			return nil
+		}
+	}
+
+	// replaying on empty flushes too, the periodic flush probes the sink for recovery.
+	// failed replays only count towards MaxReplays when the sink just accepted a batch
+	if b.deadLetter.Pending() > 0 {
+		if err := b.deadLetter.replay(b.flushFn, delivered); err != nil {
+			reportDiagnostic(fmt.Errorf("dead letter replay: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// close stops the periodic flush and flushes the pending entries
+func (b *batcher) close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.stop)
+	<-b.done
+
+	return b.flush()
+}
+
+func (b *batcher) run(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			if err := b.flush(); err != nil {
+				reportDiagnostic(err)
+			}
+		}
+	}
+}
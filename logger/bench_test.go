@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+func BenchmarkRootLog(b *testing.B) {
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		log.Log("benchmark entry")
+	}
+}
+
+// allocations of entries logged from a test closure, one above their benchmarks for the closure caller path.
+// raise them only for a deliberate trade off
+const (
+	rootLogAllocs = 35
+	logWithAllocs = 36
+)
+
+func TestRootLogAllocs(t *testing.T) {
+	if raceEnabled || defaultJSONBackend != StdlibJSONBackend {
+		t.Skip("allocations are measured with the stdlib backend without the race detector")
+	}
+
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		log.Log("benchmark entry")
+	})
+	assert.LessOrEqual(t, allocs, float64(rootLogAllocs))
+}
+
+func TestLogWithAllocs(t *testing.T) {
+	if raceEnabled || defaultJSONBackend != StdlibJSONBackend {
+		t.Skip("allocations are measured with the stdlib backend without the race detector")
+	}
+
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		log.LogWith(LOG, "benchmark entry", String("user", "u1"), Int("attempt", 2), Duration("took", time.Millisecond))
+	})
+	assert.LessOrEqual(t, allocs, float64(logWithAllocs), "typed fields are encoded without boxing")
+}
+
+func BenchmarkChildLog(b *testing.B) {
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+	child := log.With("request", "r1").WithF(String("user", "u1"), Int("attempt", 2))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		child.Log("benchmark entry")
+	}
+}
+
+func BenchmarkEvent(b *testing.B) {
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		log.Event(LOG).Str("user", "u1").Int("attempt", 2).Msg("benchmark entry")
+	}
+}
+
+func BenchmarkLogWith(b *testing.B) {
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		log.LogWith(LOG, "benchmark entry", String("user", "u1"), Int("attempt", 2), Duration("took", time.Millisecond))
+	}
+}
+
+func BenchmarkParallelLog(b *testing.B) {
+	log, _ := NewJsonLogger(context.Background(), io.Discard, "app", "scope", "uid", DEBUG, nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			log.Log("benchmark entry")
+		}
+	})
+}
+
+func BenchmarkNullDriver(b *testing.B) {
+	log, _ := createNullLogger(context.Background(), Configuration{})
+	child := log.With("request", "r1").WithF(String("user", "u1"))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		child.Log("benchmark entry")
+	}
+}
@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// buffered writer defaults
+const (
+	DefaultBufferSize    = 64 * 1024
+	DefaultFlushInterval = time.Second
+)
+
+// BufferedWriterConfiguration buffered output configuration
+type BufferedWriterConfiguration struct {
+	// Size of the buffer in bytes, defaults to DefaultBufferSize
+	Size int `toml:"size" json:"size" mapstructure:"size"`
+
+	// FlushInterval max time entries stay buffered, defaults to DefaultFlushInterval
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+}
+
+// BufferedWriter buffers writes in memory, flushing when full, periodically and on demand.
+// entries written through WriteLevel keep their level up to a level aware destination
+type BufferedWriter struct {
+	mu   sync.Mutex
+	buf  *bufio.Writer
+	dst  io.Writer
+	size int
+
+	// leveled entries buffered along with their level, flushed apart from buf to keep the order
+	leveled      []asyncEntry
+	leveledBytes int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewBufferedWriter returns a buffered writer on top of dst
+func NewBufferedWriter(dst io.Writer, cfg BufferedWriterConfiguration) *BufferedWriter {
+	if cfg.Size <= 0 {
+		cfg.Size = DefaultBufferSize
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+
+	w := &BufferedWriter{
+		buf:  bufio.NewWriterSize(dst, cfg.Size),
+		dst:  dst,
+		size: cfg.Size,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go w.flushLoop(cfg.FlushInterval)
+	return w
+}
+
+// Write buffers p, entries bigger than the available space flush the buffer first
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.flushLeveledLocked(); err != nil {
+		return 0, err
+	}
+
+	// keep entries whole in the underlying writer
+	if len(p) > w.buf.Available() && w.buf.Buffered() > 0 {
+		if err := w.buf.Flush(); err != nil {
+			return 0, err
+		}
+	}
+
+	return w.buf.Write(p)
+}
+
+// WriteLevel buffers p along with its level when the underlying writer is level aware, otherwise it's Write
+func (w *BufferedWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	if _, ok := w.dst.(LevelWriter); !ok {
+		return w.Write(p)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.buf.Flush(); err != nil {
+		return 0, err
+	}
+
+	w.leveled = append(w.leveled, asyncEntry{data: copyEntry(p), level: level, leveled: true})
+	w.leveledBytes += len(p)
+	if w.leveledBytes >= w.size {
+		if err := w.flushLeveledLocked(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the buffered entries to the underlying writer
+func (w *BufferedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.flushLocked(); err != nil {
+		return err
+	}
+
+	return flushWriter(w.dst)
+}
+
+// flushQueued writes the buffered entries to the underlying writer and drains its own buffers, without
+// syncing files, which is left to Flush and Close
+func (w *BufferedWriter) flushQueued() {
+	w.mu.Lock()
+	err := w.flushLocked()
+	w.mu.Unlock()
+
+	if err == nil {
+		flushBuffered(w.dst)
+	}
+}
+
+// Close stops the periodic flush, flushes and closes the underlying writer
+func (w *BufferedWriter) Close() error {
+	select {
+	case <-w.stop:
+		return nil
+	default:
+		close(w.stop)
+	}
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.flushLocked(); err != nil {
+		return err
+	}
+
+	return closeWriter(w.dst)
+}
+
+// flushLocked writes both buffers, at most one of them holds entries at a time
+func (w *BufferedWriter) flushLocked() error {
+	if err := w.flushLeveledLocked(); err != nil {
+		return err
+	}
+
+	return w.buf.Flush()
+}
+
+// flushLeveledLocked writes the leveled entries in order, keeping the unwritten ones on error
+func (w *BufferedWriter) flushLeveledLocked() error {
+	for len(w.leveled) > 0 {
+		entry := w.leveled[0]
+		if _, err := writeLevel(w.dst, entry.level, entry.data); err != nil {
+			return err
+		}
+
+		w.leveled = w.leveled[1:]
+		w.leveledBytes -= len(entry.data)
+	}
+
+	w.leveled = nil
+	return nil
+}
+
+func (w *BufferedWriter) flushLoop(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.flushQueued()
+		}
+	}
+}
+
+// Unwrap returns the underlying writer
+func (w *BufferedWriter) Unwrap() io.Writer {
+	return w.dst
+}
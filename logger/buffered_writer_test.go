@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fsyncRecorder file like writer counting syncs
+type fsyncRecorder struct {
+	bytes.Buffer
+	synced int
+}
+
+func (w *fsyncRecorder) Sync() error {
+	w.synced++
+	return nil
+}
+
+func TestBufferedWriterErrorFlushDoesNotSync(t *testing.T) {
+	file := &fsyncRecorder{}
+	writer := NewBufferedWriter(file, BufferedWriterConfiguration{FlushInterval: time.Hour})
+	baseLogger, _ := NewJsonLogger(context.Background(), writer, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	baseLogger.Log("buffered")
+	assert.Empty(t, file.String())
+
+	baseLogger.Error("failed")
+	assert.Equal(t, 2, strings.Count(file.String(), "\n"), "ERROR entries drain the buffer")
+	assert.Equal(t, 0, file.synced, "syncing is left to an explicit Flush")
+
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, 1, file.synced)
+	assert.Nil(t, writer.Close())
+}
+
+func TestConfiguredChainErrorFlushDoesNotSync(t *testing.T) {
+	file := &fsyncRecorder{}
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "TestApp", Scope: "TestScope", LogLevel: DEBUG}, JSONLoggerConfiguration{
+		Writer:    file,
+		Buffer:    &BufferedWriterConfiguration{FlushInterval: time.Hour},
+		RateLimit: &RateLimitWriterConfiguration{Rate: 100},
+		Async:     &AsyncWriterConfiguration{Policy: BlockPolicy},
+	})
+	assert.Nil(t, err)
+
+	log.Error("failed")
+	assert.Equal(t, 1, strings.Count(file.String(), "\n"), "ERROR entries drain every wrapper")
+	assert.Equal(t, 0, file.synced)
+	assert.Nil(t, log.Close())
+}
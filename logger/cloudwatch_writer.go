@@ -0,0 +1,272 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PutLogEvents limits
+const (
+	CloudWatchMaxBatchEvents = 10000
+	CloudWatchMaxBatchBytes  = 1048576
+	CloudWatchMaxEventBytes  = 262144
+	CloudWatchEventOverhead  = 26
+	CloudWatchMaxBatchSpan   = 24 * time.Hour
+)
+
+// errors the CloudWatchLogsClient adapters map the service errors to
+var (
+	ErrCloudWatchResourceExists   = errors.New("cloudwatch: resource already exists")
+	ErrCloudWatchResourceNotFound = errors.New("cloudwatch: resource not found")
+	ErrCloudWatchThrottled        = errors.New("cloudwatch: throttled")
+)
+
+// CloudWatchInvalidSequenceTokenError rejected sequence token, with the one expected by the stream
+type CloudWatchInvalidSequenceTokenError struct {
+	Expected string
+}
+
+// Error returns the rejection description
+func (e CloudWatchInvalidSequenceTokenError) Error() string {
+	return fmt.Sprintf("cloudwatch: invalid sequence token, expected %s", e.Expected)
+}
+
+// CloudWatchEvent log event of a PutLogEvents batch
+type CloudWatchEvent struct {
+	Timestamp int64
+	Message   string
+}
+
+// CloudWatchLogsClient CloudWatch Logs api, implemented by adapters over the aws sdk.
+// adapters return the Err* errors above and CloudWatchInvalidSequenceTokenError for the matching service errors
+type CloudWatchLogsClient interface {
+	CreateLogGroup(ctx context.Context, group string) error
+	CreateLogStream(ctx context.Context, group string, stream string) error
+
+	// PutLogEvents returns the next sequence token, empty when the service no longer issues them
+	PutLogEvents(ctx context.Context, group string, stream string, sequenceToken string, events []CloudWatchEvent) (string, error)
+}
+
+// CloudWatchWriterConfiguration CloudWatch Logs sink configuration
+type CloudWatchWriterConfiguration struct {
+	Client    CloudWatchLogsClient
+	LogGroup  string `toml:"logGroup" json:"logGroup" mapstructure:"logGroup"`
+	LogStream string `toml:"logStream" json:"logStream" mapstructure:"logStream"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+	Timeout       time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// MaxRetries retries of a throttled batch, backing off from RetryBackoff
+	MaxRetries   int           `toml:"maxRetries" json:"maxRetries" mapstructure:"maxRetries"`
+	RetryBackoff time.Duration `toml:"retryBackoff" json:"retryBackoff" mapstructure:"retryBackoff"`
+
+	// DeadLetter spills the batches failed after the retries to disk, replaying them once delivery recovers
+	DeadLetter DeadLetterConfiguration `toml:"deadLetter" json:"deadLetter" mapstructure:"deadLetter"`
+}
+
+// CloudWatchLoggerConfiguration json logger shipping to CloudWatch Logs
+type CloudWatchLoggerConfiguration struct {
+	JSONLoggerConfiguration       `mapstructure:",squash"`
+	CloudWatchWriterConfiguration `mapstructure:",squash"`
+}
+
+// CloudWatchWriter batches entries into PutLogEvents calls within the api limits, creating the log group
+// and stream when missing. events are timestamped with the entry timestamp
+type CloudWatchWriter struct {
+	cfg   CloudWatchWriterConfiguration
+	batch *batcher
+
+	mu       sync.Mutex
+	token    string
+	prepared bool
+}
+
+// NewCloudWatchWriter returns a CloudWatch Logs writer shipping through cfg.Client
+func NewCloudWatchWriter(cfg CloudWatchWriterConfiguration) (*CloudWatchWriter, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("cloudwatch writer requires a Client")
+	}
+
+	if cfg.LogGroup == "" || cfg.LogStream == "" {
+		return nil, fmt.Errorf("cloudwatch writer requires a LogGroup and LogStream")
+	}
+
+	if cfg.BatchSize <= 0 || cfg.BatchSize > CloudWatchMaxBatchEvents {
+		cfg.BatchSize = CloudWatchMaxBatchEvents
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	w := &CloudWatchWriter{cfg: cfg}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.ship)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next batch
+func (w *CloudWatchWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush ships the pending entries
+func (w *CloudWatchWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close ships the pending entries
+func (w *CloudWatchWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *CloudWatchWriter) ship(batch [][]byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var errs []error
+	for _, events := range cloudWatchBatches(cloudWatchEvents(batch, time.Now())) {
+		if err := w.put(events); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// put sends events, preparing the group and stream when missing and resending with the expected sequence token
+func (w *CloudWatchWriter) put(events []CloudWatchEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	if !w.prepared {
+		if err := w.prepare(ctx); err != nil {
+			return err
+		}
+	}
+
+	return retry(ctx, w.cfg.MaxRetries, w.cfg.RetryBackoff, cloudWatchRetryable, func() error {
+		token, err := w.cfg.Client.PutLogEvents(ctx, w.cfg.LogGroup, w.cfg.LogStream, w.token, events)
+
+		var invalid CloudWatchInvalidSequenceTokenError
+		switch {
+		case errors.As(err, &invalid):
+			w.token = invalid.Expected
+		case errors.Is(err, ErrCloudWatchResourceNotFound):
+			w.token = ""
+			if prepareErr := w.prepare(ctx); prepareErr != nil {
+				return prepareErr
+			}
+		case err == nil:
+			w.token = token
+		}
+
+		return err
+	})
+}
+
+// prepare creates the log group and stream, existing ones are kept
+func (w *CloudWatchWriter) prepare(ctx context.Context) error {
+	err := w.cfg.Client.CreateLogGroup(ctx, w.cfg.LogGroup)
+	if err != nil && !errors.Is(err, ErrCloudWatchResourceExists) {
+		return err
+	}
+
+	err = w.cfg.Client.CreateLogStream(ctx, w.cfg.LogGroup, w.cfg.LogStream)
+	if err != nil && !errors.Is(err, ErrCloudWatchResourceExists) {
+		return err
+	}
+
+	w.prepared = true
+	return nil
+}
+
+// cloudWatchRetryable throttled, rejected sequence token and missing resources, recreated before the retry
+func cloudWatchRetryable(err error) bool {
+	var invalid CloudWatchInvalidSequenceTokenError
+	return errors.Is(err, ErrCloudWatchThrottled) || errors.Is(err, ErrCloudWatchResourceNotFound) || errors.As(err, &invalid)
+}
+
+// cloudWatchEvents converts entries to events in chronological order, truncating the ones above the size limit.
+// entries without a parsable timestamp use now
+func cloudWatchEvents(batch [][]byte, now time.Time) []CloudWatchEvent {
+	events := make([]CloudWatchEvent, len(batch))
+	for i, entry := range batch {
+		timestamp := now
+		if t, err := time.Parse(time.RFC3339Nano, entryField(entry, "timestamp")); err == nil {
+			timestamp = t
+		}
+
+		message := bytes.TrimRight(entry, "\n")
+		if len(message) > CloudWatchMaxEventBytes-CloudWatchEventOverhead {
+			message = message[:CloudWatchMaxEventBytes-CloudWatchEventOverhead]
+		}
+
+		events[i] = CloudWatchEvent{Timestamp: timestamp.UnixMilli(), Message: string(message)}
+	}
+
+	sort.SliceStable(events, func(a, b int) bool {
+		return events[a].Timestamp < events[b].Timestamp
+	})
+
+	return events
+}
+
+// cloudWatchBatches splits chronological events by the PutLogEvents count, size and time span limits
+func cloudWatchBatches(events []CloudWatchEvent) [][]CloudWatchEvent {
+	var batches [][]CloudWatchEvent
+	start, size := 0, 0
+	for i, event := range events {
+		eventSize := len(event.Message) + CloudWatchEventOverhead
+		if i > start && (i-start >= CloudWatchMaxBatchEvents || size+eventSize > CloudWatchMaxBatchBytes ||
+			event.Timestamp-events[start].Timestamp >= CloudWatchMaxBatchSpan.Milliseconds()) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+
+	return batches
+}
+
+func createCloudWatchLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg CloudWatchLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewCloudWatchWriter(cfg.CloudWatchWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingCloudWatch struct {
+	groups    []string
+	streams   []string
+	puts      [][]CloudWatchEvent
+	tokens    []string
+	failures  []error
+	nextToken int
+}
+
+func (c *recordingCloudWatch) CreateLogGroup(_ context.Context, group string) error {
+	c.groups = append(c.groups, group)
+	if len(c.groups) > 1 {
+		return ErrCloudWatchResourceExists
+	}
+	return nil
+}
+
+func (c *recordingCloudWatch) CreateLogStream(_ context.Context, _ string, stream string) error {
+	c.streams = append(c.streams, stream)
+	return nil
+}
+
+func (c *recordingCloudWatch) PutLogEvents(_ context.Context, _ string, _ string, token string, events []CloudWatchEvent) (string, error) {
+	c.tokens = append(c.tokens, token)
+	if len(c.failures) > 0 {
+		err := c.failures[0]
+		c.failures = c.failures[1:]
+		return "", err
+	}
+
+	c.puts = append(c.puts, events)
+	c.nextToken++
+	return "token-" + string(rune('0'+c.nextToken)), nil
+}
+
+func TestCloudWatchDriver(t *testing.T) {
+	client := &recordingCloudWatch{
+		failures: []error{ErrCloudWatchThrottled, CloudWatchInvalidSequenceTokenError{Expected: "expected"}, ErrCloudWatchResourceNotFound},
+	}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   CloudWatchLoggerDriver,
+		Values:   map[string]any{"Client": client, "logGroup": "group", "logStream": "stream", "retryBackoff": time.Millisecond},
+	})
+	assert.Nil(t, err)
+
+	log.Log("first")
+	log.Log("second")
+	assert.Nil(t, log.(Syncer).Flush())
+
+	log.Log("third")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, []string{"group", "group"}, client.groups)
+	assert.Equal(t, []string{"stream", "stream"}, client.streams)
+	assert.Equal(t, []string{"", "", "expected", "", "token-1"}, client.tokens)
+	assert.Len(t, client.puts, 2)
+	assert.Len(t, client.puts[0], 2)
+	assert.Contains(t, client.puts[0][0].Message, `"message":"first"`)
+	assert.NotZero(t, client.puts[0][0].Timestamp)
+}
+
+func TestCloudWatchBatches(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := cloudWatchEvents([][]byte{
+		[]byte(`{"timestamp":"2024-01-02T00:00:00Z","message":"later"}` + "\n"),
+		[]byte(`{"timestamp":"2024-01-01T00:00:00Z","message":"first"}` + "\n"),
+		[]byte(`{"message":"untimed"}` + "\n"),
+		[]byte(strings.Repeat("a", CloudWatchMaxEventBytes)),
+	}, now)
+
+	assert.Equal(t, now.UnixMilli(), events[0].Timestamp)
+	assert.Contains(t, events[0].Message, "first")
+	assert.Len(t, events[2].Message, CloudWatchMaxEventBytes-CloudWatchEventOverhead)
+
+	batches := cloudWatchBatches(events)
+	assert.Len(t, batches, 2)
+	assert.Len(t, batches[0], 3)
+	assert.Contains(t, batches[1][0].Message, "later")
+
+	large := make([]CloudWatchEvent, 5)
+	for i := range large {
+		large[i] = CloudWatchEvent{Message: strings.Repeat("a", CloudWatchMaxEventBytes-CloudWatchEventOverhead)}
+	}
+	assert.Len(t, cloudWatchBatches(large), 2)
+}
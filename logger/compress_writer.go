@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// supported compression algorithms
+const (
+	GzipCompression = "gzip"
+	ZstdCompression = "zstd"
+)
+
+const defaultCompressFlushInterval = time.Second
+
+// CompressWriterConfiguration compressed output configuration
+type CompressWriterConfiguration struct {
+	Algorithm string `toml:"algorithm" json:"algorithm" mapstructure:"algorithm"`
+
+	// Level algorithm specific compression level, 0 uses the algorithm default
+	Level int `toml:"level" json:"level" mapstructure:"level"`
+
+	// FlushInterval period to flush compressed blocks to the underlying writer, defaults to 1s
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+}
+
+// compressEncoder streaming encoder, implemented by gzip.Writer and zstd.Encoder
+type compressEncoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// CompressWriter streams compressed entries into another writer, flushing periodically
+// so the output stays readable up to the last flush
+type CompressWriter struct {
+	mu  sync.Mutex
+	dst io.Writer
+	enc compressEncoder
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCompressWriter returns a writer compressing into dst
+func NewCompressWriter(dst io.Writer, cfg CompressWriterConfiguration) (*CompressWriter, error) {
+	enc, err := newCompressEncoder(dst, cfg.Algorithm, cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultCompressFlushInterval
+	}
+
+	w := &CompressWriter{
+		dst:  dst,
+		enc:  enc,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go w.flushLoop(cfg.FlushInterval)
+	return w, nil
+}
+
+// Write compresses p
+func (w *CompressWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.enc.Write(p)
+}
+
+// Flush writes pending compressed data to the underlying writer
+func (w *CompressWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.enc.Flush(); err != nil {
+		return err
+	}
+
+	return flushWriter(w.dst)
+}
+
+// flushQueued writes pending compressed data to the underlying writer and drains its buffers, without syncing files
+func (w *CompressWriter) flushQueued() {
+	w.mu.Lock()
+	err := w.enc.Flush()
+	w.mu.Unlock()
+
+	if err == nil {
+		flushBuffered(w.dst)
+	}
+}
+
+// Close finishes the compressed stream and closes the underlying writer
+func (w *CompressWriter) Close() error {
+	select {
+	case <-w.stop:
+		return nil
+	default:
+		close(w.stop)
+	}
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.enc.Close(); err != nil {
+		return err
+	}
+
+	return closeWriter(w.dst)
+}
+
+func (w *CompressWriter) flushLoop(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			_ = w.Flush()
+		}
+	}
+}
+
+// newCompressEncoder returns the encoder for algorithm
+func newCompressEncoder(dst io.Writer, algorithm string, level int) (compressEncoder, error) {
+	switch algorithm {
+	case GzipCompression:
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+
+		return gzip.NewWriterLevel(dst, level)
+	case ZstdCompression:
+		zstdLevel := zstd.SpeedDefault
+		if level != 0 {
+			zstdLevel = zstd.EncoderLevelFromZstd(level)
+		}
+
+		return zstd.NewWriter(dst, zstd.WithEncoderLevel(zstdLevel))
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %s", algorithm)
+	}
+}
+
+// compressExtension file extension used by algorithm
+func compressExtension(algorithm string) (string, error) {
+	switch algorithm {
+	case GzipCompression:
+		return ".gz", nil
+	case ZstdCompression:
+		return ".zst", nil
+	default:
+		return "", fmt.Errorf("unknown compression algorithm %s", algorithm)
+	}
+}
+
+// compressFile compresses path into path plus the algorithm extension, returning the new
+// path and size. the source file is kept, callers remove it
+func compressFile(path string, algorithm string) (string, int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return "", 0, err
+	}
+
+	ext, err := compressExtension(algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+
+	target := path + ext
+	dst, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return "", 0, err
+	}
+
+	enc, err := newCompressEncoder(dst, algorithm, 0)
+	if err == nil {
+		_, err = io.Copy(enc, src)
+		if closeErr := enc.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(target)
+		return "", 0, err
+	}
+
+	compressed, err := os.Stat(target)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return target, compressed.Size(), nil
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCompressWriter(t *testing.T) {
+	for _, algorithm := range []string{GzipCompression, ZstdCompression} {
+		t.Run(algorithm, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			writer, err := NewCompressWriter(buf, CompressWriterConfiguration{Algorithm: algorithm, FlushInterval: time.Hour})
+			assert.Nil(t, err)
+
+			_, _ = writer.Write([]byte(`{"message":"compressed"}` + "\n"))
+			assert.Nil(t, writer.Flush())
+			assert.NotZero(t, buf.Len(), "flush pushes compressed blocks to the writer")
+			assert.Nil(t, writer.Close())
+
+			var reader io.Reader
+			if algorithm == GzipCompression {
+				reader, err = gzip.NewReader(buf)
+			} else {
+				reader, err = zstd.NewReader(buf)
+			}
+			assert.Nil(t, err)
+
+			content, err := io.ReadAll(reader)
+			assert.Nil(t, err)
+			assert.Equal(t, `{"message":"compressed"}`+"\n", string(content))
+		})
+	}
+
+	_, err := NewCompressWriter(new(bytes.Buffer), CompressWriterConfiguration{Algorithm: "lz5"})
+	assert.NotNil(t, err)
+}
+
+func TestFileWriterCompressionFailure(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	var reported []error
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path, Compress: GzipCompression, MaxBackups: 1})
+	assert.Nil(t, err)
+	// unknown algorithms are refused upfront, here compressFile fails on it
+	writer.cfg.Compress = "yolo"
+
+	_, _ = writer.Write([]byte("first\n"))
+	assert.Nil(t, writer.Rotate())
+	writer.processing.Wait()
+
+	assert.Len(t, reported, 1)
+	assert.Len(t, backups(t, path), 1, "the backup is kept uncompressed")
+	assert.False(t, writer.backups[0].pending)
+
+	_, _ = writer.Write([]byte("second\n"))
+	assert.Nil(t, writer.Rotate())
+	assert.Nil(t, writer.Close())
+
+	rotated := backups(t, path)
+	assert.Len(t, rotated, 1, "failed backups are still evicted by retention")
+	content, _ := os.ReadFile(rotated[0])
+	assert.Equal(t, "second\n", string(content))
+}
+
+func TestFileWriterCompressesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path, Compress: GzipCompression})
+	assert.Nil(t, err)
+
+	_, _ = writer.Write([]byte("rotated\n"))
+	assert.Nil(t, writer.Rotate())
+	assert.Nil(t, writer.Close())
+
+	rotated := backups(t, path)
+	assert.Len(t, rotated, 1)
+	assert.Equal(t, ".gz", filepath.Ext(rotated[0]))
+
+	file, _ := os.Open(rotated[0])
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	assert.Nil(t, err)
+	content, _ := io.ReadAll(reader)
+	assert.Equal(t, "rotated\n", string(content))
+}
@@ -15,7 +15,29 @@ type FactoryConfiguration struct {
 // DefaultFactoryConfiguration default factory configuration that creates tje json logger
 var DefaultFactoryConfiguration = FactoryConfiguration{
 	Mapping: map[string]FactoryCreateFn{
-		JSONLoggerDriver: createJSONLogger,
+		JSONLoggerDriver:          createJSONLogger,
+		FileLoggerDriver:          createFileLogger,
+		SQLLoggerDriver:           createSQLLogger,
+		NullLoggerDriver:          createNullLogger,
+		SyslogLoggerDriver:        createSyslogLogger,
+		KafkaLoggerDriver:         createKafkaLogger,
+		NATSLoggerDriver:          createNATSLogger,
+		CloudWatchLoggerDriver:    createCloudWatchLogger,
+		KinesisLoggerDriver:       createKinesisLogger,
+		LokiLoggerDriver:          createLokiLogger,
+		ElasticsearchLoggerDriver: createElasticsearchLogger,
+		GELFLoggerDriver:          createGELFLogger,
+		DatadogLoggerDriver:       createDatadogLogger,
+		SentryLoggerDriver:        createSentryLogger,
+		OTLPLoggerDriver:          createOTLPLogger,
+		NetLoggerDriver:           createNetLogger,
+		WebhookLoggerDriver:       createWebhookLogger,
+		RedisLoggerDriver:         createRedisLogger,
+		SQSLoggerDriver:           createSQSLogger,
+		SeqLoggerDriver:           createSeqLogger,
+		NewRelicLoggerDriver:      createNewRelicLogger,
+		MQTTLoggerDriver:          createMQTTLogger,
+		LogfmtLoggerDriver:        createLogfmtLogger,
 	},
 }
 
@@ -28,9 +50,119 @@ func createJSONLogger(ctx context.Context, generic Configuration) (Interface, er
 
 	if cfg.Writer == nil {
 		cfg.Writer = os.Stdout //default
+		if cfg.Encoder == nil && cfg.JSONBackend == "" {
+			cfg.Encoder = defaultEncoder()
+		}
 	}
 
-	return NewJsonLogger(ctx, cfg.Writer, generic.App, generic.Scope, generic.UID, generic.LogLevel, append(generic.ExpectedCtxFields, TraceID))
+	return newConfiguredJsonLogger(ctx, generic, cfg)
+}
+
+func createFileLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg FileLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewFileWriter(cfg.FileWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
+
+// newConfiguredJsonLogger creates a JsonLogger applying the generic and json specific configuration
+func newConfiguredJsonLogger(ctx context.Context, generic Configuration, cfg JSONLoggerConfiguration) (*JsonLogger, error) {
+	if cfg.Encoder == nil && cfg.JSONBackend != "" {
+		encoder, err := NewJSONEncoder(cfg.JSONBackend)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Encoder = encoder
+	}
+
+	if len(cfg.Sinks) > 0 {
+		sinkQueue := cfg.SinkQueue
+		if sinkQueue.Encoder == nil {
+			sinkQueue.Encoder = cfg.Encoder
+		}
+
+		writer, err := NewFanoutWriter(sinkQueue, append([]io.Writer{cfg.Writer}, cfg.Sinks...)...)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Writer = writer
+	}
+
+	if cfg.Batch != nil {
+		writer, err := NewBatchWriter(cfg.Writer, *cfg.Batch)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Writer = writer
+	}
+
+	if cfg.Buffer != nil {
+		cfg.Writer = NewBufferedWriter(cfg.Writer, *cfg.Buffer)
+	}
+
+	if cfg.RateLimit != nil {
+		rateLimit := *cfg.RateLimit
+		if rateLimit.Encoder == nil {
+			rateLimit.Encoder = cfg.Encoder
+		}
+
+		writer, err := NewRateLimitWriter(cfg.Writer, rateLimit)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Writer = writer
+	}
+
+	if cfg.Async != nil {
+		async := *cfg.Async
+		if async.Encoder == nil {
+			async.Encoder = cfg.Encoder
+		}
+
+		writer, err := NewAsyncWriter(cfg.Writer, async)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Writer = writer
+	}
+
+	log, err := NewJsonLogger(ctx, cfg.Writer, generic.App, generic.Scope, generic.UID, generic.LogLevel, append(generic.ExpectedCtxFields, TraceID))
+	if err != nil {
+		return nil, err
+	}
+
+	log.ErrorSerializer = cfg.ErrorSerializer
+	log.EntryID = cfg.EntryID
+	log.Sequence = cfg.Sequence
+	log.HostFields = cfg.HostFields
+	log.TimestampPrecision = cfg.TimestampPrecision
+	log.UTC = log.UTC || cfg.UTC
+	log.LocalTime = cfg.LocalTime
+	log.SchemaVersion = cfg.SchemaVersion
+	log.RequiredFields = cfg.RequiredFields
+	log.Strict = cfg.Strict
+	log.SizeField = cfg.SizeField
+	log.SizeRecorder = cfg.SizeRecorder
+	log.Encoder = cfg.Encoder
+	log.LevelFormat = cfg.LevelFormat
+	log.LevelNames = cfg.LevelNames
+	log.LevelNum = cfg.LevelNum
+	log.ErrorStack = cfg.ErrorStack
+	log.DuplicateKeys = cfg.DuplicateKeys
+	if cfg.ErrorSampling != nil {
+		log.ErrorSampler = NewErrorSampler(*cfg.ErrorSampling)
+	}
+
+	return log, nil
 }
 
 // Configuration  logger generic config
@@ -42,9 +174,94 @@ type Configuration struct {
 	Driver            string       `toml:"driver" json:"driver" mapstructure:"driver"`
 	Values            any          `toml:"values" json:"values" mapstructure:"values"`
 	ExpectedCtxFields []string     `toml:"expectedCtxFields" json:"expectedCtxFields" mapstructure:"expectedCtxFields"`
+
+	// Routes per level destinations, the Factory assembles a json logger writing each entry to the routes of its level
+	Routes []LevelRoute `toml:"routes" json:"routes" mapstructure:"routes"`
 }
 
 // JSONLoggerConfiguration json logger with specific
 type JSONLoggerConfiguration struct {
-	Writer io.Writer
+	Writer          io.Writer
+	ErrorSerializer ErrorSerializer
+	ErrorSampling   *ErrorSamplerConfiguration
+
+	// Encoder entry encoding, defaults to JSONEncoder
+	Encoder Encoder
+
+	// JSONBackend json implementation used by the default encoder, eg: stdlib or goccy (logger_goccy build tag)
+	JSONBackend JSONBackend
+
+	// EntryID stamps every entry with an unique ULID under entry_id
+	EntryID bool
+
+	// Sequence numbers every entry with a monotonic seq, shared by the logger children
+	Sequence bool
+
+	// HostFields adds the host name and process id to every entry
+	HostFields bool
+
+	// TimestampPrecision s, ms, us or ns. defaults to seconds
+	TimestampPrecision TimestampPrecision
+
+	// UTC forces utc timestamps, taking precedence over LocalTime. also enabled by LOG_UTC
+	UTC bool
+
+	// LocalTime writes timestamps in the host timezone instead of utc, the default
+	LocalTime bool
+
+	// SchemaVersion when set, every entry carries it under the version field
+	SchemaVersion SchemaVersion
+
+	// RequiredFields fields every entry of a scope must carry, keyed by scope.
+	// violations are reported to the DiagnosticsHandler outside prod scopes
+	RequiredFields map[string][]string
+
+	// Strict forbids fmt style interpolation, messages must be constant and data go into fields.
+	// violations are reported to the DiagnosticsHandler outside prod scopes
+	Strict bool
+
+	// LevelFormat upper, lower or numeric. defaults to upper
+	LevelFormat LevelFormat
+
+	// LevelNames custom level strings, eg: WARN: "warning", taking precedence over LevelFormat
+	LevelNames map[LogLevelEnum]string
+
+	// ErrorStack records the call site stack of errors attached with WithError
+	ErrorStack bool
+
+	// DuplicateKeys how a key set twice is resolved: last, first or suffix. defaults to last
+	DuplicateKeys DuplicateKeyPolicy
+
+	// LevelNum adds the numeric level under level_num, next to the level string
+	LevelNum bool
+
+	// SizeField appends the encoded entry size, in bytes, under entry_bytes
+	SizeField bool
+
+	// SizeRecorder receives the encoded size of every entry, see SizeStats
+	SizeRecorder SizeRecorder
+
+	// Batch when set, entries are grouped into batches written by entries, bytes or interval, and on ERROR.
+	// level aware writers, eg: multi and routed loggers, are refused as batches carry no entry level
+	Batch *BatchWriterConfiguration
+
+	// Buffer when set, entries are buffered in memory and flushed periodically or on ERROR
+	Buffer *BufferedWriterConfiguration
+
+	// RateLimit when set, entries over the rate are dropped or summarized
+	RateLimit *RateLimitWriterConfiguration
+
+	// Async when set, entries are queued and written by a background goroutine
+	Async *AsyncWriterConfiguration
+
+	// Sinks additional destinations, entries are encoded once and dispatched to Writer
+	// and every sink concurrently, each one with its own queue configured by SinkQueue
+	Sinks     []io.Writer
+	SinkQueue AsyncWriterConfiguration
+}
+
+// FileLoggerConfiguration json logger writing to a file
+type FileLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	FileWriterConfiguration `mapstructure:",squash"`
 }
@@ -0,0 +1,228 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"strings"
+)
+
+// datadog intake defaults and limits
+const (
+	DefaultDatadogSite      = "datadoghq.com"
+	DefaultDatadogSource    = "go"
+	DatadogMaxBatchEntries  = 1000
+	DatadogMaxPayloadBytes  = 5 << 20
+	DatadogMaxEntryBytes    = 1 << 20
+	datadogIntakeURLPattern = "https://http-intake.logs.%s/api/v2/logs"
+)
+
+// DatadogStatus returns the datadog status of level
+func DatadogStatus(level LogLevelEnum) string {
+	switch level {
+	case FATAL:
+		return "critical"
+	case ERROR:
+		return "error"
+	case WARN:
+		return "warning"
+	case DEBUG:
+		return "debug"
+	default:
+		return "info"
+	}
+}
+
+// DatadogWriterConfiguration datadog logs sink configuration, URL defaults to the intake of Site
+type DatadogWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	APIKey string `toml:"apiKey" json:"apiKey" mapstructure:"apiKey"`
+
+	// Site datadog site, eg: datadoghq.eu. defaults to DefaultDatadogSite
+	Site string `toml:"site" json:"site" mapstructure:"site"`
+
+	// Source ddsource of the entries, defaults to DefaultDatadogSource
+	Source string `toml:"source" json:"source" mapstructure:"source"`
+
+	// Service defaults to the app of each entry
+	Service string `toml:"service" json:"service" mapstructure:"service"`
+
+	// Tags added to the scope:<scope> tag of each entry, eg: env:prod
+	Tags []string `toml:"tags" json:"tags" mapstructure:"tags"`
+}
+
+// DatadogLoggerConfiguration json logger submitting to datadog
+type DatadogLoggerConfiguration struct {
+	JSONLoggerConfiguration    `mapstructure:",squash"`
+	DatadogWriterConfiguration `mapstructure:",squash"`
+}
+
+// DatadogWriter batches entries into datadog log intake requests, within the payload limits.
+// entry fields are kept as attributes, with ddsource, ddtags, hostname, service and status added
+type DatadogWriter struct {
+	cfg    DatadogWriterConfiguration
+	sender *httpSender
+	header http.Header
+	batch  *batcher
+}
+
+// NewDatadogWriter returns a writer submitting to the datadog intake
+func NewDatadogWriter(cfg DatadogWriterConfiguration) (*DatadogWriter, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("datadog writer requires an APIKey")
+	}
+
+	if cfg.Site == "" {
+		cfg.Site = DefaultDatadogSite
+	}
+
+	if cfg.URL == "" {
+		cfg.URL = fmt.Sprintf(datadogIntakeURLPattern, cfg.Site)
+	}
+
+	if cfg.Source == "" {
+		cfg.Source = DefaultDatadogSource
+	}
+
+	if cfg.BatchSize <= 0 || cfg.BatchSize > DatadogMaxBatchEntries {
+		cfg.BatchSize = DatadogMaxBatchEntries
+	}
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &DatadogWriter{
+		cfg:    cfg,
+		sender: sender,
+		header: http.Header{"Content-Type": {"application/json"}, "Dd-Api-Key": {cfg.APIKey}},
+	}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.submit)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next request
+func (w *DatadogWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush submits the pending entries
+func (w *DatadogWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close submits the pending entries
+func (w *DatadogWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *DatadogWriter) submit(batch [][]byte) error {
+	var body bytes.Buffer
+	flush := func() error {
+		if body.Len() == 0 {
+			return nil
+		}
+
+		body.WriteByte(']')
+		_, err := w.sender.send("", w.header, body.Bytes())
+		body.Reset()
+		return err
+	}
+
+	for _, entry := range batch {
+		log, err := w.log(entry)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+
+		if body.Len()+len(log)+2 > DatadogMaxPayloadBytes {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		if body.Len() == 0 {
+			body.WriteByte('[')
+		} else {
+			body.WriteByte(',')
+		}
+		body.Write(log)
+	}
+
+	return flush()
+}
+
+// log converts an entry to a datadog log, entries that aren't json are sent as the message
+func (w *DatadogWriter) log(entry []byte) ([]byte, error) {
+	fields, err := decodeEntry(entry)
+	if err != nil {
+		fields = map[string]any{"message": strings.TrimRight(string(entry), "\n")}
+	}
+
+	service := w.cfg.Service
+	if service == "" {
+		service = fieldString(fields["app"])
+	}
+
+	status := DatadogStatus(LOG)
+	if level, err := ParseLogLevel(fieldString(fields["level"])); err == nil {
+		status = DatadogStatus(level)
+	}
+
+	tags := append([]string(nil), w.cfg.Tags...)
+	if scope := fieldString(fields["scope"]); scope != "" {
+		tags = append(tags, "scope:"+scope)
+	}
+
+	host := hostname
+	if value, ok := fields[HostField]; ok {
+		host = fieldString(value)
+	}
+
+	fields["ddsource"] = w.cfg.Source
+	fields["ddtags"] = strings.Join(tags, ",")
+	fields["hostname"] = host
+	fields["service"] = service
+	fields["status"] = status
+
+	log, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(log) > DatadogMaxEntryBytes {
+		return nil, fmt.Errorf("datadog log of %d bytes above the %d bytes limit", len(log), DatadogMaxEntryBytes)
+	}
+
+	return log, nil
+}
+
+func createDatadogLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg DatadogLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewDatadogWriter(cfg.DatadogWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
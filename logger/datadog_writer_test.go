@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDatadogDriver(t *testing.T) {
+	var logs []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("DD-API-KEY"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &logs))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   DatadogLoggerDriver,
+		Values:   map[string]any{"url": server.URL, "apiKey": "secret", "tags": []string{"env:test"}},
+	})
+	assert.Nil(t, err)
+
+	log.With("order", 42).Warn("payment retried")
+	log.Log("done")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, logs, 2)
+	assert.Equal(t, "payment retried", logs[0]["message"])
+	assert.Equal(t, "warning", logs[0]["status"])
+	assert.Equal(t, "App", logs[0]["service"])
+	assert.Equal(t, "go", logs[0]["ddsource"])
+	assert.Equal(t, "env:test,scope:Scope", logs[0]["ddtags"])
+	assert.Equal(t, float64(42), logs[0]["order"])
+	assert.Equal(t, "info", logs[1]["status"])
+}
+
+func TestDatadogStatus(t *testing.T) {
+	assert.Equal(t, "critical", DatadogStatus(FATAL))
+	assert.Equal(t, "error", DatadogStatus(ERROR))
+	assert.Equal(t, "debug", DatadogStatus(DEBUG))
+
+	writer, err := NewDatadogWriter(DatadogWriterConfiguration{APIKey: "key", Site: "datadoghq.eu"})
+	assert.Nil(t, err)
+	defer writer.Close()
+	assert.Equal(t, "https://http-intake.logs.datadoghq.eu/api/v2/logs", writer.cfg.URL)
+
+	_, err = NewDatadogWriter(DatadogWriterConfiguration{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,455 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dead letter defaults
+const (
+	// DefaultDeadLetterMaxBytes bound of a dead letter directory, and of its rejected directory
+	DefaultDeadLetterMaxBytes = 64 << 20
+	// DefaultDeadLetterMaxReplays failed replays before a batch is moved to the rejected directory
+	DefaultDeadLetterMaxReplays = 5
+)
+
+const (
+	deadLetterExt         = ".ndjson"
+	deadLetterRejectedDir = "rejected"
+)
+
+// DeadLetterConfiguration on-disk dead letter directory of the batches a sink failed to deliver
+type DeadLetterConfiguration struct {
+	// Dir directory holding the spilled batches, one file each. dead lettering is disabled when empty
+	Dir string `toml:"dir" json:"dir" mapstructure:"dir"`
+
+	// MaxBytes bound of the directory, the oldest batches are dropped past it. defaults to DefaultDeadLetterMaxBytes
+	MaxBytes int64 `toml:"maxBytes" json:"maxBytes" mapstructure:"maxBytes"`
+
+	// MaxReplays failed replays of a batch, while the sink accepts others, before it's moved to the rejected
+	// subdirectory. batches the sink rejects, eg: http 4xx answers, are moved right away. defaults to DefaultDeadLetterMaxReplays
+	MaxReplays int `toml:"maxReplays" json:"maxReplays" mapstructure:"maxReplays"`
+}
+
+type deadLetterSegment struct {
+	name     string
+	size     int64
+	failures int
+}
+
+// DeadLetterQueue bounded on-disk queue of undelivered batches, kept across restarts and replayed oldest first.
+// poison batches are moved aside to the rejected subdirectory, never replayed
+type DeadLetterQueue struct {
+	dir        string
+	maxBytes   int64
+	maxReplays int
+
+	mu       sync.Mutex
+	seq      uint64
+	size     int64
+	segments []deadLetterSegment
+
+	replayMu sync.Mutex
+}
+
+// NewDeadLetterQueue opens the directory of cfg, creating it when missing.
+// batches spilled by a previous process are queued for replay
+func NewDeadLetterQueue(cfg DeadLetterConfiguration) (*DeadLetterQueue, error) {
+	if cfg.Dir == "" {
+		return nil, fmt.Errorf("dead letter queue requires a dir")
+	}
+
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultDeadLetterMaxBytes
+	}
+
+	if cfg.MaxReplays <= 0 {
+		cfg.MaxReplays = DefaultDeadLetterMaxReplays
+	}
+
+	err := os.MkdirAll(cfg.Dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &DeadLetterQueue{dir: cfg.Dir, maxBytes: cfg.MaxBytes, maxReplays: cfg.MaxReplays}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), deadLetterExt) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		q.segments = append(q.segments, deadLetterSegment{name: entry.Name(), size: info.Size()})
+		q.size += info.Size()
+	}
+
+	// names start with the spill time, sorting them restores the spill order
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i].name < q.segments[j].name })
+	return q, nil
+}
+
+// openDeadLetter opens the queue of cfg, nil when dead lettering is disabled
+func openDeadLetter(cfg DeadLetterConfiguration) (*DeadLetterQueue, error) {
+	if cfg.Dir == "" {
+		return nil, nil
+	}
+
+	return NewDeadLetterQueue(cfg)
+}
+
+// Spill stores batch on disk, dropping the oldest batches past MaxBytes
+func (q *DeadLetterQueue) Spill(batch [][]byte) error {
+	content, err := q.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name, err := q.writeLocked(q.dir, content)
+	if err != nil {
+		return err
+	}
+
+	q.segments = append(q.segments, deadLetterSegment{name: name, size: int64(len(content))})
+	q.size += int64(len(content))
+
+	for q.size > q.maxBytes && len(q.segments) > 1 {
+		oldest := q.segments[0]
+		q.segments = q.segments[1:]
+		q.size -= oldest.size
+
+		_ = os.Remove(filepath.Join(q.dir, oldest.name))
+		reportDiagnostic(fmt.Errorf("dead letter: dropped %s, %d bytes over the %d bytes bound", oldest.name, oldest.size, q.maxBytes))
+	}
+
+	return nil
+}
+
+// Reject stores batch in the rejected subdirectory, where it's kept for inspection and never replayed
+func (q *DeadLetterQueue) Reject(batch [][]byte, cause error) error {
+	content, err := q.encode(batch)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name, err := q.writeLocked(q.rejectedDir(), content)
+	if err != nil {
+		return err
+	}
+
+	reportDiagnostic(fmt.Errorf("dead letter: rejected %s: %w", name, cause))
+	q.trimRejectedLocked()
+	return nil
+}
+
+// Replay hands the spilled batches to deliver, oldest first, removing the delivered ones.
+// it stops at the first batch deliver fails, unless that batch is moved to the rejected subdirectory
+func (q *DeadLetterQueue) Replay(deliver func(batch [][]byte) error) error {
+	return q.replay(deliver, true)
+}
+
+// replay is Replay, counting the failed replays towards MaxReplays only when the sink is known to be up
+func (q *DeadLetterQueue) replay(deliver func(batch [][]byte) error, up bool) error {
+	q.replayMu.Lock()
+	defer q.replayMu.Unlock()
+
+	for {
+		q.mu.Lock()
+		if len(q.segments) == 0 {
+			q.mu.Unlock()
+			return nil
+		}
+		segment := q.segments[0]
+		q.mu.Unlock()
+
+		path := filepath.Join(q.dir, segment.name)
+		content, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		// a missing file was dropped by a concurrent spill
+		if err == nil {
+			err = deliver(splitEntries(content))
+
+			var partial PartialDeliveryError
+			if errors.As(err, &partial) {
+				err = q.partlyDelivered(segment.name, partial)
+			}
+
+			if err != nil {
+				if !q.failed(segment.name, err, up) {
+					return err
+				}
+
+				// moved aside, it no longer holds back the batches behind it
+				continue
+			}
+			up = true
+		}
+
+		_ = os.Remove(path)
+		q.remove(segment.name)
+	}
+}
+
+// partlyDelivered moves the rejected entries of the segment name aside and keeps the undelivered ones,
+// returning the error of the undelivered entries
+func (q *DeadLetterQueue) partlyDelivered(name string, partial PartialDeliveryError) error {
+	if len(partial.Rejected) > 0 {
+		if err := q.Reject(partial.Rejected, partial.RejectedErr); err != nil {
+			reportDiagnostic(err)
+		}
+	}
+
+	if len(partial.Undelivered) == 0 {
+		return nil
+	}
+
+	content, err := q.encode(partial.Undelivered)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	path := filepath.Join(q.dir, name)
+	err = os.WriteFile(path+".tmp", content, 0o644)
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		_ = os.Remove(path + ".tmp")
+		return err
+	}
+
+	for i := range q.segments {
+		if q.segments[i].name == name {
+			q.size += int64(len(content)) - q.segments[i].size
+			q.segments[i].size = int64(len(content))
+		}
+	}
+
+	return partial.Err
+}
+
+// failed counts a failed replay of the segment name, moving it to the rejected subdirectory when cause
+// is a rejection, or it failed MaxReplays times. returns whether it was moved
+func (q *DeadLetterQueue) failed(name string, cause error, up bool) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i := range q.segments {
+		if q.segments[i].name != name {
+			continue
+		}
+
+		if up {
+			q.segments[i].failures++
+		}
+
+		if !deliveryRejected(cause) && q.segments[i].failures < q.maxReplays {
+			return false
+		}
+
+		segment := q.segments[i]
+		q.segments = append(q.segments[:i], q.segments[i+1:]...)
+		q.size -= segment.size
+
+		err := os.MkdirAll(q.rejectedDir(), 0o755)
+		if err == nil {
+			err = os.Rename(filepath.Join(q.dir, name), filepath.Join(q.rejectedDir(), name))
+		}
+		if err != nil {
+			_ = os.Remove(filepath.Join(q.dir, name))
+			reportDiagnostic(fmt.Errorf("dead letter: dropped %s after %d failed replays: %w", name, segment.failures, cause))
+			return true
+		}
+
+		reportDiagnostic(fmt.Errorf("dead letter: rejected %s after %d failed replays: %w", name, segment.failures, cause))
+		q.trimRejectedLocked()
+		return true
+	}
+
+	return false
+}
+
+// Pending returns the number of spilled batches waiting for replay
+func (q *DeadLetterQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.segments)
+}
+
+// Size returns the bytes spilled to the directory
+func (q *DeadLetterQueue) Size() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.size
+}
+
+func (q *DeadLetterQueue) remove(name string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, segment := range q.segments {
+		if segment.name == name {
+			q.segments = append(q.segments[:i], q.segments[i+1:]...)
+			q.size -= segment.size
+			return
+		}
+	}
+}
+
+// encode joins the batch entries, newline delimited, refusing batches over MaxBytes
+func (q *DeadLetterQueue) encode(batch [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, entry := range batch {
+		buf.Write(entry)
+		if !bytes.HasSuffix(entry, []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+	}
+
+	if int64(buf.Len()) > q.maxBytes {
+		return nil, fmt.Errorf("dead letter: batch of %d bytes exceeds the %d bytes bound", buf.Len(), q.maxBytes)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeLocked writes content to a new segment file of dir, returning its name
+func (q *DeadLetterQueue) writeLocked(dir string, content []byte) (string, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	q.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), q.seq%1000000, deadLetterExt)
+
+	// written aside and renamed, a crash never leaves a partial batch to replay
+	tmp := filepath.Join(dir, name+".tmp")
+	err = os.WriteFile(tmp, content, 0o644)
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+
+	err = os.Rename(tmp, filepath.Join(dir, name))
+	if err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
+
+	return name, nil
+}
+
+func (q *DeadLetterQueue) rejectedDir() string {
+	return filepath.Join(q.dir, deadLetterRejectedDir)
+}
+
+// trimRejectedLocked drops the oldest rejected batches past MaxBytes
+func (q *DeadLetterQueue) trimRejectedLocked() {
+	entries, err := os.ReadDir(q.rejectedDir())
+	if err != nil {
+		return
+	}
+
+	var size int64
+	sizes := make([]int64, len(entries))
+	for i, entry := range entries {
+		if info, err := entry.Info(); err == nil {
+			sizes[i] = info.Size()
+			size += sizes[i]
+		}
+	}
+
+	// ReadDir sorts by name, the spill order
+	for i := 0; size > q.maxBytes && i < len(entries)-1; i++ {
+		_ = os.Remove(filepath.Join(q.rejectedDir(), entries[i].Name()))
+		size -= sizes[i]
+	}
+}
+
+// PartialDeliveryError batch the sink accepted in part, eg: a bulk request failing some of its documents.
+// dead letter queues move the Rejected entries aside and spill or keep the Undelivered ones only
+type PartialDeliveryError struct {
+	// Rejected entries the sink refused, failing with RejectedErr
+	Rejected    [][]byte
+	RejectedErr error
+
+	// Undelivered entries to deliver again, failing with Err
+	Undelivered [][]byte
+	Err         error
+}
+
+// Error returns the rejection and delivery failures
+func (e PartialDeliveryError) Error() string {
+	return fmt.Sprintf("%d entries rejected, %d undelivered: %v", len(e.Rejected), len(e.Undelivered), errors.Join(e.RejectedErr, e.Err))
+}
+
+// Unwrap returns the rejection and delivery failures
+func (e PartialDeliveryError) Unwrap() []error {
+	var errs []error
+	for _, err := range []error{e.RejectedErr, e.Err} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// deliveryRejected reports whether the sink rejected the batch itself, eg: http 4xx answers or errors
+// with a true Permanent method, so delivering it again would fail the same way
+func deliveryRejected(err error) bool {
+	var status HTTPStatusError
+	if errors.As(err, &status) {
+		return !httpRetryable(status)
+	}
+
+	var permanent interface{ Permanent() bool }
+	return errors.As(err, &permanent) && permanent.Permanent()
+}
+
+// splitEntries splits newline delimited entries, keeping the trailing newlines
+func splitEntries(content []byte) [][]byte {
+	var entries [][]byte
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, '\n')
+		if i < 0 {
+			entries = append(entries, content)
+			break
+		}
+
+		entries = append(entries, content[:i+1])
+		content = content[i+1:]
+	}
+
+	return entries
+}
@@ -0,0 +1,234 @@
+package logger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	dir := t.TempDir()
+	q, err := NewDeadLetterQueue(DeadLetterConfiguration{Dir: dir})
+	assert.Nil(t, err)
+
+	assert.Nil(t, q.Spill([][]byte{[]byte("a\n"), []byte("b")}))
+	assert.Nil(t, q.Spill([][]byte{[]byte("c\n")}))
+	assert.Equal(t, 2, q.Pending())
+	assert.Equal(t, int64(6), q.Size())
+
+	// reopened queues pick up the batches of a previous process
+	q, err = NewDeadLetterQueue(DeadLetterConfiguration{Dir: dir})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, q.Pending())
+
+	var replayed [][]string
+	down := errors.New("down")
+	err = q.Replay(func(batch [][]byte) error {
+		if len(replayed) == 1 {
+			return down
+		}
+		var entries []string
+		for _, entry := range batch {
+			entries = append(entries, string(entry))
+		}
+		replayed = append(replayed, entries)
+		return nil
+	})
+	assert.ErrorIs(t, err, down)
+	assert.Equal(t, [][]string{{"a\n", "b\n"}}, replayed)
+	assert.Equal(t, 1, q.Pending())
+
+	assert.Nil(t, q.Replay(func(batch [][]byte) error { return nil }))
+	assert.Equal(t, 0, q.Pending())
+	assert.Equal(t, int64(0), q.Size())
+}
+
+func TestDeadLetterQueueBound(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	var reported []error
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	q, err := NewDeadLetterQueue(DeadLetterConfiguration{Dir: t.TempDir(), MaxBytes: 10})
+	assert.Nil(t, err)
+
+	assert.Nil(t, q.Spill([][]byte{[]byte("first\n")}))
+	assert.Nil(t, q.Spill([][]byte{[]byte("second\n")}))
+	assert.Equal(t, 1, q.Pending())
+	assert.Len(t, reported, 1)
+	assert.ErrorContains(t, q.Spill([][]byte{[]byte("too large entry\n")}), "exceeds")
+
+	var replayed string
+	assert.Nil(t, q.Replay(func(batch [][]byte) error {
+		replayed = string(batch[0])
+		return nil
+	}))
+	assert.Equal(t, "second\n", replayed)
+}
+
+func TestDeadLetterQueuePoisonBatch(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	var reported []error
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	dir := t.TempDir()
+	q, err := NewDeadLetterQueue(DeadLetterConfiguration{Dir: dir, MaxReplays: 2})
+	assert.Nil(t, err)
+
+	assert.Nil(t, q.Spill([][]byte{[]byte("poison\n")}))
+	assert.Nil(t, q.Spill([][]byte{[]byte("fine\n")}))
+
+	var replayed []string
+	deliver := func(batch [][]byte) error {
+		if string(batch[0]) == "poison\n" {
+			return errors.New("internal error")
+		}
+		replayed = append(replayed, string(batch[0]))
+		return nil
+	}
+
+	// the sink being down, failed probes don't count
+	for i := 0; i < 3; i++ {
+		assert.NotNil(t, q.replay(deliver, false))
+	}
+	assert.Equal(t, 2, q.Pending())
+
+	assert.NotNil(t, q.Replay(deliver))
+	assert.Nil(t, q.Replay(deliver), "moved aside after MaxReplays, the batches behind it are replayed")
+	assert.Equal(t, []string{"fine\n"}, replayed)
+	assert.Equal(t, 0, q.Pending())
+	assert.Len(t, reported, 1)
+
+	rejected, _ := filepath.Glob(filepath.Join(dir, "rejected", "*"+deadLetterExt))
+	assert.Len(t, rejected, 1)
+	content, _ := os.ReadFile(rejected[0])
+	assert.Equal(t, "poison\n", string(content))
+
+	// rejections are never retried
+	assert.Nil(t, q.Spill([][]byte{[]byte("rejected\n")}))
+	assert.Nil(t, q.Replay(func(batch [][]byte) error {
+		return HTTPStatusError{URL: "http://sink", StatusCode: http.StatusBadRequest}
+	}))
+	assert.Equal(t, 0, q.Pending())
+	rejected, _ = filepath.Glob(filepath.Join(dir, "rejected", "*"+deadLetterExt))
+	assert.Len(t, rejected, 2)
+}
+
+func TestDeadLetterQueuePartialReplay(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = nil
+
+	dir := t.TempDir()
+	q, err := NewDeadLetterQueue(DeadLetterConfiguration{Dir: dir})
+	assert.Nil(t, err)
+	assert.Nil(t, q.Spill([][]byte{[]byte("a\n"), []byte("b\n"), []byte("c\n")}))
+
+	err = q.Replay(func(batch [][]byte) error {
+		return PartialDeliveryError{
+			Rejected:    batch[1:2],
+			RejectedErr: ElasticsearchBulkError{Rejected: 1, Reason: "mapping"},
+			Undelivered: batch[2:],
+			Err:         ElasticsearchThrottledError{Throttled: 1},
+		}
+	})
+	assert.ErrorAs(t, err, &ElasticsearchThrottledError{})
+	assert.Equal(t, 1, q.Pending())
+	assert.Equal(t, int64(2), q.Size())
+
+	rejected, _ := filepath.Glob(filepath.Join(dir, "rejected", "*"+deadLetterExt))
+	assert.Len(t, rejected, 1)
+
+	var replayed [][]byte
+	assert.Nil(t, q.Replay(func(batch [][]byte) error {
+		replayed = batch
+		return nil
+	}))
+	assert.Equal(t, [][]byte{[]byte("c\n")}, replayed, "delivered entries are not replayed")
+}
+
+func TestWebhookWriterRejectedBatch(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	var reported []error
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	w, err := NewWebhookWriter(WebhookWriterConfiguration{HTTPSinkConfiguration: HTTPSinkConfiguration{
+		URL:          server.URL,
+		RetryBackoff: time.Millisecond,
+		DeadLetter:   DeadLetterConfiguration{Dir: dir},
+	}})
+	assert.Nil(t, err)
+
+	_, _ = w.Write([]byte(`{"message":"malformed"}` + "\n"))
+	assert.Nil(t, w.Close())
+
+	assert.Equal(t, int32(1), requests.Load(), "rejected batches are neither retried nor replayed")
+	assert.Equal(t, 0, w.batch.deadLetter.Pending())
+	assert.Len(t, reported, 1)
+
+	rejected, _ := filepath.Glob(filepath.Join(dir, "rejected", "*"+deadLetterExt))
+	assert.Len(t, rejected, 1)
+}
+
+func TestWebhookWriterDeadLetter(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = nil
+
+	var (
+		mu     sync.Mutex
+		down   = true
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if down {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	w, err := NewWebhookWriter(WebhookWriterConfiguration{HTTPSinkConfiguration: HTTPSinkConfiguration{
+		URL:          server.URL,
+		RetryBackoff: time.Millisecond,
+		DeadLetter:   DeadLetterConfiguration{Dir: t.TempDir()},
+	}})
+	assert.Nil(t, err)
+
+	_, _ = w.Write([]byte(`{"message":"lost"}` + "\n"))
+	assert.Nil(t, w.Flush())
+	assert.Equal(t, 1, w.batch.deadLetter.Pending())
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+
+	_, _ = w.Write([]byte(`{"message":"new"}` + "\n"))
+	assert.Nil(t, w.Close())
+	assert.Equal(t, 0, w.batch.deadLetter.Pending())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, bodies, 2)
+	assert.True(t, strings.Contains(bodies[0], "new"))
+	assert.True(t, strings.Contains(bodies[1], "lost"))
+}
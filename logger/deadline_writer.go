@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"net"
+	"time"
+)
+
+// DefaultWriteTimeout default per write timeout for network sinks
+const DefaultWriteTimeout = 5 * time.Second
+
+// DeadlineWriter sets a write deadline on the connection before every write, so a
+// stalled remote endpoint fails the write instead of blocking it forever
+type DeadlineWriter struct {
+	conn    net.Conn
+	timeout time.Duration
+}
+
+// NewDeadlineWriter returns a deadline writer, timeout defaults to DefaultWriteTimeout
+func NewDeadlineWriter(conn net.Conn, timeout time.Duration) *DeadlineWriter {
+	if timeout <= 0 {
+		timeout = DefaultWriteTimeout
+	}
+
+	return &DeadlineWriter{conn: conn, timeout: timeout}
+}
+
+// Write writes p to the connection within the configured timeout
+func (w *DeadlineWriter) Write(p []byte) (int, error) {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.timeout)); err != nil {
+		return 0, err
+	}
+
+	return w.conn.Write(p)
+}
+
+// Close closes the connection
+func (w *DeadlineWriter) Close() error {
+	return w.conn.Close()
+}
@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDeadlineWriter(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	writer := NewDeadlineWriter(client, 50*time.Millisecond)
+	defer writer.Close()
+
+	// nobody reads from the pipe, the write must not block forever
+	start := time.Now()
+	_, err := writer.Write([]byte("stalled\n"))
+	assert.True(t, errors.Is(err, os.ErrDeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"os"
+	"time"
+)
+
+// process identification, captured once at startup
+var (
+	hostname = func() string {
+		host, _ := os.Hostname()
+		return host
+	}()
+	pid = os.Getpid()
+)
+
+// decorate adds the optional per entry fields enabled in the logger
+func (i *JsonLogger) decorate(logEntry map[string]any, now time.Time) {
+	if i.SchemaVersion != "" {
+		i.put(logEntry, VersionField, i.SchemaVersion)
+	}
+
+	if i.EntryID {
+		i.put(logEntry, EntryIDField, NewEntryID(now))
+	}
+
+	if i.Sequence && i.sequence != nil {
+		i.put(logEntry, SequenceField, i.sequence.Add(1))
+	}
+
+	if i.HostFields {
+		i.put(logEntry, HostField, hostname)
+		i.put(logEntry, PIDField, pid)
+	}
+}
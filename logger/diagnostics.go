@@ -0,0 +1,19 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+)
+
+// DiagnosticsHandler receives problems the logger finds in its own usage, eg: schema violations.
+// defaults to writing them to stderr, set to nil to discard them
+var DiagnosticsHandler = func(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "logger diagnostics: %v\n", err)
+}
+
+// reportDiagnostic forwards err to the DiagnosticsHandler, when set
+func reportDiagnostic(err error) {
+	if handler := DiagnosticsHandler; handler != nil {
+		handler(err)
+	}
+}
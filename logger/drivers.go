@@ -2,4 +2,78 @@ package logger
 
 const (
 	JSONLoggerDriver = "json_logger_driver"
+	FileLoggerDriver = "file_logger_driver"
+	SQLLoggerDriver  = "sql_logger_driver"
+
+	// NullLoggerDriver discards every entry, eg: to disable logging through configuration or to benchmark
+	NullLoggerDriver = "null_logger_driver"
+
+	// SyslogLoggerDriver syslog over udp, tcp or unix sockets, see SyslogWriterConfiguration
+	SyslogLoggerDriver = "syslog_logger_driver"
+
+	// KafkaLoggerDriver kafka topic, through an injected KafkaProducer
+	KafkaLoggerDriver = "kafka_logger_driver"
+
+	// NATSLoggerDriver nats subject, optionally through JetStream, with an injected connection
+	NATSLoggerDriver = "nats_logger_driver"
+
+	// CloudWatchLoggerDriver aws CloudWatch Logs, through an injected CloudWatchLogsClient
+	CloudWatchLoggerDriver = "cloudwatch_logger_driver"
+
+	// KinesisLoggerDriver aws kinesis data stream or firehose delivery stream, through an injected KinesisClient
+	KinesisLoggerDriver = "kinesis_logger_driver"
+
+	// LokiLoggerDriver grafana loki push api, see LokiWriterConfiguration
+	LokiLoggerDriver = "loki_logger_driver"
+
+	// ElasticsearchLoggerDriver elasticsearch or opensearch _bulk api, see ElasticsearchWriterConfiguration
+	ElasticsearchLoggerDriver = "elasticsearch_logger_driver"
+
+	// GELFLoggerDriver graylog gelf over udp or tcp, see GELFWriterConfiguration
+	GELFLoggerDriver = "gelf_logger_driver"
+
+	// DatadogLoggerDriver datadog logs intake api, see DatadogWriterConfiguration
+	DatadogLoggerDriver = "datadog_logger_driver"
+
+	// SentryLoggerDriver stdout, with ERROR entries also forwarded to sentry, see SentryLoggerConfiguration
+	SentryLoggerDriver = "sentry_logger_driver"
+
+	// OTLPLoggerDriver opentelemetry collector, over otlp/http json or an injected OTLPLogsClient
+	OTLPLoggerDriver = "otlp_logger_driver"
+
+	// NetLoggerDriver tcp:// or udp:// endpoint with reconnect and buffering, see NetWriterConfiguration
+	NetLoggerDriver = "net_logger_driver"
+
+	// WebhookLoggerDriver ndjson or json array batches posted to an http endpoint, see WebhookWriterConfiguration
+	WebhookLoggerDriver = "webhook_logger_driver"
+
+	// RedisLoggerDriver redis stream trimmed to a max length, through an injected RedisStreamClient
+	RedisLoggerDriver = "redis_logger_driver"
+
+	// SQSLoggerDriver aws sqs queue, through an injected SQSClient
+	SQSLoggerDriver = "sqs_logger_driver"
+
+	// SeqLoggerDriver seq raw ingestion of compact log event format events
+	SeqLoggerDriver = "seq_logger_driver"
+
+	// NewRelicLoggerDriver new relic log api, with service.name and hostname attributes
+	NewRelicLoggerDriver = "newrelic_logger_driver"
+
+	// MQTTLoggerDriver mqtt topic, through an injected MQTTPublisher
+	MQTTLoggerDriver = "mqtt_logger_driver"
+
+	// LogfmtLoggerDriver logfmt lines, to stdout unless a Writer is configured
+	LogfmtLoggerDriver = "logfmt_logger_driver"
+
+	// MultiLoggerDriver several destinations, each one with its own level, see MultiLoggerConfiguration
+	MultiLoggerDriver = "multi_logger_driver"
+
+	// FailoverLoggerDriver destinations tried in order until one accepts the entry, see FailoverLoggerConfiguration
+	FailoverLoggerDriver = "failover_logger_driver"
+
+	// JournaldLoggerDriver systemd-journald native entries, only registered on linux builds
+	JournaldLoggerDriver = "journald_logger_driver"
+
+	// EventLogLoggerDriver windows event log, only registered on windows builds
+	EventLogLoggerDriver = "eventlog_logger_driver"
 )
@@ -0,0 +1,58 @@
+package logger
+
+import "strconv"
+
+// DuplicateKeyPolicy how a field key set more than once is resolved
+type DuplicateKeyPolicy string
+
+// supported duplicate key policies
+const (
+	// LastKeyWins default, a later value replaces the earlier one. the logger own fields
+	// (caller, level, message, ...) replace user fields with the same key
+	LastKeyWins DuplicateKeyPolicy = "last"
+
+	// FirstKeyWins the first value is kept, user fields shadow the logger own fields with the same key
+	FirstKeyWins DuplicateKeyPolicy = "first"
+
+	// SuffixDuplicateKeys every value is kept, later values under key_1, key_2, ...
+	// user fields colliding with the logger own fields are the ones renamed
+	SuffixDuplicateKeys DuplicateKeyPolicy = "suffix"
+)
+
+// putField sets a user field, resolving an existing key with the DuplicateKeys policy
+func (i *JsonLogger) putField(fields map[string]any, key string, value any) {
+	if _, exists := fields[key]; exists {
+		switch i.DuplicateKeys {
+		case FirstKeyWins:
+			return
+		case SuffixDuplicateKeys:
+			key = freeKey(fields, key)
+		}
+	}
+
+	fields[key] = value
+}
+
+// put sets a logger own field on the entry, resolving a user field with the same key with the DuplicateKeys policy
+func (i *JsonLogger) put(logEntry map[string]any, key string, value any) {
+	if existing, exists := logEntry[key]; exists {
+		switch i.DuplicateKeys {
+		case FirstKeyWins:
+			return
+		case SuffixDuplicateKeys:
+			logEntry[freeKey(logEntry, key)] = existing
+		}
+	}
+
+	logEntry[key] = value
+}
+
+// freeKey returns the first key_N not in fields
+func freeKey(fields map[string]any, key string) string {
+	for n := 1; ; n++ {
+		suffixed := key + "_" + strconv.Itoa(n)
+		if _, exists := fields[suffixed]; !exists {
+			return suffixed
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultElasticsearchIndex index pattern used when none is configured
+const DefaultElasticsearchIndex = "logs-%{app}-%{yyyy.MM.dd}"
+
+// ECSVersion version of the elastic common schema the ECS mapping follows
+const ECSVersion = "8.11"
+
+// ElasticsearchWriterConfiguration elasticsearch and opensearch sink configuration, URL is the cluster address
+type ElasticsearchWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	// Index pattern of the target index, %{field} is replaced by the entry field, eg: %{ctx.tenant},
+	// %{yyyy.MM.dd} by the entry date in utc. defaults to DefaultElasticsearchIndex
+	Index string `toml:"index" json:"index" mapstructure:"index"`
+
+	Username string `toml:"username" json:"username" mapstructure:"username"`
+	Password string `toml:"password" json:"password" mapstructure:"password"`
+	APIKey   string `toml:"apiKey" json:"apiKey" mapstructure:"apiKey"`
+
+	// ECS maps the entry fields to the elastic common schema, eg: timestamp to @timestamp and level to log.level
+	ECS bool `toml:"ecs" json:"ecs" mapstructure:"ecs"`
+}
+
+// ElasticsearchLoggerConfiguration json logger indexing into elasticsearch
+type ElasticsearchLoggerConfiguration struct {
+	JSONLoggerConfiguration          `mapstructure:",squash"`
+	ElasticsearchWriterConfiguration `mapstructure:",squash"`
+}
+
+// ElasticsearchBulkError documents the cluster rejected with a 4xx status other than 429, never resent
+type ElasticsearchBulkError struct {
+	Rejected int
+	Reason   string
+}
+
+// Error returns the rejection description
+func (e ElasticsearchBulkError) Error() string {
+	return fmt.Sprintf("elasticsearch: %d documents rejected: %s", e.Rejected, e.Reason)
+}
+
+// Permanent marks the rejected documents as not worth delivering again
+func (e ElasticsearchBulkError) Permanent() bool {
+	return true
+}
+
+// ElasticsearchThrottledError documents the cluster failed with 429 or a 5xx status, resent with backoff
+type ElasticsearchThrottledError struct {
+	Throttled int
+}
+
+// Error returns the throttling description
+func (e ElasticsearchThrottledError) Error() string {
+	return fmt.Sprintf("elasticsearch: %d documents throttled", e.Throttled)
+}
+
+// ElasticsearchWriter batches entries into _bulk requests. documents throttled with 429 or 5xx are resent with backoff,
+// and writers block while the batch is resent once full. documents failing for good are reported apart from
+// the undelivered ones, through a PartialDeliveryError, so dead letter queues never resend indexed documents
+type ElasticsearchWriter struct {
+	cfg    ElasticsearchWriterConfiguration
+	sender *httpSender
+	header http.Header
+	url    string
+	batch  *batcher
+}
+
+type elasticsearchDocument struct {
+	entry []byte
+	index string
+	body  []byte
+
+	// reason of the rejection, for rejected documents
+	reason string
+}
+
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// NewElasticsearchWriter returns a writer indexing into the cluster at cfg.URL
+func NewElasticsearchWriter(cfg ElasticsearchWriterConfiguration) (*ElasticsearchWriter, error) {
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.HTTPSinkConfiguration = sender.cfg
+	if cfg.Index == "" {
+		cfg.Index = DefaultElasticsearchIndex
+	}
+
+	header := http.Header{"Content-Type": {"application/x-ndjson"}}
+	switch {
+	case cfg.APIKey != "":
+		header.Set("Authorization", "ApiKey "+cfg.APIKey)
+	case cfg.Username != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(cfg.Username+":"+cfg.Password)))
+	}
+
+	w := &ElasticsearchWriter{
+		cfg:    cfg,
+		sender: sender,
+		header: header,
+		url:    strings.TrimRight(cfg.URL, "/") + "/_bulk",
+	}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.index)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next bulk request
+func (w *ElasticsearchWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush indexes the pending entries
+func (w *ElasticsearchWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close indexes the pending entries
+func (w *ElasticsearchWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *ElasticsearchWriter) index(batch [][]byte) error {
+	now := time.Now()
+	documents := make([]elasticsearchDocument, 0, len(batch))
+	for _, entry := range batch {
+		document, err := w.document(entry, now)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+		documents = append(documents, document)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout*time.Duration(w.cfg.MaxRetries+1))
+	defer cancel()
+
+	var throttled ElasticsearchThrottledError
+	resend := func(err error) bool {
+		return errors.As(err, &throttled)
+	}
+
+	var (
+		rejected []elasticsearchDocument
+		answered bool
+	)
+	err := retry(ctx, w.cfg.MaxRetries, w.cfg.RetryBackoff, resend, func() error {
+		pending, failed, err := w.bulk(documents)
+		if err != nil && !resend(err) {
+			// the whole request failed, documents are left as they are
+			return err
+		}
+
+		answered = true
+		documents = pending
+		rejected = append(rejected, failed...)
+		return err
+	})
+
+	// nothing was indexed, the batch failed as a whole
+	if !answered {
+		return err
+	}
+
+	return partialDelivery(rejected, documents, err)
+}
+
+// partialDelivery error of the documents rejected for good and the undelivered ones, nil when there are none
+func partialDelivery(rejected []elasticsearchDocument, undelivered []elasticsearchDocument, err error) error {
+	if len(rejected) == 0 && len(undelivered) == 0 {
+		return nil
+	}
+
+	partial := PartialDeliveryError{Err: err}
+	for _, document := range undelivered {
+		partial.Undelivered = append(partial.Undelivered, document.entry)
+	}
+
+	if len(rejected) > 0 {
+		for _, document := range rejected {
+			partial.Rejected = append(partial.Rejected, document.entry)
+		}
+		partial.RejectedErr = ElasticsearchBulkError{Rejected: len(rejected), Reason: rejected[len(rejected)-1].reason}
+	}
+
+	return partial
+}
+
+// bulk sends documents, returning the ones to resend, failed with 429 or 5xx, and the rejected ones
+func (w *ElasticsearchWriter) bulk(documents []elasticsearchDocument) ([]elasticsearchDocument, []elasticsearchDocument, error) {
+	var body bytes.Buffer
+	for _, document := range documents {
+		action, _ := json.Marshal(map[string]any{"create": map[string]string{"_index": document.index}})
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(document.body)
+		body.WriteByte('\n')
+	}
+
+	response, err := w.sender.send(w.url, w.header, body.Bytes())
+	if err != nil {
+		return documents, nil, err
+	}
+
+	var result elasticsearchBulkResponse
+	if err := json.Unmarshal(response, &result); err != nil || !result.Errors {
+		return nil, nil, err
+	}
+
+	var pending, rejected []elasticsearchDocument
+	for i, item := range result.Items {
+		if i >= len(documents) {
+			break
+		}
+
+		for _, status := range item {
+			switch {
+			case status.Status == http.StatusTooManyRequests || status.Status >= 500:
+				pending = append(pending, documents[i])
+			case status.Status >= 300:
+				document := documents[i]
+				document.reason = status.Error.Reason
+				rejected = append(rejected, document)
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		return pending, rejected, ElasticsearchThrottledError{Throttled: len(pending)}
+	}
+
+	return nil, rejected, nil
+}
+
+// document resolves the index of the entry and its body, mapped to ecs when configured
+func (w *ElasticsearchWriter) document(entry []byte, now time.Time) (elasticsearchDocument, error) {
+	fields, err := decodeEntry(entry)
+	if err != nil {
+		return elasticsearchDocument{}, err
+	}
+
+	index := elasticsearchIndex(w.cfg.Index, fields, entryTime(fields, now).UTC())
+	if !w.cfg.ECS {
+		return elasticsearchDocument{entry: entry, index: index, body: bytes.TrimRight(entry, "\n")}, nil
+	}
+
+	body, err := json.Marshal(ecsFields(fields))
+	return elasticsearchDocument{entry: entry, index: index, body: body}, err
+}
+
+// elasticsearchIndex expands the %{field} and %{date format} references of pattern, lowercased as indices require
+func elasticsearchIndex(pattern string, fields map[string]any, t time.Time) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(pattern, "%{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(pattern[start:], "}")
+		if end < 0 {
+			break
+		}
+
+		b.WriteString(pattern[:start])
+		reference := strings.TrimPrefix(pattern[start+2:start+end], "+")
+		if value, ok := lookupField(fields, reference); ok {
+			b.WriteString(fieldString(value))
+		} else if layout, ok := jodaLayout(reference); ok {
+			b.WriteString(t.Format(layout))
+		}
+
+		pattern = pattern[start+end+1:]
+	}
+
+	b.WriteString(pattern)
+	return strings.ToLower(b.String())
+}
+
+// jodaLayout converts a yyyy.MM.dd style date format to a go layout
+func jodaLayout(format string) (string, bool) {
+	replacer := strings.NewReplacer("yyyy", "2006", "yy", "06", "MM", "01", "dd", "02", "HH", "15", "mm", "04", "ss", "05")
+	layout := replacer.Replace(format)
+	if strings.ContainsAny(layout, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return "", false
+	}
+	return layout, true
+}
+
+// ecsFields maps the logger fields to their elastic common schema names, others are kept as is
+func ecsFields(fields map[string]any) map[string]any {
+	renames := map[string]string{
+		"timestamp":  "@timestamp",
+		"level":      "log.level",
+		"scope":      "log.logger",
+		"app":        "service.name",
+		EntryIDField: "event.id",
+		HostField:    "host.name",
+		PIDField:     "process.pid",
+	}
+
+	mapped := make(map[string]any, len(fields)+1)
+	for key, value := range fields {
+		name, ok := renames[key]
+		if !ok {
+			mapped[key] = value
+			continue
+		}
+
+		if key == "level" {
+			value = strings.ToLower(fieldString(value))
+		}
+		mapped[name] = value
+	}
+
+	if err, ok := fields[ErrorField].(string); ok {
+		mapped[ErrorField] = map[string]any{"message": err}
+	}
+
+	if caller, ok := lookupField(fields, CallerField+".Path"); ok {
+		delete(mapped, CallerField)
+		mapped["log.origin.function"] = caller
+	}
+
+	mapped["ecs.version"] = ECSVersion
+	return mapped
+}
+
+func createElasticsearchLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg ElasticsearchLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewElasticsearchWriter(cfg.ElasticsearchWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
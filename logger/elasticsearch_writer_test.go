@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestElasticsearchDriver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests [][]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "ApiKey key", r.Header.Get("Authorization"))
+
+		body, _ := io.ReadAll(r.Body)
+		var lines []string
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		requests = append(requests, lines)
+
+		if len(requests) == 1 {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"create":{"status":201}},{"create":{"status":429}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[{"create":{"status":201}}]}`))
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   ElasticsearchLoggerDriver,
+		Values: map[string]any{
+			"url":          server.URL,
+			"apiKey":       "key",
+			"ecs":          true,
+			"retryBackoff": time.Millisecond,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("first")
+	log.Log("second")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, requests, 2)
+	assert.Len(t, requests[0], 4)
+	assert.Equal(t, requests[0][2:], requests[1])
+
+	var action map[string]map[string]string
+	assert.Nil(t, json.Unmarshal([]byte(requests[0][0]), &action))
+	assert.Equal(t, "logs-app-"+time.Now().UTC().Format("2006.01.02"), action["create"]["_index"])
+
+	var document map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(requests[0][1]), &document))
+	assert.Equal(t, "first", document["message"])
+	assert.Equal(t, "log", document["log.level"])
+	assert.Equal(t, "App", document["service.name"])
+	assert.Equal(t, "Scope", document["log.logger"])
+	assert.Equal(t, ECSVersion, document["ecs.version"])
+	assert.NotContains(t, document, "timestamp")
+	assert.Contains(t, document, "@timestamp")
+}
+
+func TestElasticsearchIndex(t *testing.T) {
+	fields := map[string]any{"app": "Billing", "ctx": map[string]any{"tenant": "t1"}}
+	date := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "logs-billing-2024.03.09", elasticsearchIndex(DefaultElasticsearchIndex, fields, date))
+	assert.Equal(t, "t1-2024-03", elasticsearchIndex("%{ctx.tenant}-%{+yyyy-MM}", fields, date))
+	assert.Equal(t, "logs--", elasticsearchIndex("logs-%{missing}-", fields, date))
+}
+
+func TestElasticsearchRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"create":{"status":400,"error":{"reason":"mapping"}}}]}`))
+	}))
+	defer server.Close()
+
+	writer, err := NewElasticsearchWriter(ElasticsearchWriterConfiguration{
+		HTTPSinkConfiguration: HTTPSinkConfiguration{URL: server.URL},
+	})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte(`{"message":"a"}` + "\n"))
+	assert.Nil(t, err)
+	var rejected ElasticsearchBulkError
+	assert.ErrorAs(t, writer.Close(), &rejected)
+	assert.Equal(t, ElasticsearchBulkError{Rejected: 1, Reason: "mapping"}, rejected)
+	assert.True(t, deliveryRejected(rejected))
+}
+
+func TestElasticsearchPartialDelivery(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = nil
+
+	var (
+		mu      sync.Mutex
+		busy    = true
+		indexed []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		body, _ := io.ReadAll(r.Body)
+		lines := bytes.Split(bytes.TrimSpace(body), []byte("\n"))
+
+		var items []string
+		for i := 1; i < len(lines); i += 2 {
+			var document map[string]any
+			_ = json.Unmarshal(lines[i], &document)
+
+			message, _ := document["message"].(string)
+			switch {
+			case message == "invalid":
+				items = append(items, `{"create":{"status":400,"error":{"reason":"mapping"}}}`)
+			case message == "throttled" && busy:
+				items = append(items, `{"create":{"status":429}}`)
+			default:
+				indexed = append(indexed, message)
+				items = append(items, `{"create":{"status":201}}`)
+			}
+		}
+		_, _ = w.Write([]byte(`{"errors":true,"items":[` + strings.Join(items, ",") + `]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writer, err := NewElasticsearchWriter(ElasticsearchWriterConfiguration{
+		HTTPSinkConfiguration: HTTPSinkConfiguration{
+			URL:          server.URL,
+			MaxRetries:   1,
+			RetryBackoff: time.Millisecond,
+			DeadLetter:   DeadLetterConfiguration{Dir: dir},
+		},
+	})
+	assert.Nil(t, err)
+
+	for _, message := range []string{"indexed", "invalid", "throttled"} {
+		_, _ = writer.Write([]byte(`{"message":"` + message + `"}` + "\n"))
+	}
+	assert.Nil(t, writer.Flush())
+	assert.Equal(t, 1, writer.batch.deadLetter.Pending(), "only the throttled document is spilled")
+
+	rejected, _ := filepath.Glob(filepath.Join(dir, "rejected", "*"+deadLetterExt))
+	assert.Len(t, rejected, 1)
+
+	mu.Lock()
+	busy = false
+	mu.Unlock()
+
+	_, _ = writer.Write([]byte(`{"message":"recovered"}` + "\n"))
+	assert.Nil(t, writer.Close())
+	assert.Equal(t, 0, writer.batch.deadLetter.Pending())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"indexed", "recovered", "throttled"}, indexed, "indexed documents are never sent again")
+}
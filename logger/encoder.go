@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// Encoder turns an entry into the bytes written to the logger writer, without trailing newline
+type Encoder interface {
+	Encode(entry map[string]any) ([]byte, error)
+}
+
+// AppendEncoder implemented by encoders able to append the entry to a reused buffer, saving a copy
+type AppendEncoder interface {
+	AppendEncode(dst []byte, entry map[string]any) ([]byte, error)
+}
+
+// EncoderFunc adapts a function into an Encoder
+type EncoderFunc func(entry map[string]any) ([]byte, error)
+
+// Encode calls f
+func (f EncoderFunc) Encode(entry map[string]any) ([]byte, error) {
+	return f(entry)
+}
+
+// JSONEncoder default encoder, one json object per entry
+type JSONEncoder struct {
+	// Backend json implementation, the build default when empty
+	Backend JSONBackend
+}
+
+// NewJSONEncoder returns a json encoder using backend, failing for unknown backends
+func NewJSONEncoder(backend JSONBackend) (JSONEncoder, error) {
+	if _, err := jsonBackend(backend); err != nil {
+		return JSONEncoder{}, err
+	}
+
+	return JSONEncoder{Backend: backend}, nil
+}
+
+// Encode marshals the entry as json
+func (e JSONEncoder) Encode(entry map[string]any) ([]byte, error) {
+	marshal, err := jsonBackend(e.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshal(entry)
+}
+
+// AppendEncode appends the json entry to dst, encoding in place with the stdlib backend
+func (e JSONEncoder) AppendEncode(dst []byte, entry map[string]any) ([]byte, error) {
+	backend := e.Backend
+	if backend == "" {
+		backend = defaultJSONBackend
+	}
+
+	if backend != StdlibJSONBackend {
+		data, err := e.Encode(entry)
+		return append(dst, data...), err
+	}
+
+	state := jsonEncoderPool.Get().(*jsonEncoderState)
+	defer putJSONEncoderState(state)
+
+	if err := state.enc.Encode(entry); err != nil {
+		return dst, err
+	}
+
+	// json.Encoder terminates the value with a newline
+	return append(dst, bytes.TrimSuffix(state.buf.Bytes(), []byte("\n"))...), nil
+}
+
+// jsonEncoderState json.Encoder reused across entries with the buffer it writes to
+type jsonEncoderState struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+var jsonEncoderPool = sync.Pool{
+	New: func() any {
+		state := new(jsonEncoderState)
+		state.enc = json.NewEncoder(&state.buf)
+		return state
+	},
+}
+
+func putJSONEncoderState(state *jsonEncoderState) {
+	if state.buf.Cap() > maxPooledLine {
+		return
+	}
+
+	state.buf.Reset()
+	jsonEncoderPool.Put(state)
+}
+
+// appendEncode appends the entry encoded with the logger encoder, JSONEncoder when none is set, to dst
+func (i *JsonLogger) appendEncode(dst []byte, entry map[string]any) ([]byte, error) {
+	var encoder Encoder = JSONEncoder{}
+	if i.Encoder != nil {
+		encoder = i.Encoder
+	}
+
+	if ae, ok := encoder.(AppendEncoder); ok {
+		return ae.AppendEncode(dst, entry)
+	}
+
+	data, err := encoder.Encode(entry)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, data...), nil
+}
+
+// jsonEncoded check if entries are encoded as json objects
+func (i *JsonLogger) jsonEncoded() bool {
+	switch i.Encoder.(type) {
+	case nil, JSONEncoder:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// decodeEntry decodes a json encoded entry, numbers are kept as json.Number
+func decodeEntry(entry []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(bytes.TrimRight(entry, "\n")))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// lookupField returns the value under the dotted path of fields, eg: ctx.trace_id
+func lookupField(fields map[string]any, path string) (any, bool) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := fields[key].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		fields = nested
+	}
+
+	value, ok := fields[keys[len(keys)-1]]
+	return value, ok
+}
+
+// removeField deletes the value under the dotted path of fields, dropping the groups left empty
+func removeField(fields map[string]any, path string) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		delete(fields, key)
+		return
+	}
+
+	group, ok := fields[key].(map[string]any)
+	if !ok {
+		return
+	}
+
+	removeField(group, rest)
+	if len(group) == 0 {
+		delete(fields, key)
+	}
+}
+
+// fieldString returns value as text, empty for nil
+func fieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// entryTime returns the timestamp field of fields, or fallback when missing or unparsable
+func entryTime(fields map[string]any, fallback time.Time) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, fieldString(fields["timestamp"]))
+	if err != nil {
+		return fallback
+	}
+	return t
+}
+
+// entryField returns the value under the dotted path of a json encoded entry, eg: ctx.trace_id.
+// empty when the entry isn't json or the field is missing
+func entryField(entry []byte, path string) string {
+	if path == "" {
+		return ""
+	}
+
+	fields, err := decodeEntry(entry)
+	if err != nil {
+		return ""
+	}
+
+	value, _ := lookupField(fields, path)
+	return fieldString(value)
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"strconv"
+	"sync"
+)
+
+// EntrySize encoded size of a single entry, newline included
+type EntrySize struct {
+	App    string
+	Scope  string
+	Caller string
+	Level  LogLevelEnum
+	Bytes  int
+}
+
+// SizeRecorder receives the size of every written entry, eg: to export it as a metric
+type SizeRecorder func(EntrySize)
+
+// SizeKey log volume attribution key
+type SizeKey struct {
+	App    string
+	Scope  string
+	Caller string
+}
+
+// SizeTotal accumulated log volume
+type SizeTotal struct {
+	Entries int64
+	Bytes   int64
+}
+
+// SizeStats aggregates entry sizes per app, scope and call site. Record is a SizeRecorder
+type SizeStats struct {
+	mu     sync.Mutex
+	totals map[SizeKey]SizeTotal
+}
+
+// Record accounts a written entry
+func (s *SizeStats) Record(size EntrySize) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.totals == nil {
+		s.totals = make(map[SizeKey]SizeTotal)
+	}
+
+	key := SizeKey{App: size.App, Scope: size.Scope, Caller: size.Caller}
+	total := s.totals[key]
+	total.Entries++
+	total.Bytes += int64(size.Bytes)
+	s.totals[key] = total
+}
+
+// Totals returns a copy of the accumulated volume
+func (s *SizeStats) Totals() map[SizeKey]SizeTotal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	totals := make(map[SizeKey]SizeTotal, len(s.totals))
+	for k, v := range s.totals {
+		totals[k] = v
+	}
+	return totals
+}
+
+// account appends the entry_bytes field to json objects and reports the entry size, when enabled
+func (i *JsonLogger) account(jsonLog []byte, level LogLevelEnum, call any) []byte {
+	if !i.SizeField && i.SizeRecorder == nil {
+		return jsonLog
+	}
+
+	size := len(jsonLog) + 1
+	if i.SizeField && i.jsonEncoded() && len(jsonLog) > 2 && jsonLog[len(jsonLog)-1] == '}' {
+		prefix := `,"` + EntrySizeField + `":`
+		base := size + len(prefix)
+		size = base + len(strconv.Itoa(base))
+		size = base + len(strconv.Itoa(size))
+
+		jsonLog = append(jsonLog[:len(jsonLog)-1], prefix...)
+		jsonLog = strconv.AppendInt(jsonLog, int64(size), 10)
+		jsonLog = append(jsonLog, '}')
+	}
+
+	if i.SizeRecorder != nil {
+		i.SizeRecorder(EntrySize{App: i.App, Scope: i.Scope, Caller: callerPath(call), Level: level, Bytes: size})
+	}
+
+	return jsonLog
+}
+
+// callerPath returns the caller path of a caller field value, empty when unknown
+func callerPath(call any) string {
+	switch c := call.(type) {
+	case caller.Ptr:
+		if c != nil {
+			return c.Path
+		}
+	case interface{ String() string }:
+		return c.String()
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// maxErrorChain errors kept when walking a chain, guards against cyclic wrappers
+const maxErrorChain = 32
+
+// errorChain error attached by WithError, with the call site stack when enabled
+type errorChain struct {
+	err   error
+	stack []string
+}
+
+// WithError attaches err under the error field, with its whole Unwrap and errors.Join chain
+// and, with ErrorStack enabled, the stack of the call site
+func (i *innerJsonLog) WithError(err error) Interface {
+	value := newErrorChain(err, i.ErrorStack)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.fields[ErrorField] = value
+	return i
+}
+
+// WithError attaches err under the error field, with its whole Unwrap and errors.Join chain
+// and, with ErrorStack enabled, the stack of the call site
+func (i *JsonLogger) WithError(err error) Interface {
+	return i.segment(map[string]any{ErrorField: newErrorChain(err, i.ErrorStack)})
+}
+
+func newErrorChain(err error, withStack bool) errorChain {
+	chain := errorChain{err: err}
+	if withStack {
+		chain.stack = callStack(3)
+	}
+
+	return chain
+}
+
+// value returns the logged representation: the error string, the chain and the stack
+func (c errorChain) value() any {
+	if c.err == nil {
+		return "nil"
+	}
+
+	value := map[string]any{
+		"errorString":   c.err.Error(),
+		ErrorChainField: walkErrorChain(c.err, nil),
+	}
+
+	if len(c.stack) > 0 {
+		value[ErrorStackField] = c.stack
+	}
+
+	return value
+}
+
+// walkErrorChain appends err and every error it wraps, depth first
+func walkErrorChain(err error, chain []map[string]any) []map[string]any {
+	for err != nil && len(chain) < maxErrorChain {
+		chain = append(chain, map[string]any{
+			"errorString": err.Error(),
+			"type":        fmt.Sprintf("%T", err),
+		})
+
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, joined := range u.Unwrap() {
+				chain = walkErrorChain(joined, chain)
+			}
+			return chain
+		default:
+			return chain
+		}
+	}
+
+	return chain
+}
+
+// callStack returns the stack, as "function file:line" frames, skipping skip callers
+func callStack(skip int) []string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+1, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
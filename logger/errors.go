@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ErrorSerializer converts an error attached as a field into its logged representation
+type ErrorSerializer func(error) any
+
+// DefaultErrorSerializer default error serialization; keeps the error string and, for
+// non trivial errors, the exported fields of the innermost unwrapped error, also
+// keyed by their json tags under details
+func DefaultErrorSerializer(err error) any {
+	// Create a map to hold both struct values and error string
+	errorInfo := make(map[string]any)
+
+	// Always add the error string
+	errorInfo["errorString"] = err.Error()
+
+	// Try to unwrap the error
+	var innerErr any = err
+	for {
+		u, ok := innerErr.(interface{ Unwrap() error })
+		if !ok {
+			break
+		}
+
+		unwrapped := u.Unwrap()
+		if unwrapped == nil {
+			break
+		}
+		innerErr = unwrapped
+	}
+
+	// check if it's a fmt.Errorf type
+	if reflect.TypeOf(innerErr).String() != "*errors.errorString" {
+		// for other error types, try reflection
+		errorValue := reflect.ValueOf(innerErr)
+		if errorValue.Kind() == reflect.Ptr {
+			errorValue = errorValue.Elem()
+		}
+		if errorValue.Kind() == reflect.Struct {
+			details := make(map[string]any)
+			for i := 0; i < errorValue.NumField(); i++ {
+				field := errorValue.Type().Field(i)
+				if !field.IsExported() {
+					continue
+				}
+
+				value := errorValue.Field(i)
+				errorInfo[field.Name] = value.Interface()
+
+				name, omitEmpty, skip := jsonFieldName(field)
+				if skip || (omitEmpty && value.IsZero()) {
+					continue
+				}
+				details[name] = value.Interface()
+			}
+
+			if len(details) > 0 {
+				errorInfo[ErrorDetailsField] = details
+			}
+		}
+	}
+
+	return errorInfo
+}
+
+// jsonFieldName resolves the field name as encoding/json would, based on the json tag
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, skip bool) {
+	tag, ok := field.Tag.Lookup("json")
+	if !ok {
+		return field.Name, false, false
+	}
+
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, false
+}
+
+// serializeError uses the logger serializer, falling back to DefaultErrorSerializer
+func (i *JsonLogger) serializeError(err error) any {
+	if i.ErrorSerializer != nil {
+		return i.ErrorSerializer(err)
+	}
+
+	return DefaultErrorSerializer(err)
+}
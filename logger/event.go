@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"slices"
+	"sync"
+)
+
+// Event one-shot entry builder, fields added to it belong only to the entry finalized by Msg.
+// a nil Event, returned for disabled levels, ignores every call
+type Event struct {
+	parent *innerJsonLog
+	root   *JsonLogger
+	level  LogLevelEnum
+	err    error
+	fields []Field
+}
+
+var eventPool = sync.Pool{
+	New: func() any {
+		return &Event{fields: make([]Field, 0, 8)}
+	},
+}
+
+func newEvent(root *JsonLogger, parent *innerJsonLog, level LogLevelEnum) *Event {
+	if !root.Enabled(level) {
+		return nil
+	}
+
+	e := eventPool.Get().(*Event)
+	e.root = root
+	e.parent = parent
+	e.level = level
+	return e
+}
+
+// Event starts an entry at level, nil when the level is disabled
+func (i *JsonLogger) Event(level LogLevelEnum) *Event {
+	return newEvent(i, nil, level)
+}
+
+// Event starts an entry at level carrying the logger fields, nil when the level is disabled
+func (i *innerJsonLog) Event(level LogLevelEnum) *Event {
+	return newEvent(i.JsonLogger, i, level)
+}
+
+// Str adds a string field
+func (e *Event) Str(key string, value string) *Event {
+	return e.Field(String(key, value))
+}
+
+// Int adds an int field
+func (e *Event) Int(key string, value int) *Event {
+	return e.Field(Int(key, value))
+}
+
+// Int64 adds an int64 field
+func (e *Event) Int64(key string, value int64) *Event {
+	return e.Field(Int64(key, value))
+}
+
+// Float64 adds a float64 field
+func (e *Event) Float64(key string, value float64) *Event {
+	return e.Field(Float64(key, value))
+}
+
+// Bool adds a bool field
+func (e *Event) Bool(key string, value bool) *Event {
+	return e.Field(Bool(key, value))
+}
+
+// Any adds a field of any type
+func (e *Event) Any(key string, value any) *Event {
+	return e.Field(Any(key, value))
+}
+
+// Object adds a field logged through value MarshalLog
+func (e *Event) Object(key string, value LogMarshaler) *Event {
+	return e.Field(Object(key, value))
+}
+
+// Err attaches err under the error field
+func (e *Event) Err(err error) *Event {
+	if e != nil {
+		e.err = err
+	}
+	return e
+}
+
+// Field adds typed fields
+func (e *Event) Field(fields ...Field) *Event {
+	if e != nil {
+		e.fields = append(e.fields, fields...)
+	}
+	return e
+}
+
+// Msg writes the entry and releases the event, it must not be used afterwards. FATAL events exit like Fatal
+func (e *Event) Msg(msg string) {
+	if e != nil {
+		e.write(caller.Upper(), msg)
+	}
+}
+
+// Msgf writes the entry with a formatted message and releases the event
+func (e *Event) Msgf(format string, args ...any) {
+	if e != nil {
+		e.write(caller.Upper(), format, args...)
+	}
+}
+
+// Discard releases the event without writing it
+func (e *Event) Discard() {
+	if e != nil {
+		e.release()
+	}
+}
+
+func (e *Event) write(call caller.Ptr, format string, args ...any) {
+	var segment *innerJsonLog
+	if e.parent != nil {
+		segment = e.parent.clone()
+	} else {
+		segment = e.root.entrySegment(call, nil)
+	}
+
+	segment.caller = call
+	if e.err != nil {
+		segment.fields[ErrorField] = e.err
+	}
+
+	segment.typed = slices.Grow(segment.typed, len(e.fields))
+	for _, f := range e.fields {
+		f.group = segment.group
+		segment.typed = append(segment.typed, f)
+	}
+
+	level := e.level
+	segment.log(level, format, args...)
+	e.release()
+
+	if level == FATAL {
+		segment.exit()
+	}
+}
+
+func (e *Event) release() {
+	clear(e.fields)
+	e.fields = e.fields[:0]
+	e.parent = nil
+	e.root = nil
+	e.err = nil
+	eventPool.Put(e)
+}
@@ -0,0 +1,114 @@
+//go:build windows
+
+package logger
+
+import (
+	"context"
+	"github.com/pixie-sh/logger-go/mapper"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"strings"
+)
+
+// DefaultEventID event id used when none is configured
+const DefaultEventID uint32 = 1
+
+// EventLogConfiguration windows event log configuration
+type EventLogConfiguration struct {
+	// Source event source name, usually the service name
+	Source string `toml:"source" json:"source" mapstructure:"source"`
+
+	// EventID id of the written events
+	EventID uint32 `toml:"eventId" json:"eventId" mapstructure:"eventId"`
+
+	// Install registers Source in the registry when missing, requires administrator rights
+	Install bool `toml:"install" json:"install" mapstructure:"install"`
+}
+
+// EventLogLoggerConfiguration json logger writing to the windows event log
+type EventLogLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	EventLogConfiguration   `mapstructure:",squash"`
+}
+
+// EventLogWriter writes entries to the windows event log, ERROR as error events,
+// WARN as warning events and everything else as information events.
+// the Buffer and Async options hide the entry level, every event becomes information
+type EventLogWriter struct {
+	log     *eventlog.Log
+	eventID uint32
+}
+
+func init() {
+	DefaultFactoryConfiguration.Mapping[EventLogLoggerDriver] = createEventLogLogger
+}
+
+// NewEventLogWriter opens the event log of cfg.Source
+func NewEventLogWriter(cfg EventLogConfiguration) (*EventLogWriter, error) {
+	if cfg.EventID == 0 {
+		cfg.EventID = DefaultEventID
+	}
+
+	if cfg.Install {
+		err := eventlog.InstallAsEventCreate(cfg.Source, eventlog.Error|eventlog.Warning|eventlog.Info)
+		if err != nil && !strings.Contains(err.Error(), "registry key already exists") {
+			return nil, err
+		}
+	}
+
+	log, err := eventlog.Open(cfg.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventLogWriter{log: log, eventID: cfg.EventID}, nil
+}
+
+// Write writes p as an information event
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(LOG, p)
+}
+
+// WriteLevel writes p with the event type matching level
+func (w *EventLogWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	var err error
+	switch {
+	case level <= ERROR:
+		err = w.log.Error(w.eventID, msg)
+	case level == WARN:
+		err = w.log.Warning(w.eventID, msg)
+	default:
+		err = w.log.Info(w.eventID, msg)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the event log handle
+func (w *EventLogWriter) Close() error {
+	return w.log.Close()
+}
+
+func createEventLogLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg EventLogLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Source == "" {
+		cfg.Source = generic.App
+	}
+
+	writer, err := NewEventLogWriter(cfg.EventLogConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -27,9 +27,19 @@ func NewFactory(_ context.Context, config FactoryConfiguration) (Factory, error)
 // Create returns a new logger.Interface or error
 func (f *Factory) Create(ctx context.Context, configuration Configuration) (Interface, error) {
 	fn, exist := f.createMap[configuration.Driver]
+	if len(configuration.Routes) > 0 {
+		fn, exist = f.createRoutedLogger, true
+	}
+
 	if !exist {
 		return nil, fmt.Errorf("unknown logger driver %s. unable to create", configuration.Driver)
 	}
 
-	return fn(ctx, configuration)
+	log, err := fn(ctx, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	registerOwned(log)
+	return log, nil
 }
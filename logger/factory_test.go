@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"os"
+	"syscall"
 	"testing"
+	"time"
 )
 
 func TestFactory(t *testing.T) {
@@ -38,3 +42,237 @@ func TestFactory(t *testing.T) {
 	log := logger.With("A", container{Test: "A inner", Inner: &container{Test: "B inner"}})
 	log.Log("something to flush the logger")
 }
+
+type syncWriter struct {
+	bytes.Buffer
+	flushed int
+	closed  int
+}
+
+func (w *syncWriter) Flush() error {
+	w.flushed++
+	return nil
+}
+
+func (w *syncWriter) Close() error {
+	w.closed++
+	return nil
+}
+
+func TestFactoryRegistersSyncers(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	writer := &syncWriter{}
+	logger, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   JSONLoggerDriver,
+		Values:   JSONLoggerConfiguration{Writer: writer},
+	})
+	assert.Nil(t, err)
+
+	logger.Log("buffered entry")
+	assert.Nil(t, FlushAll())
+	assert.Equal(t, 1, writer.flushed)
+
+	assert.Nil(t, CloseAll())
+	assert.Equal(t, 2, writer.flushed)
+	assert.Equal(t, 1, writer.closed)
+
+	assert.Nil(t, FlushAll())
+	assert.Equal(t, 2, writer.flushed, "closed syncers are unregistered")
+}
+
+func TestFactoryRegistersOutputsOnce(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	registered := func() int {
+		syncersMu.Lock()
+		defer syncersMu.Unlock()
+		return len(syncers)
+	}
+	before := registered()
+
+	writer := &syncWriter{}
+	var logger Interface
+	for i := 0; i < 10; i++ {
+		logger, err = factory.Create(context.Background(), Configuration{
+			App:      "App",
+			Scope:    "Scope",
+			LogLevel: LOG,
+			Driver:   JSONLoggerDriver,
+			Values:   JSONLoggerConfiguration{Writer: writer},
+		})
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, before+1, registered(), "loggers on the same output share its entry")
+
+	assert.Nil(t, logger.(Syncer).Close())
+	assert.Equal(t, before, registered())
+	assert.Equal(t, 1, writer.closed)
+}
+
+// sliceSyncer syncer of a non comparable type
+type sliceSyncer struct {
+	counts []int
+}
+
+func (s sliceSyncer) Flush() error {
+	s.counts[0]++
+	return nil
+}
+
+func (s sliceSyncer) Close() error {
+	s.counts[1]++
+	return nil
+}
+
+func TestRegisterNonComparableSyncer(t *testing.T) {
+	syncer := sliceSyncer{counts: make([]int, 2)}
+	assert.NotPanics(t, func() { RegisterSyncer(syncer) })
+
+	assert.Nil(t, FlushAll())
+	assert.Equal(t, 1, syncer.counts[0])
+
+	assert.NotPanics(t, func() { unregisterSyncer(syncer) })
+	assert.Nil(t, CloseAll())
+	assert.Equal(t, 1, syncer.counts[1])
+
+	assert.Nil(t, FlushAll())
+	assert.Equal(t, 1, syncer.counts[0], "closed syncers are unregistered")
+}
+
+func TestSingletonClose(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	global, sink := &syncWriter{}, &syncWriter{}
+	defer func(log Interface) { Logger = log }(Logger)
+	Logger, _ = NewJsonLogger(context.Background(), global, "App", "Scope", "", LOG, nil)
+
+	_, err = factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   JSONLoggerDriver,
+		Values:   JSONLoggerConfiguration{Writer: sink},
+	})
+	assert.Nil(t, err)
+
+	assert.Nil(t, Flush())
+	assert.Equal(t, 1, global.flushed)
+	assert.Equal(t, 1, sink.flushed)
+
+	assert.Nil(t, Close())
+	assert.Equal(t, 1, global.closed)
+	assert.Equal(t, 1, sink.closed)
+}
+
+func TestHandleShutdown(t *testing.T) {
+	writer := &syncWriter{}
+	log, _ := NewJsonLogger(context.Background(), writer, "App", "Scope", "", LOG, nil)
+	RegisterSyncer(log)
+
+	exitCode := -1
+	defer func(fn func(int)) { ExitFunc = fn }(ExitFunc)
+	ExitFunc = func(code int) { exitCode = code }
+
+	signals := make(chan os.Signal, 1)
+	signals <- syscall.SIGTERM
+	handleShutdown(context.Background(), signals)
+
+	assert.Equal(t, 128+int(syscall.SIGTERM), exitCode)
+	assert.Equal(t, 1, writer.closed)
+}
+
+func TestFactoryBufferedOutput(t *testing.T) {
+	factory, _ := NewFactory(context.Background(), DefaultFactoryConfiguration)
+
+	buf := new(bytes.Buffer)
+	logger, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   JSONLoggerDriver,
+		Values: JSONLoggerConfiguration{
+			Writer: buf,
+			Buffer: &BufferedWriterConfiguration{FlushInterval: time.Hour},
+		},
+	})
+	assert.Nil(t, err)
+
+	logger.Log("buffered")
+	assert.Zero(t, buf.Len(), "entries stay buffered until flushed")
+
+	logger.Error("flushed")
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")), "ERROR entries flush the buffer")
+
+	logger.Log("closed")
+	assert.Nil(t, logger.(Syncer).Close())
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func TestFactoryEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	encoder := EncoderFunc(func(entry map[string]any) ([]byte, error) {
+		return []byte(fmt.Sprintf("%s %s", entry["level"], entry["message"])), nil
+	})
+
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "app", LogLevel: DEBUG}, JSONLoggerConfiguration{Writer: buf, Encoder: encoder, SizeField: true})
+	assert.NoError(t, err)
+
+	log.Warn("custom encoding")
+	assert.Equal(t, "WARN custom encoding\n", buf.String())
+}
+
+func TestFactoryJSONBackend(t *testing.T) {
+	_, err := newConfiguredJsonLogger(context.Background(), Configuration{}, JSONLoggerConfiguration{Writer: new(bytes.Buffer), JSONBackend: "missing"})
+	assert.Error(t, err)
+
+	calls := 0
+	RegisterJSONBackend("counting", func(v any) ([]byte, error) {
+		calls++
+		return json.Marshal(v)
+	})
+
+	buf := new(bytes.Buffer)
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{LogLevel: DEBUG}, JSONLoggerConfiguration{Writer: buf, JSONBackend: "counting"})
+	assert.NoError(t, err)
+
+	log.Log("counted")
+	assert.Equal(t, 1, calls)
+	assert.True(t, json.Valid(buf.Bytes()))
+}
+
+func TestFactoryCustomDriver(t *testing.T) {
+	factory, err := NewFactory(context.Background(), FactoryConfiguration{
+		Mapping: map[string]FactoryCreateFn{
+			"nop": func(context.Context, Configuration) (Interface, error) { return NewNopLogger(), nil },
+		},
+	})
+	assert.NoError(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{Driver: "nop"})
+	assert.NoError(t, err)
+
+	previous := Logger
+	defer func() { Logger = previous }()
+	Logger = log
+
+	assert.NotNil(t, Clone())
+	assert.NotNil(t, WithCtx(context.Background()))
+}
+
+func TestFactoryNullDriver(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.NoError(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{Driver: NullLoggerDriver, LogLevel: DEBUG})
+	assert.NoError(t, err)
+	assert.Equal(t, NopLogger{}, log)
+	assert.False(t, log.Enabled(ERROR))
+	assert.NotPanics(t, func() { log.With("k", "v").Error("discarded") })
+}
@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+	"sync"
+)
+
+func init() {
+	DefaultFactoryConfiguration.Mapping[FailoverLoggerDriver] = createFailoverLogger
+}
+
+// FailoverDestination destination of a FailoverWriter, Name identifies it in Served
+type FailoverDestination struct {
+	Name   string
+	Writer io.Writer
+}
+
+// FailoverError entry served by a fallback, reported to the DiagnosticsHandler
+type FailoverError struct {
+	From string
+	To   string
+	Err  error
+}
+
+// Error returns the failover description
+func (e FailoverError) Error() string {
+	return fmt.Sprintf("failover from %s to %s: %v", e.From, e.To, e.Err)
+}
+
+// Unwrap returns the error of the failed destination
+func (e FailoverError) Unwrap() error {
+	return e.Err
+}
+
+// FailoverWriter writes every entry to the first destination accepting it, eg: remote sink, then local file,
+// then stderr. failovers are reported to the DiagnosticsHandler and counted per serving destination
+type FailoverWriter struct {
+	destinations []FailoverDestination
+
+	mu         sync.Mutex
+	served     map[string]uint64
+	lastServed string
+}
+
+// NewFailoverWriter returns a failover writer trying destinations in order
+func NewFailoverWriter(destinations ...FailoverDestination) *FailoverWriter {
+	for i := range destinations {
+		if destinations[i].Name == "" {
+			destinations[i].Name = fmt.Sprintf("destination %d", i)
+		}
+	}
+
+	return &FailoverWriter{destinations: destinations, served: map[string]uint64{}}
+}
+
+// Write writes p to the first destination accepting it
+func (w *FailoverWriter) Write(p []byte) (int, error) {
+	return w.write(func(dst io.Writer) error {
+		_, err := dst.Write(p)
+		return err
+	}, len(p))
+}
+
+// WriteLevel writes p with its level to the first destination accepting it
+func (w *FailoverWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	return w.write(func(dst io.Writer) error {
+		var err error
+		if lw, ok := dst.(LevelWriter); ok {
+			_, err = lw.WriteLevel(level, p)
+		} else {
+			_, err = dst.Write(p)
+		}
+		return err
+	}, len(p))
+}
+
+// Served returns the count of entries served by each destination
+func (w *FailoverWriter) Served() map[string]uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	served := make(map[string]uint64, len(w.served))
+	for name, count := range w.served {
+		served[name] = count
+	}
+	return served
+}
+
+// LastServed returns the name of the destination that served the last entry
+func (w *FailoverWriter) LastServed() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.lastServed
+}
+
+// Flush flushes every destination
+func (w *FailoverWriter) Flush() error {
+	var errs []error
+	for _, d := range w.destinations {
+		errs = append(errs, flushWriter(d.Writer))
+	}
+
+	return errors.Join(errs...)
+}
+
+// flushQueued drains the buffers of every destination, without syncing files
+func (w *FailoverWriter) flushQueued() {
+	for _, d := range w.destinations {
+		flushBuffered(d.Writer)
+	}
+}
+
+// Close flushes and closes every destination, standard streams are left open
+func (w *FailoverWriter) Close() error {
+	var errs []error
+	for _, d := range w.destinations {
+		errs = append(errs, closeWriter(d.Writer))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *FailoverWriter) write(write func(io.Writer) error, n int) (int, error) {
+	var errs []error
+	for i, d := range w.destinations {
+		err := write(d.Writer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.Name, err))
+			continue
+		}
+
+		w.mu.Lock()
+		w.served[d.Name]++
+		w.lastServed = d.Name
+		w.mu.Unlock()
+
+		if i > 0 {
+			reportDiagnostic(FailoverError{From: w.destinations[0].Name, To: d.Name, Err: errors.Join(errs...)})
+		}
+		return n, nil
+	}
+
+	return 0, errors.Join(errs...)
+}
+
+// FailoverDestinationConfiguration destination of the failover logger driver: either Writer or
+// the writer of a logger created by Driver with Values. Name defaults to Driver
+type FailoverDestinationConfiguration struct {
+	Name   string `toml:"name" json:"name" mapstructure:"name"`
+	Writer io.Writer
+	Driver string `toml:"driver" json:"driver" mapstructure:"driver"`
+	Values any    `toml:"values" json:"values" mapstructure:"values"`
+}
+
+// FailoverLoggerConfiguration json logger writing to the first destination accepting each entry
+type FailoverLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	Destinations            []FailoverDestinationConfiguration `toml:"destinations" json:"destinations" mapstructure:"destinations"`
+}
+
+func createFailoverLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg FailoverLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	destinations := make([]FailoverDestination, 0, len(cfg.Destinations))
+	for _, d := range cfg.Destinations {
+		dst, err := multiDestinationWriter(ctx, generic, MultiDestinationConfiguration{
+			Level:  DEBUG,
+			Writer: d.Writer,
+			Driver: d.Driver,
+			Values: d.Values,
+		})
+		if err != nil {
+			_ = NewFailoverWriter(destinations...).Close()
+			return nil, err
+		}
+
+		name := d.Name
+		if name == "" {
+			name = d.Driver
+		}
+		destinations = append(destinations, FailoverDestination{Name: name, Writer: dst})
+	}
+
+	cfg.Writer = NewFailoverWriter(destinations...)
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type toggleWriter struct {
+	syncWriter
+	down bool
+}
+
+func (w *toggleWriter) Write(p []byte) (int, error) {
+	if w.down {
+		return 0, errors.New("down")
+	}
+	return w.syncWriter.Write(p)
+}
+
+func TestFailoverWriter(t *testing.T) {
+	var reported []error
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	remote, local := &toggleWriter{}, &syncWriter{}
+	w := NewFailoverWriter(
+		FailoverDestination{Name: "remote", Writer: remote},
+		FailoverDestination{Name: "local", Writer: local},
+	)
+
+	_, err := w.WriteLevel(WARN, []byte("first\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "remote", w.LastServed())
+
+	remote.down = true
+	_, err = w.Write([]byte("second\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "local", w.LastServed())
+	assert.Equal(t, "first\n", remote.String())
+	assert.Equal(t, "second\n", local.String())
+	assert.Equal(t, map[string]uint64{"remote": 1, "local": 1}, w.Served())
+
+	assert.Len(t, reported, 1)
+	var failover FailoverError
+	assert.True(t, errors.As(reported[0], &failover))
+	assert.Equal(t, "remote", failover.From)
+	assert.Equal(t, "local", failover.To)
+	assert.Contains(t, failover.Error(), "down")
+
+	assert.Nil(t, w.Close())
+	assert.Equal(t, 1, remote.closed)
+	assert.Equal(t, 1, local.closed)
+}
+
+func TestFailoverWriterAllFailed(t *testing.T) {
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = nil
+
+	w := NewFailoverWriter(
+		FailoverDestination{Writer: &toggleWriter{down: true}},
+		FailoverDestination{Writer: &toggleWriter{down: true}},
+	)
+
+	n, err := w.Write([]byte("entry\n"))
+	assert.Equal(t, 0, n)
+	assert.ErrorContains(t, err, "destination 0: down")
+	assert.ErrorContains(t, err, "destination 1: down")
+	assert.Empty(t, w.Served())
+	assert.Equal(t, "", w.LastServed())
+}
+
+func TestFactoryFailoverDriver(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = nil
+
+	remote, local := &toggleWriter{down: true}, &syncWriter{}
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: DEBUG,
+		Driver:   FailoverLoggerDriver,
+		Values: map[string]any{
+			"destinations": []map[string]any{
+				{"name": "remote", "Writer": remote},
+				{"name": "local", "Writer": local},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Warn("failed over")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 1, strings.Count(local.String(), "\n"))
+	assert.Contains(t, local.String(), "failed over")
+	assert.Equal(t, 1, remote.closed)
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+// FanoutWriter dispatches every entry to several sinks, each one with its own queue and goroutine,
+// so a slow sink doesn't delay the others. entries are encoded once and shared by the sinks
+type FanoutWriter struct {
+	sinks []*AsyncWriter
+}
+
+// NewFanoutWriter returns a fanout writer over sinks, queued according to cfg
+func NewFanoutWriter(cfg AsyncWriterConfiguration, sinks ...io.Writer) (*FanoutWriter, error) {
+	w := &FanoutWriter{}
+	for _, sink := range sinks {
+		async, err := NewAsyncWriter(sink, cfg)
+		if err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+		w.sinks = append(w.sinks, async)
+	}
+
+	return w, nil
+}
+
+// Write queues p on every sink
+func (w *FanoutWriter) Write(p []byte) (int, error) {
+	return w.fanout(asyncEntry{data: copyEntry(p)})
+}
+
+// WriteLevel queues p along with its level on every sink
+func (w *FanoutWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	return w.fanout(asyncEntry{data: copyEntry(p), level: level, leveled: true})
+}
+
+func (w *FanoutWriter) fanout(entry asyncEntry) (int, error) {
+	var errs []error
+	for _, sink := range w.sinks {
+		if _, err := sink.enqueue(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(entry.data), errors.Join(errs...)
+}
+
+// Sinks returns the queued sinks, eg: to inspect their Dropped count
+func (w *FanoutWriter) Sinks() []*AsyncWriter {
+	return w.sinks
+}
+
+// Flush waits for every sink queue to drain and flushes the sinks
+func (w *FanoutWriter) Flush() error {
+	var errs []error
+	for _, sink := range w.sinks {
+		errs = append(errs, sink.Flush())
+	}
+
+	return errors.Join(errs...)
+}
+
+// flushQueued waits for the entries queued so far on every sink, up to their ErrorFlushTimeout
+func (w *FanoutWriter) flushQueued() {
+	for _, sink := range w.sinks {
+		sink.flushQueued()
+	}
+}
+
+// Close drains and closes every sink
+func (w *FanoutWriter) Close() error {
+	var errs []error
+	for _, sink := range w.sinks {
+		errs = append(errs, sink.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Rotate rotates every rotatable sink
+func (w *FanoutWriter) Rotate() error {
+	return w.eachRotator(Rotator.Rotate)
+}
+
+// Reopen reopens every rotatable sink
+func (w *FanoutWriter) Reopen() error {
+	return w.eachRotator(Rotator.Reopen)
+}
+
+func (w *FanoutWriter) eachRotator(fn func(Rotator) error) error {
+	var errs []error
+	rotated := false
+	for _, sink := range w.sinks {
+		err := rotateWriter(sink, fn)
+		if errors.Is(err, errNotRotatable) {
+			continue
+		}
+
+		rotated = true
+		errs = append(errs, err)
+	}
+
+	if !rotated {
+		return errNotRotatable
+	}
+
+	return errors.Join(errs...)
+}
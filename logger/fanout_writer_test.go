@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFanoutSlowSink(t *testing.T) {
+	fast := newGatedWriter()
+	close(fast.gate)
+	slow := newGatedWriter()
+
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "app", LogLevel: DEBUG}, JSONLoggerConfiguration{
+		Writer: fast,
+		Sinks:  []io.Writer{slow},
+	})
+	assert.NoError(t, err)
+
+	log.Log("first")
+	log.Log("second")
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(fast.String(), "\n") == 2
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, slow.String())
+
+	close(slow.gate)
+	assert.NoError(t, log.Close())
+	assert.Equal(t, fast.String(), slow.String())
+}
+
+func TestFanoutRotate(t *testing.T) {
+	writer, err := NewFanoutWriter(AsyncWriterConfiguration{}, io.Discard)
+	assert.NoError(t, err)
+	defer func() { _ = writer.Close() }()
+
+	assert.ErrorIs(t, writer.Rotate(), errNotRotatable)
+	assert.Len(t, writer.Sinks(), 1)
+}
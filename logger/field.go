@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"math"
+	"time"
+)
+
+// FieldType type of a typed Field value
+type FieldType uint8
+
+// supported field types
+const (
+	AnyType FieldType = iota
+	StringType
+	IntType
+	FloatType
+	BoolType
+	ErrorType
+	DurationType
+	TimeType
+)
+
+// Field typed key/value pair, primitives are kept unboxed until the entry is written
+type Field struct {
+	Key       string
+	Type      FieldType
+	Integer   int64
+	Str       string
+	Interface any
+
+	group []string
+}
+
+// String returns a string field
+func String(key string, value string) Field {
+	return Field{Key: key, Type: StringType, Str: value}
+}
+
+// Int returns an int field
+func Int(key string, value int) Field {
+	return Field{Key: key, Type: IntType, Integer: int64(value)}
+}
+
+// Int64 returns an int64 field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Type: IntType, Integer: value}
+}
+
+// Float64 returns a float64 field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Type: FloatType, Integer: int64(math.Float64bits(value))}
+}
+
+// Bool returns a bool field
+func Bool(key string, value bool) Field {
+	var integer int64
+	if value {
+		integer = 1
+	}
+
+	return Field{Key: key, Type: BoolType, Integer: integer}
+}
+
+// Err returns an error field under ErrorField
+func Err(err error) Field {
+	return Field{Key: ErrorField, Type: ErrorType, Interface: err}
+}
+
+// Duration returns a time.Duration field
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Type: DurationType, Integer: int64(value)}
+}
+
+// Time returns a time.Time field, kept as unix nanoseconds and location. times out of the
+// unix nanoseconds range are logged as Any
+func Time(key string, value time.Time) Field {
+	if value.Year() < 1678 || value.Year() > 2261 {
+		return Any(key, value)
+	}
+
+	return Field{Key: key, Type: TimeType, Integer: value.UnixNano(), Interface: value.Location()}
+}
+
+// Any returns a field of any value
+func Any(key string, value any) Field {
+	return Field{Key: key, Type: AnyType, Interface: value}
+}
+
+// Value returns the field value
+func (f Field) Value() any {
+	switch f.Type {
+	case StringType:
+		return f.Str
+	case IntType:
+		return f.Integer
+	case FloatType:
+		return math.Float64frombits(uint64(f.Integer))
+	case BoolType:
+		return f.Integer == 1
+	case DurationType:
+		return time.Duration(f.Integer)
+	case TimeType:
+		return time.Unix(0, f.Integer).In(f.Interface.(*time.Location))
+	default:
+		return f.Interface
+	}
+}
+
+// WithF adds typed fields to the logger
+func (i *innerJsonLog) WithF(fields ...Field) Interface {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, f := range fields {
+		f.group = i.group
+		i.typed = append(i.typed, f)
+	}
+
+	return i
+}
+
+// WithF adds typed fields to the logger
+func (i *JsonLogger) WithF(fields ...Field) Interface {
+	segment := i.segment(nil)
+
+	return segment.WithF(fields...)
+}
+
+// LogWith logs msg at level with typed fields, without formatting nor a child logger. FATAL exits like Fatal
+func (i *JsonLogger) LogWith(level LogLevelEnum, msg string, fields ...Field) {
+	if e := i.Event(level); e != nil {
+		e.fields = append(e.fields, fields...)
+		e.write(caller.Upper(), msg)
+	}
+}
+
+// LogWith logs msg at level with the logger fields and typed fields. FATAL exits like Fatal
+func (i *innerJsonLog) LogWith(level LogLevelEnum, msg string, fields ...Field) {
+	if e := i.Event(level); e != nil {
+		e.fields = append(e.fields, fields...)
+		e.write(caller.Upper(), msg)
+	}
+}
+
+// addTypedFields adds the typed fields to the entry, nested under their groups. inlined fields are left out,
+// they're appended to the encoded entry
+func (i *innerJsonLog) addTypedFields(logEntry map[string]any, inlined bool) {
+	for _, f := range i.typed {
+		if inlined && inlineable(f) {
+			continue
+		}
+
+		target := logEntry
+		for _, name := range f.group {
+			nested, ok := target[name].(fieldGroup)
+			if !ok {
+				nested = fieldGroup{}
+				target[name] = nested
+			}
+			target = nested
+		}
+
+		i.putField(target, f.Key, i.fieldValue(f.Value()))
+	}
+}
+
+// WithTyped adds key with value to log, primitive values are kept typed to avoid interface conversions
+func WithTyped[T any](log Interface, key string, value T) Interface {
+	return log.WithF(TypedField(key, value))
+}
+
+// TypedField returns the typed field matching value type, Any for non primitive types
+func TypedField[T any](key string, value T) Field {
+	switch v := any(value).(type) {
+	case string:
+		return String(key, v)
+	case int:
+		return Int(key, v)
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint8:
+		return Int64(key, int64(v))
+	case uint16:
+		return Int64(key, int64(v))
+	case uint32:
+		return Int64(key, int64(v))
+	case float32:
+		return Float64(key, float64(v))
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case time.Duration:
+		return Duration(key, v)
+	case time.Time:
+		return Time(key, v)
+	case error:
+		return Field{Key: key, Type: ErrorType, Interface: v}
+	default:
+		return Any(key, v)
+	}
+}
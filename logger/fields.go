@@ -0,0 +1,51 @@
+package logger
+
+// field keys written by the logger itself
+const (
+	ErrorField  = "error"
+	CallerField = "caller"
+
+	// LoggerField component path of a logger created with Named, e.g. payments.refunds
+	LoggerField = "logger"
+
+	// PanicField set to true on entries logged by Panic
+	PanicField = "panic"
+
+	// VersionField schema version of the entry, see SchemaVersion
+	VersionField = "version"
+
+	// LevelNumField numeric level, lower is more severe: level_num <= 1 matches ERROR and WARN
+	LevelNumField = "level_num"
+
+	EntryIDField       = "entry_id"
+	SequenceField      = "seq"
+	HostField          = "host"
+	PIDField           = "pid"
+	FormatWarningField = "format_warning"
+	SuppressedField    = "suppressed_count"
+	EntrySizeField     = "entry_bytes"
+
+	// DroppedField count of entries dropped by a full AsyncWriter queue, see DropReportInterval
+	DroppedField = "dropped"
+
+	OperationField = "operation"
+	DurationField  = "duration_ms"
+	OutcomeField   = "outcome"
+
+	ProcessedField = "processed"
+	TotalField     = "total"
+	RateField      = "rate"
+	ETAField       = "eta_ms"
+
+	// HTTPField group holding access log fields
+	HTTPField = "http"
+
+	// ErrorDetailsField nested under the error field, holds json tagged error struct fields
+	ErrorDetailsField = "details"
+
+	// ErrorChainField nested under the error field by WithError, holds the wrapped errors
+	ErrorChainField = "chain"
+
+	// ErrorStackField nested under the error field by WithError, holds the call site stack
+	ErrorStackField = "stack"
+)
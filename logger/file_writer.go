@@ -0,0 +1,546 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultFilePerm = 0o644
+
+	// DefaultFsyncInterval fsync period of the FsyncInterval policy
+	DefaultFsyncInterval = time.Second
+
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+)
+
+// FsyncPolicy when a FileWriter commits written entries to stable storage
+type FsyncPolicy string
+
+// supported fsync policies
+const (
+	// FsyncNone leaves it to the os, the default
+	FsyncNone FsyncPolicy = "none"
+
+	// FsyncEveryWrite syncs after every entry, durable but slow, eg: for audit logs
+	FsyncEveryWrite FsyncPolicy = "write"
+
+	// FsyncInterval syncs every FsyncInterval when entries were written
+	FsyncInterval FsyncPolicy = "interval"
+)
+
+// FileWriterConfiguration file sink configuration
+type FileWriterConfiguration struct {
+	Path string      `toml:"path" json:"path" mapstructure:"path"`
+	Perm os.FileMode `toml:"perm" json:"perm" mapstructure:"perm"`
+
+	// MaxSize in bytes of the active file before it's rotated, 0 disables size based rotation
+	MaxSize int64 `toml:"maxSize" json:"maxSize" mapstructure:"maxSize"`
+
+	// MaxTotalSize in bytes of the active file plus backups, oldest backups are evicted
+	// to honor it. 0 disables the quota
+	MaxTotalSize int64 `toml:"maxTotalSize" json:"maxTotalSize" mapstructure:"maxTotalSize"`
+
+	// MaxBackups number of backups to keep, 0 keeps all
+	MaxBackups int `toml:"maxBackups" json:"maxBackups" mapstructure:"maxBackups"`
+
+	// MaxAge of backups before they're removed, 0 keeps them regardless of age
+	MaxAge time.Duration `toml:"maxAge" json:"maxAge" mapstructure:"maxAge"`
+
+	// PostRotate called in the background, one backup at a time, with each rotated backup
+	// path once compressed. the hook may move or delete the backup, eg: after uploading it. errors are
+	// reported to DiagnosticsHandler
+	PostRotate func(path string) error `toml:"-" json:"-" mapstructure:"postRotate"`
+
+	// Compress algorithm used to compress rotated backups, eg: gzip or zstd. empty keeps them as is
+	Compress string `toml:"compress" json:"compress" mapstructure:"compress"`
+
+	// ReopenOnSIGHUP reopens Path on SIGHUP, to be used with external logrotate setups
+	ReopenOnSIGHUP bool `toml:"reopenOnSighup" json:"reopenOnSighup" mapstructure:"reopenOnSighup"`
+
+	// Fsync policy, none by default. entries are written with a single O_APPEND write, so several processes
+	// can share Path, although size based rotation must then be left to a single one or to logrotate
+	Fsync         FsyncPolicy   `toml:"fsync" json:"fsync" mapstructure:"fsync"`
+	FsyncInterval time.Duration `toml:"fsyncInterval" json:"fsyncInterval" mapstructure:"fsyncInterval"`
+}
+
+// FileWriter appends entries to a file, supporting reopen and rotation
+type FileWriter struct {
+	cfg FileWriterConfiguration
+
+	mu      sync.Mutex
+	closed  bool
+	file    *os.File
+	size    int64
+	dirty   bool
+	backups []backupFile
+
+	processing  sync.WaitGroup
+	processMu   sync.Mutex
+	stopSignals func()
+	stopFsync   chan struct{}
+	fsyncDone   chan struct{}
+}
+
+// NewFileWriter opens, or creates, the configured file for appending
+func NewFileWriter(cfg FileWriterConfiguration) (*FileWriter, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("unable to create file writer, path is missing")
+	}
+
+	if cfg.Perm == 0 {
+		cfg.Perm = defaultFilePerm
+	}
+
+	if cfg.Compress != "" {
+		if _, err := compressExtension(cfg.Compress); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Fsync {
+	case "":
+		cfg.Fsync = FsyncNone
+	case FsyncNone, FsyncEveryWrite, FsyncInterval:
+	default:
+		return nil, fmt.Errorf("unknown fsync policy %s", cfg.Fsync)
+	}
+
+	if cfg.FsyncInterval <= 0 {
+		cfg.FsyncInterval = DefaultFsyncInterval
+	}
+
+	w := &FileWriter{cfg: cfg}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if err := w.scanBackups(); err != nil {
+		_ = w.file.Close()
+		return nil, err
+	}
+	w.applyRetention()
+
+	if cfg.ReopenOnSIGHUP {
+		w.stopSignals = w.reopenOnSignal()
+	}
+
+	if cfg.Fsync == FsyncInterval {
+		w.stopFsync = make(chan struct{})
+		w.fsyncDone = make(chan struct{})
+		go w.fsyncPeriodically(w.stopFsync, w.fsyncDone)
+	}
+
+	return w, nil
+}
+
+// Write appends p to the file, rotating it first when MaxSize would be exceeded
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.cfg.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.cfg.MaxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.enforceQuota(int64(len(p))); err != nil {
+		return 0, err
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+
+	w.dirty = true
+	if w.cfg.Fsync == FsyncEveryWrite {
+		return n, w.syncLocked()
+	}
+
+	return n, nil
+}
+
+// Reopen closes and reopens the configured path, picking up a file moved away by external tools
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	if w.file != nil {
+		_ = w.file.Close()
+	}
+
+	return w.open()
+}
+
+// Rotate moves the active file to a timestamped backup and starts a new one
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	return w.rotate()
+}
+
+// Sync commits the file contents to stable storage
+func (w *FileWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.syncLocked()
+}
+
+// Close stops signal handling and periodic fsync, waits for pending backup processing and closes the file,
+// synced first unless the policy is FsyncNone
+func (w *FileWriter) Close() error {
+	w.processing.Wait()
+
+	w.mu.Lock()
+	stopFsync := w.stopFsync
+	w.stopFsync = nil
+	w.mu.Unlock()
+
+	if stopFsync != nil {
+		close(stopFsync)
+		<-w.fsyncDone
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.stopSignals != nil {
+		w.stopSignals()
+		w.stopSignals = nil
+	}
+
+	if w.file == nil {
+		return nil
+	}
+
+	var err error
+	if w.cfg.Fsync != FsyncNone {
+		err = w.syncLocked()
+	}
+
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	return err
+}
+
+func (w *FileWriter) syncLocked() error {
+	if w.file == nil {
+		return nil
+	}
+
+	w.dirty = false
+	return w.file.Sync()
+}
+
+// fsyncPeriodically syncs the file every FsyncInterval when entries were written since the last sync
+func (w *FileWriter) fsyncPeriodically(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.cfg.FsyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			var err error
+			if w.dirty {
+				err = w.syncLocked()
+			}
+			w.mu.Unlock()
+
+			if err != nil {
+				reportDiagnostic(err)
+			}
+		}
+	}
+}
+
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.cfg.Path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.cfg.Perm)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+func (w *FileWriter) rotate() error {
+	if w.file != nil {
+		if w.cfg.Fsync != FsyncNone {
+			_ = w.syncLocked()
+		}
+		_ = w.file.Close()
+		w.file = nil
+	}
+
+	// backups are named after the rotation time, move forward on same millisecond rotations
+	backupTime := time.Now()
+	for {
+		if _, err := os.Stat(w.backupName(backupTime)); os.IsNotExist(err) {
+			break
+		}
+		backupTime = backupTime.Add(time.Millisecond)
+	}
+
+	backup := backupFile{path: w.backupName(backupTime), size: w.size, created: backupTime}
+	err := os.Rename(w.cfg.Path, backup.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err == nil {
+		backup.pending = w.cfg.Compress != "" || w.cfg.PostRotate != nil
+		w.backups = append(w.backups, backup)
+		w.applyRetention()
+
+		if backup.pending {
+			w.processing.Add(1)
+			go w.processBackup(backup.path)
+		}
+	}
+
+	return w.open()
+}
+
+// processBackup compresses a rotated backup and runs the PostRotate hook, in the background.
+// a backup failing to compress is kept uncompressed, compression and hook failures are reported to DiagnosticsHandler
+func (w *FileWriter) processBackup(path string) {
+	defer w.processing.Done()
+
+	w.processMu.Lock()
+	defer w.processMu.Unlock()
+	defer w.applyRetentionLocked()
+
+	if w.cfg.Compress != "" {
+		target, size, err := compressFile(path, w.cfg.Compress)
+		switch {
+		case err != nil:
+			reportDiagnostic(fmt.Errorf("file writer compress %s: %w", path, err))
+		case !w.replaceBackup(path, backupFile{path: target, size: size, pending: w.cfg.PostRotate != nil}):
+			// evicted while compressing
+			_ = os.Remove(target)
+			return
+		default:
+			_ = os.Remove(path)
+			path = target
+		}
+	}
+
+	if w.cfg.PostRotate != nil {
+		if err := w.cfg.PostRotate(path); err != nil {
+			reportDiagnostic(fmt.Errorf("file writer post rotate %s: %w", path, err))
+		}
+	}
+
+	if info, err := os.Stat(path); err != nil {
+		w.replaceBackup(path, backupFile{})
+	} else {
+		w.replaceBackup(path, backupFile{path: path, size: info.Size()})
+	}
+}
+
+// replaceBackup replaces the tracked backup at path, removing it when replacement has no path.
+// returns false if path is no longer tracked
+func (w *FileWriter) replaceBackup(path string, replacement backupFile) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for idx := range w.backups {
+		if w.backups[idx].path != path {
+			continue
+		}
+
+		if replacement.path == "" {
+			w.backups = append(w.backups[:idx], w.backups[idx+1:]...)
+			return true
+		}
+
+		replacement.created = w.backups[idx].created
+		w.backups[idx] = replacement
+		return true
+	}
+
+	return false
+}
+
+func (w *FileWriter) applyRetentionLocked() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.applyRetention()
+}
+
+// applyRetention removes the backups exceeding MaxBackups or older than MaxAge.
+// backups still being compressed or handed to PostRotate are left for later
+func (w *FileWriter) applyRetention() {
+	var expired time.Time
+	if w.cfg.MaxAge > 0 {
+		expired = time.Now().Add(-w.cfg.MaxAge)
+	}
+
+	kept := w.backups[:0]
+	for idx, backup := range w.backups {
+		tooMany := w.cfg.MaxBackups > 0 && len(w.backups)-idx > w.cfg.MaxBackups
+		tooOld := !expired.IsZero() && backup.created.Before(expired)
+		if (tooMany || tooOld) && !backup.pending {
+			_ = os.Remove(backup.path)
+			continue
+		}
+
+		kept = append(kept, backup)
+	}
+
+	w.backups = kept
+}
+
+// backupFile rotated file tracked for retention and quota purposes
+type backupFile struct {
+	path    string
+	size    int64
+	created time.Time
+	pending bool
+}
+
+// scanBackups loads the existing backups of the configured path, oldest first
+func (w *FileWriter) scanBackups() error {
+	dir, name := filepath.Split(w.cfg.Path)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "-"
+
+	entries, err := os.ReadDir(filepath.Join(dir, "."))
+	if err != nil {
+		return err
+	}
+
+	w.backups = nil
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() || len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+
+		created, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)])
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		w.backups = append(w.backups, backupFile{path: filepath.Join(dir, entry.Name()), size: info.Size(), created: created})
+	}
+
+	// timestamped names sort chronologically
+	sort.Slice(w.backups, func(i, j int) bool { return w.backups[i].path < w.backups[j].path })
+	return nil
+}
+
+// enforceQuota evicts the oldest backups until incoming bytes fit MaxTotalSize.
+// when the active file alone exceeds it, it's rotated and evicted as well
+func (w *FileWriter) enforceQuota(incoming int64) error {
+	if w.cfg.MaxTotalSize <= 0 {
+		return nil
+	}
+
+	total := w.size + incoming
+	for _, backup := range w.backups {
+		total += backup.size
+	}
+
+	for total > w.cfg.MaxTotalSize {
+		if len(w.backups) == 0 {
+			if w.size == 0 {
+				return nil
+			}
+
+			if err := w.rotate(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		oldest := w.backups[0]
+		if err := os.Remove(oldest.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		w.backups = w.backups[1:]
+		total -= oldest.size
+	}
+
+	return nil
+}
+
+// backupName returns the backup path for t, eg: app.log -> app-2006-01-02T15-04-05.000.log
+func (w *FileWriter) backupName(t time.Time) string {
+	dir, name := filepath.Split(w.cfg.Path)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext)
+
+	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", prefix, t.UTC().Format(backupTimeFormat), ext))
+}
+
+func (w *FileWriter) reopenOnSignal() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signals:
+				_ = w.Reopen()
+			}
+		}
+	}()
+
+	return cancel
+}
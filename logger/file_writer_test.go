@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	return string(content)
+}
+
+func backups(t *testing.T, path string) []string {
+	matches, err := filepath.Glob(filepath.Join(filepath.Dir(path), "app-*.log*"))
+	assert.Nil(t, err)
+	return matches
+}
+
+func TestFileWriterReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("first\n"))
+
+	// external logrotate moves the file away
+	moved := path + ".1"
+	assert.Nil(t, os.Rename(path, moved))
+	_, _ = writer.Write([]byte("second\n"))
+	assert.Nil(t, writer.Reopen())
+	_, _ = writer.Write([]byte("third\n"))
+
+	assert.Equal(t, "first\nsecond\n", readFile(t, moved))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestFileWriterRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path, MaxSize: 10})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	_, _ = writer.Write([]byte("12345\n"))
+	_, _ = writer.Write([]byte("67890\n"))
+	assert.Len(t, backups(t, path), 1, "exceeding MaxSize rotates the file")
+	assert.Equal(t, "67890\n", readFile(t, path))
+
+	assert.Nil(t, writer.Rotate())
+	assert.Len(t, backups(t, path), 2)
+	assert.Equal(t, "", readFile(t, path))
+}
+
+func TestFileLoggerDriver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	factory, _ := NewFactory(context.Background(), DefaultFactoryConfiguration)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   FileLoggerDriver,
+		Values:   map[string]any{"path": path},
+	})
+	assert.Nil(t, err)
+
+	log.Log("to file")
+	assert.Nil(t, log.(Syncer).Close())
+	assert.Contains(t, readFile(t, path), `"message":"to file"`)
+}
+
+func TestFileWriterQuota(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path, MaxSize: 10, MaxTotalSize: 25})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		_, _ = writer.Write([]byte(line))
+	}
+
+	remaining := backups(t, path)
+	assert.Len(t, remaining, 1, "oldest backups are evicted")
+	assert.Equal(t, "cccccccc\n", readFile(t, remaining[0]))
+	assert.Equal(t, "dddddddd\n", readFile(t, path))
+
+	// backups left by a previous process count towards the quota
+	assert.Nil(t, writer.Close())
+	writer, err = NewFileWriter(FileWriterConfiguration{Path: path, MaxTotalSize: 20})
+	assert.Nil(t, err)
+	_, _ = writer.Write([]byte("eeeeeeee\n"))
+	assert.Len(t, backups(t, path), 0)
+	assert.Nil(t, writer.Close())
+}
+
+func TestFileWriterRetention(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	// a backup left behind a long time ago
+	stale := filepath.Join(dir, "app-2001-01-01T00-00-00.000.log")
+	assert.Nil(t, os.WriteFile(stale, []byte("stale\n"), 0o644))
+
+	var uploaded []string
+	writer, err := NewFileWriter(FileWriterConfiguration{
+		Path:       path,
+		MaxBackups: 2,
+		MaxAge:     time.Hour,
+		PostRotate: func(path string) error {
+			uploaded = append(uploaded, readFile(t, path))
+			return nil
+		},
+	})
+	assert.Nil(t, err)
+	assert.NoFileExists(t, stale, "backups older than MaxAge are removed")
+
+	for _, line := range []string{"a\n", "b\n", "c\n"} {
+		_, _ = writer.Write([]byte(line))
+		assert.Nil(t, writer.Rotate())
+	}
+	assert.Nil(t, writer.Close())
+
+	remaining := backups(t, path)
+	assert.Len(t, remaining, 2, "MaxBackups keeps the newest backups")
+	assert.Equal(t, "b\n", readFile(t, remaining[0]))
+	assert.Equal(t, "c\n", readFile(t, remaining[1]))
+	assert.Len(t, uploaded, 3)
+}
+
+func TestFileWriterPostRotateError(t *testing.T) {
+	var reported []error
+	var mu sync.Mutex
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, err)
+	}
+
+	hookErr := errors.New("upload failed")
+	writer, err := NewFileWriter(FileWriterConfiguration{
+		Path:       filepath.Join(t.TempDir(), "app.log"),
+		PostRotate: func(string) error { return hookErr },
+	})
+	assert.Nil(t, err)
+
+	_, _ = writer.Write([]byte("a\n"))
+	assert.Nil(t, writer.Rotate())
+	assert.Nil(t, writer.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], hookErr)
+}
+
+func TestLoggerRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	factory, _ := NewFactory(context.Background(), DefaultFactoryConfiguration)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   FileLoggerDriver,
+		Values: FileLoggerConfiguration{
+			JSONLoggerConfiguration: JSONLoggerConfiguration{
+				Buffer: &BufferedWriterConfiguration{FlushInterval: time.Hour},
+				Async:  &AsyncWriterConfiguration{},
+			},
+			FileWriterConfiguration: FileWriterConfiguration{Path: path},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("before rotation")
+	assert.Nil(t, log.(Rotator).Rotate(), "rotation reaches the file through async and buffered writers")
+	log.Log("after rotation")
+	assert.Nil(t, RotateAll())
+	assert.Nil(t, log.(Syncer).Close())
+
+	rotated := backups(t, path)
+	assert.Len(t, rotated, 2)
+	assert.Contains(t, readFile(t, rotated[0]), "before rotation")
+	assert.Contains(t, readFile(t, rotated[1]), "after rotation")
+
+	stdout, _ := NewJsonLogger(context.Background(), os.Stdout, "App", "Scope", "", LOG, nil)
+	assert.NotNil(t, stdout.Rotate())
+}
+
+func TestFileWriterFsync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	writer, err := NewFileWriter(FileWriterConfiguration{Path: path, Fsync: FsyncEveryWrite})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte("granted\n"))
+	assert.Nil(t, err)
+	assert.False(t, writer.dirty)
+	assert.Nil(t, writer.Close())
+
+	writer, err = NewFileWriter(FileWriterConfiguration{Path: path, Fsync: FsyncInterval, FsyncInterval: 10 * time.Millisecond})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	// a second writer on the same path stands for another process appending to it
+	other, err := NewFileWriter(FileWriterConfiguration{Path: path})
+	assert.Nil(t, err)
+	defer other.Close()
+
+	_, _ = writer.Write([]byte("revoked\n"))
+	_, _ = other.Write([]byte("other\n"))
+	_, _ = writer.Write([]byte("expired\n"))
+	assert.Eventually(t, func() bool {
+		writer.mu.Lock()
+		defer writer.mu.Unlock()
+		return !writer.dirty
+	}, time.Second, 5*time.Millisecond, "synced by the interval policy")
+	assert.Equal(t, "granted\nrevoked\nother\nexpired\n", readFile(t, path))
+
+	_, err = NewFileWriter(FileWriterConfiguration{Path: path, Fsync: "sometimes"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/pixie-sh/logger-go/caller"
+	"regexp"
+	"strings"
+)
+
+var (
+	// matches fmt error output like %!v(MISSING), %!d(string=x) or %!(EXTRA int=1)
+	brokenFormatRegex = regexp.MustCompile(`%!(\w?\([^)]*\)|\w)`)
+
+	// matches a formatting verb right after %, after + or #, or after flags followed by a width or precision.
+	// the space flag isn't matched, so prose like "100% done" or "50% off" isn't taken for verbs
+	formatVerbRegex = regexp.MustCompile(`%([+#]?|[-+#0]*((\d+|\*)(\.(\d+|\*)?)?|\.(\d+|\*)?))[vTtbcdoOqxXUeEfFgGsp]`)
+)
+
+// StrictModeError message interpolated while the logger is in strict structured mode
+type StrictModeError struct {
+	Format string
+}
+
+// Error returns the violation description
+func (e StrictModeError) Error() string {
+	return fmt.Sprintf("strict mode: message %q must be constant, move the data into fields", e.Format)
+}
+
+// preformatted message built by the library's own helpers, eg: Println or Timer, from data already
+// in fields or given as values rather than as a format, logged as is and never reported in strict mode
+type preformatted string
+
+// logPreformatted logs msg as is at level with call as caller
+func logPreformatted(log Interface, level LogLevelEnum, call caller.Ptr, msg string) {
+	logWithCaller(log, level, call, "%s", preformatted(msg))
+}
+
+// format expands the message and, outside prod scopes, reports obviously broken formatting
+// and interpolation in strict mode
+func (i *JsonLogger) format(format string, args ...any) (msg string, warning string) {
+	if len(args) == 1 && format == "%s" {
+		if msg, ok := args[0].(preformatted); ok {
+			return string(msg), ""
+		}
+	}
+
+	msg = format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	if i.isProdScope() {
+		return msg, ""
+	}
+
+	if i.Strict && (len(args) > 0 || hasFormatVerbs(format)) {
+		reportDiagnostic(StrictModeError{Format: format})
+	}
+
+	return msg, formatWarning(format, msg, args)
+}
+
+func hasFormatVerbs(format string) bool {
+	return formatVerbRegex.MatchString(strings.ReplaceAll(format, "%%", ""))
+}
+
+func formatWarning(format string, msg string, args []any) string {
+	if len(args) > 0 {
+		broken := brokenFormatRegex.FindAllString(msg, -1)
+		if len(broken) == 0 {
+			return ""
+		}
+
+		return fmt.Sprintf("mismatched format verbs and arguments: %s", strings.Join(broken, ", "))
+	}
+
+	if hasFormatVerbs(format) {
+		return "format verbs without arguments"
+	}
+
+	return ""
+}
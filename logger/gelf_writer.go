@@ -0,0 +1,330 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gelf defaults and limits
+const (
+	DefaultGELFChunkSize = 1420
+	GELFMaxChunks        = 128
+	gelfVersion          = "1.1"
+)
+
+// gelf chunked message magic bytes
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// supported gelf udp compressions
+const (
+	GELFGzip = "gzip"
+	GELFZlib = "zlib"
+	GELFNone = "none"
+)
+
+// GELFWriterConfiguration graylog gelf sink configuration
+type GELFWriterConfiguration struct {
+	// Network udp, the default, or tcp
+	Network string `toml:"network" json:"network" mapstructure:"network"`
+	Address string `toml:"address" json:"address" mapstructure:"address"`
+
+	// Host gelf host of the messages, defaults to the hostname
+	Host string `toml:"host" json:"host" mapstructure:"host"`
+
+	// Compression of udp messages: gzip, the default, zlib or none. tcp messages aren't compressed
+	Compression string `toml:"compression" json:"compression" mapstructure:"compression"`
+
+	// ChunkSize udp datagram size above which messages are chunked, defaults to DefaultGELFChunkSize
+	ChunkSize int           `toml:"chunkSize" json:"chunkSize" mapstructure:"chunkSize"`
+	Timeout   time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+}
+
+// GELFLoggerConfiguration logger sending gelf messages
+type GELFLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	GELFWriterConfiguration `mapstructure:",squash"`
+}
+
+// GELFEncoder encodes entries as gelf 1.1 messages: message as short_message, the level as syslog severity
+// and every other field as an additional field, nested fields flattened with _, eg: _http_method
+type GELFEncoder struct {
+	Host string
+}
+
+// Encode renders the entry as a gelf message
+func (e GELFEncoder) Encode(entry map[string]any) ([]byte, error) {
+	host := e.Host
+	if value, ok := entry[HostField]; ok {
+		host = fieldString(value)
+	}
+
+	severity := SyslogSeverity(LOG)
+	if level, err := ParseLogLevel(fmt.Sprint(entry["level"])); err == nil {
+		severity = SyslogSeverity(level)
+	}
+
+	timestamp := time.Now()
+	if t, err := time.Parse(time.RFC3339Nano, fmt.Sprint(entry["timestamp"])); err == nil {
+		timestamp = t
+	}
+
+	message := map[string]any{
+		"version":       gelfVersion,
+		"host":          host,
+		"short_message": fmt.Sprint(entry["message"]),
+		"timestamp":     float64(timestamp.UnixMilli()) / 1000,
+		"level":         severity,
+	}
+
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch key {
+		case "message", "timestamp", HostField:
+			continue
+		}
+
+		if err := gelfFields(message, gelfFieldName(key), entry[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(message)
+}
+
+// gelfFields adds value as _name, flattening groups and objects with _ separators.
+// gelf additional fields only hold strings and numbers, other values are json encoded
+func gelfFields(message map[string]any, name string, value any) error {
+	var nested map[string]any
+	switch v := value.(type) {
+	case fieldGroup:
+		nested = v
+	case map[string]any:
+		nested = v
+	}
+
+	if nested != nil {
+		for key, v := range nested {
+			if err := gelfFields(message, name+"_"+gelfFieldName(key), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if name == "id" {
+		name = "id_"
+	}
+
+	switch v := value.(type) {
+	case string, float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		message["_"+name] = v
+	case bool:
+		message["_"+name] = fmt.Sprint(v)
+	default:
+		text, err := textValue(v)
+		if err != nil {
+			return err
+		}
+		message["_"+name] = text
+	}
+
+	return nil
+}
+
+// gelfFieldName replaces the characters gelf field names don't allow with _
+func gelfFieldName(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// GELFWriter sends GELFEncoder messages to graylog, compressed and chunked over udp, null byte delimited over tcp
+type GELFWriter struct {
+	cfg    GELFWriterConfiguration
+	stream bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewGELFWriter connects to the gelf input at cfg.Address
+func NewGELFWriter(cfg GELFWriterConfiguration) (*GELFWriter, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("gelf writer requires an Address")
+	}
+
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+
+	switch cfg.Compression {
+	case "":
+		cfg.Compression = GELFGzip
+	case GELFGzip, GELFZlib, GELFNone:
+	default:
+		return nil, fmt.Errorf("unknown gelf compression %s", cfg.Compression)
+	}
+
+	if cfg.ChunkSize <= len(gelfChunkMagic)+10 {
+		cfg.ChunkSize = DefaultGELFChunkSize
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	conn, err := net.DialTimeout(cfg.Network, cfg.Address, cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GELFWriter{cfg: cfg, conn: conn, stream: !strings.HasPrefix(cfg.Network, "udp")}, nil
+}
+
+// Write sends the message p
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.cfg.Timeout)); err != nil {
+		return 0, err
+	}
+
+	message := bytes.TrimRight(p, "\n")
+	if w.stream {
+		// framed in a copy, p belongs to the caller
+		frame := make([]byte, len(message)+1)
+		copy(frame, message)
+		if _, err := w.conn.Write(frame); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	datagrams, err := w.datagrams(message)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, datagram := range datagrams {
+		if _, err := w.conn.Write(datagram); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection
+func (w *GELFWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.conn.Close()
+}
+
+// datagrams compresses message and splits it in chunks when above the chunk size
+func (w *GELFWriter) datagrams(message []byte) ([][]byte, error) {
+	message, err := w.compress(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(message) <= w.cfg.ChunkSize {
+		return [][]byte{message}, nil
+	}
+
+	header := len(gelfChunkMagic) + 10
+	size := w.cfg.ChunkSize - header
+	count := (len(message) + size - 1) / size
+	if count > GELFMaxChunks {
+		return nil, fmt.Errorf("gelf message of %d bytes needs %d chunks, above %d", len(message), count, GELFMaxChunks)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, 0, count)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(message) {
+			end = len(message)
+		}
+
+		chunk := make([]byte, 0, header+end-i*size)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunks = append(chunks, append(chunk, message[i*size:end]...))
+	}
+
+	return chunks, nil
+}
+
+func (w *GELFWriter) compress(message []byte) ([]byte, error) {
+	var (
+		b  bytes.Buffer
+		cw io.WriteCloser
+	)
+	switch w.cfg.Compression {
+	case GELFGzip:
+		cw = gzip.NewWriter(&b)
+	case GELFZlib:
+		cw = zlib.NewWriter(&b)
+	default:
+		return message, nil
+	}
+
+	if _, err := cw.Write(message); err != nil {
+		return nil, err
+	}
+
+	if err := cw.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func createGELFLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg GELFLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Host == "" {
+		cfg.Host = hostname
+	}
+
+	writer, err := NewGELFWriter(cfg.GELFWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	cfg.Encoder = GELFEncoder{Host: cfg.Host}
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
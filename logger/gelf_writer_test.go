@@ -0,0 +1,151 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGELFDriverUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   GELFLoggerDriver,
+		Values:   map[string]any{"address": conn.LocalAddr().String(), "host": "web-1", "chunkSize": 512},
+	})
+	assert.Nil(t, err)
+	defer log.(Syncer).Close()
+
+	log.WithGroup("http").With("method", "GET").With("id", 7).Warn("slow request")
+	log.With("payload", strings.Repeat("x", 4096)).Log("large")
+
+	message := readGELFDatagrams(t, conn)
+	assert.Equal(t, "1.1", message["version"])
+	assert.Equal(t, "web-1", message["host"])
+	assert.Equal(t, "slow request", message["short_message"])
+	assert.Equal(t, float64(4), message["level"])
+	assert.Equal(t, "App", message["_app"])
+	assert.Equal(t, "GET", message["_http_method"])
+	assert.Equal(t, float64(7), message["_http_id"])
+	assert.NotContains(t, message, "_message")
+
+	message = readGELFDatagrams(t, conn)
+	assert.Equal(t, "large", message["short_message"])
+	assert.Len(t, message["_payload"], 4096)
+}
+
+func TestGELFWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		message, _ := bufio.NewReader(conn).ReadString(0)
+		received <- message
+	}()
+
+	writer, err := NewGELFWriter(GELFWriterConfiguration{Network: "tcp", Address: listener.Addr().String()})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	line := []byte(`{"short_message":"a"}` + "\n")
+	_, err = writer.Write(line)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"short_message":"a"}`+"\x00", <-received)
+	assert.Equal(t, `{"short_message":"a"}`+"\n", string(line), "the written line is left untouched")
+}
+
+func TestGELFFieldName(t *testing.T) {
+	assert.Equal(t, "user_name.first-x", gelfFieldName("user name.first-x"))
+	_, err := NewGELFWriter(GELFWriterConfiguration{Address: "127.0.0.1:1", Compression: "lz4"})
+	assert.Error(t, err)
+}
+
+// readGELFDatagrams reads the datagrams of one message, reassembling chunks, and decodes it
+func readGELFDatagrams(t *testing.T, conn net.PacketConn) map[string]any {
+	var (
+		chunks  [][]byte
+		payload []byte
+	)
+	buf := make([]byte, 65536)
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		assert.Nil(t, err)
+
+		datagram := append([]byte(nil), buf[:n]...)
+		if !bytes.HasPrefix(datagram, gelfChunkMagic) {
+			payload = datagram
+			break
+		}
+
+		assert.LessOrEqual(t, n, 512)
+		if chunks == nil {
+			chunks = make([][]byte, datagram[11])
+		}
+		chunks[datagram[10]] = datagram[12:]
+		if int(datagram[10]) == len(chunks)-1 {
+			payload = bytes.Join(chunks, nil)
+			break
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(payload))
+	assert.Nil(t, err)
+	data, err := io.ReadAll(gz)
+	assert.Nil(t, err)
+
+	var message map[string]any
+	assert.Nil(t, json.Unmarshal(data, &message))
+	return message
+}
+
+func TestGELFChunks(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	writer, err := NewGELFWriter(GELFWriterConfiguration{Address: conn.LocalAddr().String(), Compression: GELFNone, ChunkSize: 100})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	message := []byte(strings.Repeat("m", 250))
+	chunks, err := writer.datagrams(message)
+	assert.Nil(t, err)
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, chunks[0][2:10], chunks[2][2:10])
+	assert.Equal(t, []byte{2, 3}, chunks[2][10:12])
+
+	var joined []byte
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), 100)
+		joined = append(joined, chunk[12:]...)
+	}
+	assert.Equal(t, message, joined)
+
+	_, err = writer.datagrams(make([]byte, 100*GELFMaxChunks))
+	assert.Error(t, err)
+}
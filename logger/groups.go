@@ -0,0 +1,54 @@
+package logger
+
+// fieldGroup nested fields namespace created by WithGroup
+type fieldGroup map[string]any
+
+// copyFields copies fields, including nested groups, so copies can be changed independently
+func copyFields(fields map[string]any) map[string]any {
+	copied := make(map[string]any, len(fields))
+	for k, v := range fields {
+		if group, ok := v.(fieldGroup); ok {
+			v = fieldGroup(copyFields(group))
+		}
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// groupFields returns the fields map of the current group, creating the nested groups
+// as needed. must be called with the lock held
+func (i *innerJsonLog) groupFields() map[string]any {
+	fields := i.fields
+	for _, name := range i.group {
+		group, ok := fields[name].(fieldGroup)
+		if !ok {
+			group = fieldGroup{}
+			fields[name] = group
+		}
+		fields = group
+	}
+
+	return fields
+}
+
+// WithGroup returns a copy of the logger whose subsequent fields are nested under name
+func (i *innerJsonLog) WithGroup(name string) Interface {
+	segment := i.clone()
+	if name != "" {
+		segment.group = append(segment.group, name)
+	}
+
+	return segment
+}
+
+// WithGroup returns a logger whose fields are nested under name
+func (i *JsonLogger) WithGroup(name string) Interface {
+	segment := i.segment(nil)
+
+	if name != "" {
+		segment.group = []string{name}
+	}
+
+	return segment
+}
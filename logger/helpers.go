@@ -0,0 +1,37 @@
+package logger
+
+import "github.com/pixie-sh/logger-go/caller"
+
+// callerLogger implemented by loggers able to log with an explicit caller,
+// so helpers wrapping them report their own caller instead of the helper
+type callerLogger interface {
+	logWithCaller(level LogLevelEnum, call caller.Ptr, format string, args ...any)
+}
+
+// logWithCaller logs at level with call as caller when supported by log
+func logWithCaller(log Interface, level LogLevelEnum, call caller.Ptr, format string, args ...any) {
+	if cl, ok := log.(callerLogger); ok {
+		cl.logWithCaller(level, call, format, args...)
+		return
+	}
+
+	switch level {
+	case ERROR:
+		log.Error(format, args...)
+	case WARN:
+		log.Warn(format, args...)
+	case DEBUG:
+		log.Debug(format, args...)
+	default:
+		log.Log(format, args...)
+	}
+}
+
+func (i *JsonLogger) logWithCaller(level LogLevelEnum, call caller.Ptr, format string, args ...any) {
+	i.log(level, call, format, args...)
+}
+
+func (i *innerJsonLog) logWithCaller(level LogLevelEnum, call caller.Ptr, format string, args ...any) {
+	i.setCaller(call)
+	i.log(level, format, args...)
+}
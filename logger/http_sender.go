@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPSinkConfiguration configuration shared by the sinks posting batches over http
+type HTTPSinkConfiguration struct {
+	// Client defaults to a client with the Timeout
+	Client  *http.Client
+	URL     string            `toml:"url" json:"url" mapstructure:"url"`
+	Headers map[string]string `toml:"headers" json:"headers" mapstructure:"headers"`
+
+	// Gzip compresses the request bodies
+	Gzip bool `toml:"gzip" json:"gzip" mapstructure:"gzip"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+	Timeout       time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// MaxRetries retries of requests failed with 429, 5xx or a transport error, backing off from RetryBackoff
+	MaxRetries   int           `toml:"maxRetries" json:"maxRetries" mapstructure:"maxRetries"`
+	RetryBackoff time.Duration `toml:"retryBackoff" json:"retryBackoff" mapstructure:"retryBackoff"`
+
+	// DeadLetter spills the batches failed after the retries to disk, replaying them once delivery recovers
+	DeadLetter DeadLetterConfiguration `toml:"deadLetter" json:"deadLetter" mapstructure:"deadLetter"`
+}
+
+// HTTPStatusError request answered with an unexpected status
+type HTTPStatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+// Error returns the status description
+func (e HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s answered %d: %s", e.URL, e.StatusCode, e.Body)
+}
+
+// httpSender posts request bodies with the HTTPSinkConfiguration headers, compression and retries
+type httpSender struct {
+	cfg HTTPSinkConfiguration
+}
+
+// newHTTPSender validates cfg and applies its defaults
+func newHTTPSender(cfg HTTPSinkConfiguration) (*httpSender, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("http sink requires an URL")
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	return &httpSender{cfg: cfg}, nil
+}
+
+// send posts body to url, the configured URL when empty, with header on top of the configured headers.
+// it returns the response body
+func (s *httpSender) send(url string, header http.Header, body []byte) ([]byte, error) {
+	if url == "" {
+		url = s.cfg.URL
+	}
+
+	if s.cfg.Gzip {
+		var b bytes.Buffer
+		gz := gzip.NewWriter(&b)
+		_, _ = gz.Write(body)
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+		body = b.Bytes()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout*time.Duration(s.cfg.MaxRetries+1))
+	defer cancel()
+
+	var response []byte
+	err := retry(ctx, s.cfg.MaxRetries, s.cfg.RetryBackoff, httpRetryable, func() error {
+		var err error
+		response, err = s.post(ctx, url, header, body)
+		return err
+	})
+
+	return response, err
+}
+
+func (s *httpSender) post(ctx context.Context, url string, header http.Header, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range s.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	if s.cfg.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return io.ReadAll(resp.Body)
+	}
+
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, HTTPStatusError{URL: url, StatusCode: resp.StatusCode, Body: string(text)}
+}
+
+// httpRetryable transport errors, 429 and 5xx
+func httpRetryable(err error) bool {
+	var status HTTPStatusError
+	if errors.As(err, &status) {
+		return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+	}
+	return true
+}
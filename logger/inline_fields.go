@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+)
+
+// inlineable check if f can be appended straight to a json entry, without boxing it into the entry map
+func inlineable(f Field) bool {
+	if len(f.group) > 0 {
+		return false
+	}
+
+	switch f.Type {
+	case StringType, IntType, BoolType, DurationType, TimeType:
+		return true
+	case FloatType:
+		v := math.Float64frombits(uint64(f.Integer))
+		return !math.IsNaN(v) && !math.IsInf(v, 0)
+	default:
+		return false
+	}
+}
+
+// inlinedFields returns the typed fields appended straight to the encoded entry, nil when the entry isn't
+// json encoded or a typed key collides with another field, those entries box every typed field into the map
+// so the DuplicateKeys policy resolves them in order. callers must hold the read lock
+func (i *innerJsonLog) inlinedFields() []Field {
+	if len(i.typed) == 0 || !i.jsonEncoded() {
+		return nil
+	}
+
+	for n, f := range i.typed {
+		if !inlineable(f) {
+			continue
+		}
+
+		if _, exists := i.fields[f.Key]; exists {
+			return nil
+		}
+
+		for m, other := range i.typed {
+			if m != n && (len(other.group) > 0 && other.group[0] == f.Key || len(other.group) == 0 && other.Key == f.Key) {
+				return nil
+			}
+		}
+	}
+
+	return i.typed
+}
+
+// isInlined check if f of inlined is appended straight to the entry, a key taken by the logger own fields isn't
+func isInlined(f Field, logEntry map[string]any) bool {
+	if !inlineable(f) {
+		return false
+	}
+
+	_, exists := logEntry[f.Key]
+	return !exists
+}
+
+// resolveInlined resolves the inlined fields colliding with the logger own fields with the DuplicateKeys policy,
+// as if they were set before them
+func (i *innerJsonLog) resolveInlined(logEntry map[string]any, inlined []Field) {
+	for _, f := range inlined {
+		if !inlineable(f) {
+			continue
+		}
+
+		if _, exists := logEntry[f.Key]; !exists {
+			continue
+		}
+
+		switch i.DuplicateKeys {
+		case FirstKeyWins:
+			logEntry[f.Key] = f.Value()
+		case SuffixDuplicateKeys:
+			logEntry[freeKey(logEntry, f.Key)] = f.Value()
+		}
+	}
+}
+
+// appendInlined appends the inlined fields to the json object encoded in line
+func appendInlined(line []byte, logEntry map[string]any, inlined []Field) []byte {
+	if len(line) == 0 || line[len(line)-1] != '}' {
+		return line
+	}
+
+	line = line[:len(line)-1]
+	for _, f := range inlined {
+		if !isInlined(f, logEntry) {
+			continue
+		}
+
+		if line[len(line)-1] != '{' {
+			line = append(line, ',')
+		}
+
+		line = appendJSONString(line, f.Key)
+		line = append(line, ':')
+		line = appendFieldJSON(line, f)
+	}
+
+	return append(line, '}')
+}
+
+// appendFieldJSON appends the json value of an inlineable field, as encoding/json encodes it
+func appendFieldJSON(dst []byte, f Field) []byte {
+	switch f.Type {
+	case StringType:
+		return appendJSONString(dst, f.Str)
+	case IntType, DurationType:
+		return strconv.AppendInt(dst, f.Integer, 10)
+	case FloatType:
+		return appendJSONFloat(dst, math.Float64frombits(uint64(f.Integer)))
+	case BoolType:
+		return strconv.AppendBool(dst, f.Integer == 1)
+	case TimeType:
+		dst = append(dst, '"')
+		dst = time.Unix(0, f.Integer).In(f.Interface.(*time.Location)).AppendFormat(dst, time.RFC3339Nano)
+		return append(dst, '"')
+	default:
+		return append(dst, "null"...)
+	}
+}
+
+// appendJSONFloat appends v formatted like encoding/json
+func appendJSONFloat(dst []byte, v float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+
+	dst = strconv.AppendFloat(dst, v, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		n := len(dst)
+		if n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+
+	return dst
+}
+
+// appendJSONString appends s quoted, strings needing escapes go through encoding/json
+func appendJSONString(dst []byte, s string) []byte {
+	for n := 0; n < len(s); n++ {
+		if c := s[n]; c < 0x20 || c >= 0x80 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			quoted, _ := json.Marshal(s)
+			return append(dst, quoted...)
+		}
+	}
+
+	dst = append(dst, '"')
+	dst = append(dst, s...)
+	return append(dst, '"')
+}
+
+// hasInlined check if key is one of the inlined fields
+func hasInlined(inlined []Field, key string) bool {
+	for _, f := range inlined {
+		if f.Key == key && inlineable(f) {
+			return true
+		}
+	}
+
+	return false
+}
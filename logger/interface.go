@@ -1,12 +1,16 @@
 package logger
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // LogLevelEnum is an enum to represent log levels.
 type LogLevelEnum int
 
 const (
-	ERROR LogLevelEnum = iota
+	FATAL LogLevelEnum = iota - 1
+	ERROR
 	WARN
 	LOG
 	DEBUG
@@ -15,6 +19,8 @@ const (
 // String returns the string representation of the LogLevelEnum.
 func (l LogLevelEnum) String() string {
 	switch l {
+	case FATAL:
+		return "FATAL"
 	case ERROR:
 		return "ERROR"
 	case WARN:
@@ -29,12 +35,34 @@ func (l LogLevelEnum) String() string {
 }
 
 // Interface LoggerInterface represents the basic logging interface.
+// the singleton and helpers rely on every method, see NopLogger for a minimal implementation.
 type Interface interface {
 	Clone() Interface
 	WithCtx(ctx context.Context) Interface
 	With(field string, value any) Interface
+	WithKV(keyvals ...any) Interface
+	WithError(err error) Interface
+	WithF(fields ...Field) Interface
+	WithGroup(name string) Interface
+	WithScope(scope string) Interface
+	Named(name string) Interface
+	WithApp(app string) Interface
+	WithUID(uid string) Interface
+	WithWriter(writer io.Writer) Interface
+	WithLevel(level LogLevelEnum) Interface
+	WithSamplingHint(always bool) Interface
+	Event(level LogLevelEnum) *Event
+	Enabled(level LogLevelEnum) bool
 	Log(format string, args ...any)
+	Printf(format string, args ...any)
+	Println(v ...any)
+	LogAt(level LogLevelEnum, format string, args ...any)
+	LogWith(level LogLevelEnum, msg string, fields ...Field)
 	Error(format string, args ...any)
 	Warn(format string, args ...any)
 	Debug(format string, args ...any)
+	Err(err error, format string, args ...any)
+	Fatal(format string, args ...any)
+	Fatalf(format string, args ...any)
+	Panic(format string, args ...any)
 }
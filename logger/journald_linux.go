@@ -0,0 +1,226 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"golang.org/x/sys/unix"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// DefaultJournaldSocket systemd-journald native protocol socket
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+func init() {
+	DefaultFactoryConfiguration.Mapping[JournaldLoggerDriver] = createJournaldLogger
+}
+
+// JournaldConfiguration systemd-journald sink configuration
+type JournaldConfiguration struct {
+	// Socket journald socket, defaults to DefaultJournaldSocket
+	Socket string `toml:"socket" json:"socket" mapstructure:"socket"`
+
+	// Identifier SYSLOG_IDENTIFIER of the entries, defaults to the logger app
+	Identifier string `toml:"identifier" json:"identifier" mapstructure:"identifier"`
+}
+
+// JournaldLoggerConfiguration logger writing native journal entries
+type JournaldLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	JournaldConfiguration   `mapstructure:",squash"`
+}
+
+// JournalEncoder encodes entries as native journal fields: MESSAGE, PRIORITY (the syslog severity of the level),
+// SYSLOG_IDENTIFIER and every other field uppercased, groups flattened with _, eg: HTTP_METHOD
+type JournalEncoder struct {
+	Identifier string
+}
+
+// Encode renders the entry in the journal native protocol, without the final newline added by the logger
+func (e JournalEncoder) Encode(entry map[string]any) ([]byte, error) {
+	fields := make(map[string]any, len(entry))
+	for key, value := range entry {
+		flattenGroup(fields, key, value)
+	}
+
+	var b bytes.Buffer
+	priority := SyslogSeverity(LOG)
+	if level, err := ParseLogLevel(fmt.Sprint(entry["level"])); err == nil {
+		priority = SyslogSeverity(level)
+	}
+	writeJournalField(&b, "PRIORITY", strconv.Itoa(priority))
+
+	identifier := e.Identifier
+	if identifier == "" {
+		identifier = fmt.Sprint(entry["app"])
+	}
+	writeJournalField(&b, "SYSLOG_IDENTIFIER", identifier)
+	writeJournalField(&b, "MESSAGE", fmt.Sprint(entry["message"]))
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key != "message" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := journalFieldName(key)
+		if name == "" {
+			continue
+		}
+
+		value, err := textValue(fields[key])
+		if err != nil {
+			return nil, err
+		}
+		writeJournalField(&b, name, value)
+	}
+
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
+// journalFieldName uppercases key, replacing invalid characters with _. leading _ are trusted fields, removed
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, "_")
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		name = "F" + name
+	}
+
+	if len(name) > 64 {
+		name = name[:64]
+	}
+
+	return name
+}
+
+// writeJournalField writes KEY=value, or the binary form for values with newlines
+func writeJournalField(b *bytes.Buffer, name string, value string) {
+	if !strings.Contains(value, "\n") {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+
+	b.WriteString(name)
+	b.WriteByte('\n')
+	_ = binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// JournaldWriter sends JournalEncoder entries to journald, one datagram per entry.
+// entries above the socket datagram limit are passed through a sealed memfd
+type JournaldWriter struct {
+	conn *net.UnixConn
+	addr *net.UnixAddr
+}
+
+// NewJournaldWriter connects to the journald socket
+func NewJournaldWriter(cfg JournaldConfiguration) (*JournaldWriter, error) {
+	if cfg.Socket == "" {
+		cfg.Socket = DefaultJournaldSocket
+	}
+
+	if _, err := os.Stat(cfg.Socket); err != nil {
+		return nil, err
+	}
+
+	// unconnected and autobound, file descriptors can't be passed on connected datagram sockets
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &JournaldWriter{conn: conn, addr: &net.UnixAddr{Name: cfg.Socket, Net: "unixgram"}}, nil
+}
+
+// Write sends the entry p
+func (w *JournaldWriter) Write(p []byte) (int, error) {
+	_, err := w.conn.WriteToUnix(p, w.addr)
+	if err == nil {
+		return len(p), nil
+	}
+
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return 0, err
+	}
+
+	if err := w.writeMemfd(p); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection
+func (w *JournaldWriter) Close() error {
+	return w.conn.Close()
+}
+
+func (w *JournaldWriter) writeMemfd(p []byte) error {
+	fd, err := unix.MemfdCreate("journal-entry", unix.MFD_ALLOW_SEALING|unix.MFD_CLOEXEC)
+	if err != nil {
+		return err
+	}
+
+	file := os.NewFile(uintptr(fd), "journal-entry")
+	defer file.Close()
+
+	if _, err := file.Write(p); err != nil {
+		return err
+	}
+
+	seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+	if _, err := unix.FcntlInt(file.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+		return err
+	}
+
+	_, _, err = w.conn.WriteMsgUnix(nil, unix.UnixRights(int(file.Fd())), w.addr)
+	return err
+}
+
+func createJournaldLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg JournaldLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Identifier == "" {
+		cfg.Identifier = generic.App
+	}
+
+	writer, err := NewJournaldWriter(cfg.JournaldConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	cfg.Encoder = JournalEncoder{Identifier: cfg.Identifier}
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,94 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listenJournald(t *testing.T) (*net.UnixConn, string) {
+	socket := filepath.Join(t.TempDir(), "journal.socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn, socket
+}
+
+func TestJournaldDriver(t *testing.T) {
+	conn, socket := listenJournald(t)
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   JournaldLoggerDriver,
+		Values:   map[string]any{"socket": socket},
+	})
+	assert.Nil(t, err)
+	defer log.(Syncer).Close()
+
+	log.WithGroup("http").With("method", "GET").With("note", "two\nlines").Warn("slow request")
+
+	buf := make([]byte, 64*1024)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	assert.Nil(t, err)
+
+	entry := string(buf[:n])
+	assert.Contains(t, entry, "PRIORITY=4\n")
+	assert.Contains(t, entry, "SYSLOG_IDENTIFIER=App\n")
+	assert.Contains(t, entry, "MESSAGE=slow request\n")
+	assert.Contains(t, entry, "HTTP_METHOD=GET\n")
+	assert.Contains(t, entry, "SCOPE=Scope\n")
+
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len("two\nlines")))
+	assert.Contains(t, entry, "HTTP_NOTE\n"+string(size[:])+"two\nlines\n")
+	assert.True(t, strings.HasSuffix(entry, "\n"))
+	assert.False(t, strings.HasSuffix(entry, "\n\n"))
+}
+
+func TestJournaldWriterMemfd(t *testing.T) {
+	conn, socket := listenJournald(t)
+
+	writer, err := NewJournaldWriter(JournaldConfiguration{Socket: socket})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	entry := append([]byte("MESSAGE="), bytes.Repeat([]byte("x"), 4*1024*1024)...)
+	entry = append(entry, '\n')
+	_, err = writer.Write(entry)
+	assert.Nil(t, err)
+
+	oob := make([]byte, unix.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, oobn, _, _, err := conn.ReadMsgUnix(make([]byte, 16), oob)
+	assert.Nil(t, err)
+
+	messages, err := unix.ParseSocketControlMessage(oob[:oobn])
+	assert.Nil(t, err)
+	fds, err := unix.ParseUnixRights(&messages[0])
+	assert.Nil(t, err)
+
+	file := os.NewFile(uintptr(fds[0]), "memfd")
+	defer file.Close()
+	_, _ = file.Seek(0, io.SeekStart)
+	received, err := io.ReadAll(file)
+	assert.Nil(t, err)
+	assert.Equal(t, entry, received)
+}
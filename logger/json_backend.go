@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// JSONBackend name of a registered json marshaling implementation
+type JSONBackend string
+
+// StdlibJSONBackend encoding/json, always available
+const StdlibJSONBackend JSONBackend = "stdlib"
+
+// JSONMarshalFunc json marshaling implementation
+type JSONMarshalFunc func(v any) ([]byte, error)
+
+var (
+	jsonBackendsMu sync.RWMutex
+	jsonBackends   = map[JSONBackend]JSONMarshalFunc{
+		StdlibJSONBackend: json.Marshal,
+	}
+
+	// defaultJSONBackend used when none is configured, replaced by build tags, eg: logger_goccy
+	defaultJSONBackend = StdlibJSONBackend
+)
+
+// RegisterJSONBackend registers a json backend under name, replacing any previous one
+func RegisterJSONBackend(name JSONBackend, marshal JSONMarshalFunc) {
+	jsonBackendsMu.Lock()
+	defer jsonBackendsMu.Unlock()
+
+	jsonBackends[name] = marshal
+}
+
+// jsonBackend returns the marshal function of name, the default backend when name is empty
+func jsonBackend(name JSONBackend) (JSONMarshalFunc, error) {
+	if name == "" {
+		name = defaultJSONBackend
+	}
+
+	jsonBackendsMu.RLock()
+	defer jsonBackendsMu.RUnlock()
+
+	marshal, ok := jsonBackends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown json backend %s", name)
+	}
+
+	return marshal, nil
+}
@@ -0,0 +1,13 @@
+//go:build logger_goccy
+
+package logger
+
+import "github.com/goccy/go-json"
+
+// GoccyJSONBackend github.com/goccy/go-json, available with the logger_goccy build tag
+const GoccyJSONBackend JSONBackend = "goccy"
+
+func init() {
+	RegisterJSONBackend(GoccyJSONBackend, json.Marshal)
+	defaultJSONBackend = GoccyJSONBackend
+}
@@ -2,33 +2,79 @@ package logger
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/pixie-sh/logger-go/caller"
-	"github.com/pixie-sh/logger-go/structs"
+	"github.com/pixie-sh/logger-go/env"
 	"io"
-	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // JsonLogger represents a logger that outputs JSON logs.
 type JsonLogger struct {
-	App               string
-	Scope             string
-	UID               string
-	LogLevel          LogLevelEnum
+	App   string
+	Scope string
+	UID   string
+	// Name dot separated component path set by Named, written as the logger field
+	Name            string
+	LogLevel        LogLevelEnum
+	ErrorSerializer ErrorSerializer
+	ErrorSampler    *ErrorSampler
+	EntryID         bool
+	Sequence        bool
+	HostFields      bool
+
+	TimestampPrecision TimestampPrecision
+	UTC                bool
+	LocalTime          bool
+	SchemaVersion      SchemaVersion
+	Strict             bool
+	SizeField          bool
+	SizeRecorder       SizeRecorder
+	Encoder            Encoder
+	LevelFormat        LevelFormat
+	LevelNames         map[LogLevelEnum]string
+	LevelNum           bool
+	ErrorStack         bool
+	DuplicateKeys      DuplicateKeyPolicy
+
+	// Levels when set, drives the level at runtime instead of LogLevel, see NewAdminHandler
+	Levels *LevelController
+
+	// RequiredFields fields every entry of a scope must carry, keyed by scope
+	RequiredFields    map[string][]string
 	writer            io.Writer
 	expectedCtxFields []string
+
+	// sequence shared with clones and children, so entries are numbered per logger
+	sequence *atomic.Uint64
+
+	// writeMu shared with every logger of the tree writing to the same writer
+	writeMu *sync.Mutex
+	locks   *writeLocks
+
+	// envScope scope of the logger the Named and WithScope ones derive from, checked for prod
+	envScope string
 }
 
+// compile time check both implementations satisfy the full Interface
+var (
+	_ Interface = (*JsonLogger)(nil)
+	_ Interface = (*innerJsonLog)(nil)
+)
+
 // innerJsonLog represents a logger with additional fields.
 type innerJsonLog struct {
 	*JsonLogger
 
 	mu                sync.RWMutex
 	Ctx               context.Context
+	caller            caller.Ptr
+	sampling          samplingHint
 	fields            map[string]any
+	typed             []Field
+	group             []string
 	expectedCtxFields []string
 }
 
@@ -36,10 +82,26 @@ func (i *innerJsonLog) With(field string, value any) Interface {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
-	i.fields[field] = value
+	i.putField(i.groupFields(), field, value)
 	return i
 }
 
+// set adds a top level field, regardless of the current group
+func (i *innerJsonLog) set(field string, value any) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.fields[field] = value
+}
+
+// setCaller sets the caller of the next entry
+func (i *innerJsonLog) setCaller(call caller.Ptr) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.caller = call
+}
+
 // WithCtx adds ctx to fields
 func (i *innerJsonLog) WithCtx(ctx context.Context) Interface {
 	i.mu.Lock()
@@ -50,139 +112,193 @@ func (i *innerJsonLog) WithCtx(ctx context.Context) Interface {
 }
 
 func (i *innerJsonLog) Clone() Interface {
+	return i.clone()
+}
+
+func (i *innerJsonLog) clone() *innerJsonLog {
 	i.mu.RLock()
 	defer i.mu.RUnlock()
 
-	// Create a new map and copy all fields
-	newFields := make(map[string]any, len(i.fields))
-	for k, v := range i.fields {
-		newFields[k] = v
-	}
-
 	// Create a new innerJsonLog with copied fields
-	return &innerJsonLog{
-		JsonLogger:        i.JsonLogger,
-		Ctx:               i.Ctx,
-		fields:            newFields,
-		expectedCtxFields: i.expectedCtxFields,
-	}
+	segment := i.JsonLogger.segment(copyFields(i.fields))
+	segment.Ctx = i.Ctx
+	segment.expectedCtxFields = i.expectedCtxFields
+	segment.caller = i.caller
+	segment.sampling = i.sampling
+	segment.typed = append([]Field(nil), i.typed...)
+	segment.group = append([]string(nil), i.group...)
+	return segment
 }
 
 // Log logs a message at LOG level.
 func (i *innerJsonLog) Log(format string, args ...any) {
-	i.With("caller", caller.Upper())
+	i.setCaller(caller.Upper())
 	i.log(LOG, format, args...)
 }
 
+// LogAt logs a message at level, FATAL exits like Fatal.
+func (i *innerJsonLog) LogAt(level LogLevelEnum, format string, args ...any) {
+	i.setCaller(caller.Upper())
+	i.log(level, format, args...)
+	if level == FATAL {
+		i.exit()
+	}
+}
+
 // Error logs a message at ERROR level.
 func (i *innerJsonLog) Error(format string, args ...any) {
-	i.With("caller", caller.Upper())
+	i.setCaller(caller.Upper())
 	i.log(ERROR, format, args...)
 }
 
 // Warn logs a message at WARN level.
 func (i *innerJsonLog) Warn(format string, args ...any) {
-	i.With("caller", caller.Upper())
+	i.setCaller(caller.Upper())
 	i.log(WARN, format, args...)
 }
 
 // Debug logs a message at DEBUG level.
 func (i *innerJsonLog) Debug(format string, args ...any) {
-	i.With("caller", caller.Upper())
+	i.setCaller(caller.Upper())
 	i.log(DEBUG, format, args...)
 }
 
-// log is an internal method to log messages with structured logging.
+// Err logs a message at ERROR level with err attached, without adding it to the logger fields.
+func (i *innerJsonLog) Err(err error, format string, args ...any) {
+	segment := i.clone()
+	segment.setCaller(caller.Upper())
+	segment.set(ErrorField, err)
+	segment.log(ERROR, format, args...)
+}
+
+// Fatal logs a message at FATAL level, flushes every pending write and exits with ExitFunc(1).
+func (i *innerJsonLog) Fatal(format string, args ...any) {
+	segment := i.clone()
+	segment.setCaller(caller.Upper())
+	segment.log(FATAL, format, args...)
+	i.exit()
+}
+
+// log is the logger core, every entry, root or child, is built, encoded and written here
 func (i *innerJsonLog) log(level LogLevelEnum, format string, args ...any) {
-	if i.LogLevel < level {
+	if i.level() < level {
 		return
 	}
 
-	var logEntry = make(map[string]any)
-	var jsonLog []byte
-	var err error
-	var msg = format
-
-	if len(args) > 0 {
-		msg = fmt.Sprintf(format, args...)
-	}
+	buf := getLine()
+	defer putLine(buf)
 
 	{
 		i.mu.RLock()
 		defer i.mu.RUnlock()
 
-		for k, v := range i.fields {
-			if v == nil {
-				logEntry[k] = "nil"
-			} else {
-				switch v := v.(type) {
-				case error:
-					// Create a map to hold both struct values and error string
-					errorInfo := make(map[string]interface{})
-
-					// Always add the error string
-					errorInfo["errorString"] = v.Error()
-
-					// Try to unwrap the error
-					var innerErr interface{} = v
-					for {
-						u, ok := innerErr.(interface{ Unwrap() error })
-						if !ok {
-							break
-						}
-						innerErr = u.Unwrap()
-						if innerErr == nil {
-							break
-						}
-					}
-
-					// check if it's a fmt.Errorf type
-					if reflect.TypeOf(innerErr).String() != "*errors.errorString" {
-						// for other error types, try reflection
-						errorValue := reflect.ValueOf(innerErr)
-						if errorValue.Kind() == reflect.Ptr {
-							errorValue = errorValue.Elem()
-						}
-						if errorValue.Kind() == reflect.Struct {
-							for i := 0; i < errorValue.NumField(); i++ {
-								field := errorValue.Type().Field(i)
-								if field.IsExported() {
-									errorInfo[field.Name] = errorValue.Field(i).Interface()
-								}
-							}
-						}
-					}
-
-					logEntry[k] = errorInfo
-
-				default:
-					logEntry[k] = v
-				}
-			}
+		write, suppressed := i.sample(level, format)
+		if !write {
+			return
 		}
 
-		logEntry["timestamp"] = time.Now().Format(time.RFC3339)
-		logEntry["level"] = level.String()
-		logEntry["app"] = i.App
-		logEntry["scope"] = i.Scope
-		logEntry["message"] = msg
-
-		if i.UID != "" {
-			logEntry["uid"] = i.UID
+		msg, formatWarning := i.format(format, args...)
+		inlined := i.inlinedFields()
+		logEntry := i.entry(level, msg, inlined != nil)
+		if formatWarning != "" {
+			i.put(logEntry, FormatWarningField, formatWarning)
 		}
 
-		if i.Ctx != nil {
-			logEntry["ctx"] = i.ctxLog(i.Ctx)
+		if suppressed > 0 {
+			i.put(logEntry, SuppressedField, suppressed)
 		}
 
-		jsonLog, err = json.Marshal(logEntry)
+		i.resolveInlined(logEntry, inlined)
+		i.validateRequired(logEntry, inlined, msg)
+		line, err := i.appendEncode(*buf, logEntry)
 		if err != nil {
 			_, _ = fmt.Fprintf(i.writer, "Error marshaling log: %v", err)
 			return
 		}
+		line = appendInlined(line, logEntry, inlined)
+		*buf = append(i.account(line, level, i.caller), '\n')
 	}
 
-	_, _ = fmt.Fprintln(i.writer, string(jsonLog))
+	i.writeLine(level, *buf)
+	if level <= ERROR {
+		flushBuffered(i.writer)
+	}
+}
+
+// entry builds the entry fields, leaving out the inlined typed fields, callers must hold the read lock
+func (i *innerJsonLog) entry(level LogLevelEnum, msg string, inlined bool) map[string]any {
+	size := len(i.fields) + len(i.typed) + 8
+	if inlined {
+		for _, f := range i.typed {
+			if inlineable(f) {
+				size--
+			}
+		}
+	}
+
+	logEntry := make(map[string]any, size)
+	for k, v := range i.fields {
+		if group, ok := v.(fieldGroup); ok && len(group) == 0 {
+			continue
+		}
+
+		logEntry[k] = i.fieldValue(v)
+	}
+	i.addTypedFields(logEntry, inlined)
+
+	now := time.Now()
+	i.decorate(logEntry, now)
+
+	i.put(logEntry, "timestamp", i.timestamp(now))
+	i.put(logEntry, "level", i.levelValue(level))
+	if i.LevelNum {
+		i.put(logEntry, LevelNumField, int(level))
+	}
+	i.put(logEntry, "app", i.App)
+	i.put(logEntry, "scope", i.Scope)
+	i.put(logEntry, "message", msg)
+
+	if i.caller != nil {
+		i.put(logEntry, CallerField, i.caller)
+	}
+
+	if i.Name != "" {
+		i.put(logEntry, LoggerField, i.Name)
+	}
+
+	if i.UID != "" {
+		i.put(logEntry, "uid", i.UID)
+	}
+
+	if i.Ctx != nil {
+		i.put(logEntry, "ctx", i.ctxLog(i.Ctx))
+	}
+
+	return logEntry
+}
+
+// fieldValue converts a field value into its logged representation
+func (i *innerJsonLog) fieldValue(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return "nil"
+	case Lazy:
+		return i.fieldValue(v())
+	case LogMarshaler:
+		return marshalLog(v)
+	case errorChain:
+		return v.value()
+	case error:
+		return i.serializeError(v)
+	case fieldGroup:
+		group := make(fieldGroup, len(v))
+		for k, nested := range v {
+			group[k] = i.fieldValue(nested)
+		}
+		return group
+	default:
+		return v
+	}
 }
 
 func (i *innerJsonLog) ctxLog(ctx context.Context) any {
@@ -205,6 +321,7 @@ func NewJsonLogger(
 	app, scope, uid string,
 	logLevel LogLevelEnum,
 	expectedCtxFields []string) (*JsonLogger, error) {
+	locks, writeMu := newWriteLocks(writer)
 	return &JsonLogger{
 		App:               app,
 		Scope:             scope,
@@ -212,38 +329,57 @@ func NewJsonLogger(
 		LogLevel:          logLevel,
 		writer:            writer,
 		expectedCtxFields: expectedCtxFields,
+		sequence:          new(atomic.Uint64),
+		writeMu:           writeMu,
+		locks:             locks,
+		UTC:               env.IsUTCForced(),
 	}, nil
 }
 
-// With adds a field to the logger.
-func (i *JsonLogger) With(field string, value any) Interface {
+// segment returns a child of the logger with fields, an empty set when nil, logging the background context.
+// every segment, child loggers and single entries alike, is built here
+func (i *JsonLogger) segment(fields map[string]any) *innerJsonLog {
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
 	return &innerJsonLog{
 		JsonLogger:        i,
 		Ctx:               context.Background(),
 		expectedCtxFields: i.expectedCtxFields,
-		fields:            map[string]any{field: value},
+		fields:            fields,
 	}
 }
 
+// entrySegment returns the segment of a single entry logged straight on the logger from call.
+// the root logger has no context, its entries carry no ctx
+func (i *JsonLogger) entrySegment(call caller.Ptr, fields map[string]any) *innerJsonLog {
+	segment := i.segment(fields)
+	segment.Ctx = nil
+	segment.caller = call
+	return segment
+}
+
+// With adds a field to the logger.
+func (i *JsonLogger) With(field string, value any) Interface {
+	return i.segment(map[string]any{field: value})
+}
+
 // WithCtx adds ctx to fields
 func (i *JsonLogger) WithCtx(ctx context.Context) Interface {
-	return &innerJsonLog{
-		JsonLogger:        i,
-		Ctx:               ctx,
-		expectedCtxFields: i.expectedCtxFields,
-		fields:            map[string]any{},
-	}
+	segment := i.segment(nil)
+	segment.Ctx = ctx
+	return segment
+}
+
+// Writer returns the writer entries are written to, eg: to forward already encoded entries
+func (i *JsonLogger) Writer() io.Writer {
+	return i.writer
 }
 
 func (i *JsonLogger) Clone() Interface {
-	return &JsonLogger{
-		App:               i.App,
-		Scope:             i.Scope,
-		UID:               i.UID,
-		LogLevel:          i.LogLevel,
-		writer:            i.writer,
-		expectedCtxFields: i.expectedCtxFields,
-	}
+	clone := *i
+	return &clone
 }
 
 // Log logs a message at LOG level.
@@ -251,6 +387,14 @@ func (i *JsonLogger) Log(format string, args ...any) {
 	i.log(LOG, caller.Upper(), format, args...)
 }
 
+// LogAt logs a message at level, FATAL exits like Fatal.
+func (i *JsonLogger) LogAt(level LogLevelEnum, format string, args ...any) {
+	i.log(level, caller.Upper(), format, args...)
+	if level == FATAL {
+		i.exit()
+	}
+}
+
 // Error logs a message at ERROR level.
 func (i *JsonLogger) Error(format string, args ...any) {
 	i.log(ERROR, caller.Upper(), format, args...)
@@ -266,35 +410,29 @@ func (i *JsonLogger) Debug(format string, args ...any) {
 	i.log(DEBUG, caller.Upper(), format, args...)
 }
 
-// log is an internal method to log messages with structured logging.
-func (i *JsonLogger) log(level LogLevelEnum, call caller.Ptr, format string, args ...any) {
-	if i.LogLevel < level {
-		return
-	}
-
-	msg := format
-	if len(args) > 0 {
-		msg = fmt.Sprintf(format, args...)
-	}
+// Err logs a message at ERROR level with err attached.
+func (i *JsonLogger) Err(err error, format string, args ...any) {
+	i.entrySegment(caller.Upper(), map[string]any{ErrorField: err}).log(ERROR, format, args...)
+}
 
-	logEntry := map[string]any{
-		"caller":    call,
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
-		"level":     level.String(),
-		"app":       i.App,
-		"scope":     i.Scope,
-		"message":   msg,
-	}
+// Fatal logs a message at FATAL level, flushes every pending write and exits with ExitFunc(1).
+func (i *JsonLogger) Fatal(format string, args ...any) {
+	i.log(FATAL, caller.Upper(), format, args...)
+	i.exit()
+}
 
-	if i.UID != "" {
-		logEntry["uid"] = i.UID
-	}
+// exit flushes the logger writer and every registered Syncer, then exits with ExitFunc(1)
+func (i *JsonLogger) exit() {
+	_ = i.Flush()
+	_ = FlushAll()
+	ExitFunc(1)
+}
 
-	jsonLog, err := json.Marshal(logEntry)
-	if err != nil {
-		_, _ = fmt.Fprintf(i.writer, "Error marshaling log: %v", err)
+// log logs through the single core, as a segment carrying only the caller
+func (i *JsonLogger) log(level LogLevelEnum, call caller.Ptr, format string, args ...any) {
+	if i.level() < level {
 		return
 	}
 
-	_, _ = fmt.Fprintln(i.writer, *structs.UnsafeString(jsonLog))
+	i.entrySegment(call, nil).log(level, format, args...)
 }
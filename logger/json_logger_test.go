@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/pixie-sh/logger-go/env"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 type container struct {
@@ -269,3 +272,1090 @@ func TestSegmentWithAndWithCtx(t *testing.T) {
 	assert.Equal(t, nil, modifiedCtx["requestID"], "Modified log should have initial requestID")
 	assert.Equal(t, "new-user-id", modifiedCtx["userID"], "Modified log should have new userID")
 }
+
+func TestCustomErrorSerializer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.ErrorSerializer = func(err error) any {
+		return map[string]any{"kind": "custom", "text": err.Error()}
+	}
+
+	baseLogger.With("error", fmt.Errorf("boom")).Error("failed")
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, map[string]any{"kind": "custom", "text": "boom"}, entry["error"])
+
+	buf.Reset()
+	baseLogger.ErrorSerializer = nil
+	baseLogger.With("error", errorStruct{Msg: "wrong", Field: "User.ID"}).Error("failed")
+
+	entry = nil
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	errInfo := entry["error"].(map[string]any)
+	assert.Equal(t, "THIS IS WRONG", errInfo["errorString"])
+	assert.Equal(t, "wrong", errInfo["Msg"])
+	assert.Equal(t, map[string]any{"msg": "wrong", "field": "User.ID"}, errInfo[ErrorDetailsField])
+}
+
+func TestErr(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	child := baseLogger.With("userID", 123)
+	child.Err(fmt.Errorf("wrapped: %w", errorStruct{Msg: "wrong"}), "failed for %d", 123)
+	child.Log("after")
+	baseLogger.Err(fmt.Errorf("boom"), "root failed")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 3)
+
+	var errLog, afterLog, rootLog map[string]any
+	_ = json.Unmarshal(logLines[0], &errLog)
+	_ = json.Unmarshal(logLines[1], &afterLog)
+	_ = json.Unmarshal(logLines[2], &rootLog)
+
+	assert.Equal(t, "ERROR", errLog["level"])
+	assert.Equal(t, "failed for 123", errLog["message"])
+	assert.Equal(t, "logger.TestErr", errLog["caller"].(map[string]any)["Path"])
+	assert.Equal(t, "wrapped: THIS IS WRONG", errLog["error"].(map[string]any)["errorString"])
+	assert.Equal(t, "wrong", errLog["error"].(map[string]any)["Msg"])
+	assert.NotContains(t, afterLog, "error", "Err must not leak the error into the child fields")
+
+	assert.Equal(t, "logger.TestErr", rootLog["caller"].(map[string]any)["Path"])
+	assert.Equal(t, "boom", rootLog["error"].(map[string]any)["errorString"])
+}
+
+func TestFormatWarning(t *testing.T) {
+	buf := new(bytes.Buffer)
+	devLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "local", "TestUID", DEBUG, nil)
+	prodLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "prod", "TestUID", DEBUG, nil)
+
+	// formats kept in variables, vet would otherwise reject the broken calls
+	missingArg, wrongType, noArgs := "user %s has %d items", "user %d", "no args %s"
+
+	devLogger.Log(missingArg, "john")
+	devLogger.With("k", "v").Log(wrongType, "john")
+	devLogger.Log(noArgs)
+	devLogger.Log("100%% fine %s", "here")
+	prodLogger.Log(missingArg, "john")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 5)
+
+	entries := make([]map[string]any, len(logLines))
+	for idx, line := range logLines {
+		_ = json.Unmarshal(line, &entries[idx])
+	}
+
+	assert.Equal(t, "mismatched format verbs and arguments: %!d(MISSING)", entries[0][FormatWarningField])
+	assert.Equal(t, "mismatched format verbs and arguments: %!d(string=john)", entries[1][FormatWarningField])
+	assert.Equal(t, "format verbs without arguments", entries[2][FormatWarningField])
+	assert.NotContains(t, entries[3], FormatWarningField)
+	assert.NotContains(t, entries[4], FormatWarningField, "prod scopes skip format checks")
+}
+
+func TestFormatVerbs(t *testing.T) {
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%-5d", "%05d", "%.2f", "%8.3f", "%*d", "user %q"} {
+		assert.True(t, hasFormatVerbs(format), format)
+	}
+
+	for _, literal := range []string{"upload 100% done", "50% off", "100%% fine %%s", "% of users", "rate 5%", "% x"} {
+		assert.False(t, hasFormatVerbs(literal), literal)
+	}
+
+	buf := new(bytes.Buffer)
+	devLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "local", "TestUID", DEBUG, nil)
+	// kept in a variable, vet would otherwise take it for a format
+	literal := "upload 100% done"
+	devLogger.Log(literal)
+	devLogger.Event(LOG).Msg("50% off")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2)
+	for _, line := range logLines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.NotContains(t, entry, FormatWarningField)
+	}
+}
+
+func TestDerivedProdScope(t *testing.T) {
+	buf := new(bytes.Buffer)
+	prodLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "prod", "TestUID", DEBUG, nil)
+	prodLogger.RequiredFields = map[string][]string{"prod.payments": {"order_id"}}
+
+	var reported []error
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	noArgs := "no args %s"
+	prodLogger.Named("payments").Log(noArgs)
+	prodLogger.With("k", "v").Named("payments").Log(noArgs)
+	prodLogger.WithScope("billing").Named("refunds").Log(noArgs)
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 3)
+	for _, line := range logLines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.NotContains(t, entry, FormatWarningField, "loggers derived from prod keep skipping format checks")
+	}
+	assert.Empty(t, reported)
+}
+
+func TestWithGroup(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	parent := baseLogger.With("requestID", "abc")
+	db := parent.WithGroup("db").With("query", "select 1").With("rows", 1)
+	db.WithGroup("pool").With("size", 10).Log("query done")
+	parent.WithGroup("empty").Log("no group fields")
+	parent.Log("parent untouched")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 3)
+
+	var grouped, empty, parentLog map[string]any
+	_ = json.Unmarshal(logLines[0], &grouped)
+	_ = json.Unmarshal(logLines[1], &empty)
+	_ = json.Unmarshal(logLines[2], &parentLog)
+
+	assert.Equal(t, "abc", grouped["requestID"])
+	assert.Equal(t, map[string]any{
+		"query": "select 1",
+		"rows":  float64(1),
+		"pool":  map[string]any{"size": float64(10)},
+	}, grouped["db"])
+	assert.Equal(t, "logger.TestWithGroup", grouped["caller"].(map[string]any)["Path"], "caller stays top level")
+
+	assert.NotContains(t, empty, "empty", "empty groups are omitted")
+	assert.NotContains(t, parentLog, "db")
+}
+
+func TestWithScopeAppUID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	child := baseLogger.With("field", "value")
+	child.WithScope("payments").WithApp("billing").WithUID("uid-2").Log("narrowed")
+	child.Log("parent")
+	baseLogger.WithScope("root-scope").Log("root narrowed")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 3)
+
+	var narrowed, parent, root map[string]any
+	_ = json.Unmarshal(logLines[0], &narrowed)
+	_ = json.Unmarshal(logLines[1], &parent)
+	_ = json.Unmarshal(logLines[2], &root)
+
+	assert.Equal(t, "payments", narrowed["scope"])
+	assert.Equal(t, "billing", narrowed["app"])
+	assert.Equal(t, "uid-2", narrowed["uid"])
+	assert.Equal(t, "value", narrowed["field"])
+
+	assert.Equal(t, "TestScope", parent["scope"])
+	assert.Equal(t, "TestApp", parent["app"])
+	assert.Equal(t, "root-scope", root["scope"])
+	assert.Equal(t, "TestScope", baseLogger.Scope)
+}
+
+func TestWithWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	audit := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	child := baseLogger.With("userID", 123)
+	child.WithWriter(audit).Log("audit entry")
+	child.Log("regular entry")
+	baseLogger.WithWriter(audit).Log("root audit entry")
+
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+	assert.Contains(t, buf.String(), "regular entry")
+	assert.Equal(t, 2, bytes.Count(audit.Bytes(), []byte("\n")))
+	assert.Contains(t, audit.String(), `"userID":123`)
+}
+
+func TestWithWriterSharedLock(t *testing.T) {
+	buf := new(bytes.Buffer)
+	audit := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				baseLogger.With("i", i).WithWriter(audit).Log("audit entry")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Same(t, baseLogger.WithWriter(audit).(*JsonLogger).writeMu, baseLogger.With("k", "v").WithWriter(audit).(*innerJsonLog).writeMu)
+	assert.Equal(t, 800, bytes.Count(audit.Bytes(), []byte("\n")), "writes to the same writer never interleave")
+	for _, line := range strings.Split(strings.TrimSpace(audit.String()), "\n") {
+		assert.True(t, json.Valid([]byte(line)))
+	}
+}
+
+// sliceWriter writer of a non comparable type
+type sliceWriter struct {
+	lines [][]byte
+	buf   *bytes.Buffer
+}
+
+func (w sliceWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func TestWithWriterLocksPerTree(t *testing.T) {
+	audit := new(bytes.Buffer)
+	first, _ := NewJsonLogger(context.Background(), new(bytes.Buffer), "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	second, _ := NewJsonLogger(context.Background(), new(bytes.Buffer), "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	assert.NotSame(t, first.WithWriter(audit).(*JsonLogger).writeMu, second.WithWriter(audit).(*JsonLogger).writeMu, "locks live with their logger tree")
+
+	writer := sliceWriter{buf: new(bytes.Buffer)}
+	assert.NotPanics(t, func() { first.WithWriter(writer).Log("entry") })
+	assert.Contains(t, writer.buf.String(), `"entry"`)
+}
+
+func TestRootEntriesCarryNoCtx(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, []string{"requestID"})
+
+	baseLogger.Log("root")
+	baseLogger.LogWith(LOG, "root typed", String("k", "v"))
+	baseLogger.Err(fmt.Errorf("boom"), "root error")
+	baseLogger.With("k", "v").Log("child")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 4)
+	for n, line := range logLines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		if n < 3 {
+			assert.NotContains(t, entry, "ctx", entry["message"])
+		} else {
+			assert.Contains(t, entry, "ctx", entry["message"])
+		}
+	}
+}
+
+func TestWithLevel(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", LOG, nil)
+
+	component := baseLogger.With("component", "db").WithLevel(DEBUG)
+	component.Debug("component debug")
+	baseLogger.Debug("global debug")
+	baseLogger.WithLevel(ERROR).Log("silenced")
+
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")))
+	assert.Contains(t, buf.String(), "component debug")
+	assert.Equal(t, LOG, baseLogger.LogLevel)
+
+	defer func(log Interface) { Logger = log }(Logger)
+	Logger = baseLogger
+	noisy := WithLevel(WARN)
+	noisy.Log("noisy silenced")
+	noisy.Warn("noisy warn")
+
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+	assert.Contains(t, buf.String(), "noisy warn")
+}
+
+func TestTimer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	NewTimer(baseLogger, "import").Stop()
+	elapsed := NewTimer(baseLogger.With("batch", 1), "export").StopErr(fmt.Errorf("disk full"))
+	assert.Greater(t, elapsed, time.Duration(0))
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2)
+
+	var success, failure map[string]any
+	_ = json.Unmarshal(logLines[0], &success)
+	_ = json.Unmarshal(logLines[1], &failure)
+
+	assert.Equal(t, "import completed", success["message"])
+	assert.Equal(t, OutcomeSuccess, success[OutcomeField])
+	assert.Contains(t, success, DurationField)
+	assert.Equal(t, "logger.TestTimer", success["caller"].(map[string]any)["Path"])
+
+	assert.Equal(t, "ERROR", failure["level"])
+	assert.Equal(t, OutcomeFailure, failure[OutcomeField])
+	assert.Equal(t, "disk full", failure["error"].(map[string]any)["errorString"])
+	assert.Equal(t, "logger.TestTimer", failure["caller"].(map[string]any)["Path"])
+}
+
+// copyingLogger logger returning a new logger from With, recording the fields of its entries
+type copyingLogger struct {
+	NopLogger
+	fields  map[string]any
+	entries *[]map[string]any
+}
+
+func (l copyingLogger) Clone() Interface           { return l }
+func (l copyingLogger) WithGroup(string) Interface { return l }
+
+func (l copyingLogger) With(field string, value any) Interface {
+	fields := copyFields(l.fields)
+	fields[field] = value
+	return copyingLogger{fields: fields, entries: l.entries}
+}
+
+func (l copyingLogger) Log(string, ...any)   { *l.entries = append(*l.entries, l.fields) }
+func (l copyingLogger) Error(string, ...any) { *l.entries = append(*l.entries, l.fields) }
+
+func TestHelpersKeepWithResults(t *testing.T) {
+	var entries []map[string]any
+	log := copyingLogger{fields: map[string]any{}, entries: &entries}
+
+	NewTimer(log, "import").StopErr(fmt.Errorf("disk full"))
+	NewProgress(log, "backfill", 100, time.Minute).Done()
+	NewJSONAccessLogger(log).Log(AccessLogEntry{Method: "GET", URI: "/", Status: 200, User: "frank"})
+
+	assert.Len(t, entries, 3)
+	assert.Equal(t, OutcomeFailure, entries[0][OutcomeField])
+	assert.Contains(t, entries[0], ErrorField)
+	assert.Equal(t, int64(100), entries[1][TotalField])
+	assert.Equal(t, "frank", entries[2]["user"])
+}
+
+func TestProgress(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	progress := NewProgress(baseLogger, "backfill", 100, time.Minute)
+	now := progress.start
+	progress.now = func() time.Time { return now }
+
+	now = now.Add(10 * time.Second)
+	progress.Add(10)
+	now = now.Add(50 * time.Second)
+	progress.Add(20)
+	progress.Add(20)
+	progress.Done()
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2, "progress entries are throttled by the interval")
+
+	var inProgress, done map[string]any
+	_ = json.Unmarshal(logLines[0], &inProgress)
+	_ = json.Unmarshal(logLines[1], &done)
+
+	assert.Equal(t, "backfill in progress", inProgress["message"])
+	assert.Equal(t, float64(30), inProgress[ProcessedField])
+	assert.Equal(t, float64(100), inProgress[TotalField])
+	assert.Equal(t, float64(0.5), inProgress[RateField])
+	assert.Equal(t, float64(140000), inProgress[ETAField])
+	assert.Equal(t, "logger.TestProgress", inProgress["caller"].(map[string]any)["Path"])
+
+	assert.Equal(t, "backfill done", done["message"])
+	assert.Equal(t, float64(50), done[ProcessedField])
+}
+
+func TestWithF(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	log := baseLogger.WithF(String("user", "john"), Int("attempt", 3), Bool("retry", true))
+	log.WithGroup("db").WithF(Float64("duration", 1.5), Any("tags", []string{"a"}), Err(fmt.Errorf("timeout"))).Log("typed fields")
+	log.Log("parent")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2)
+
+	var typed, parent map[string]any
+	_ = json.Unmarshal(logLines[0], &typed)
+	_ = json.Unmarshal(logLines[1], &parent)
+
+	assert.Equal(t, "john", typed["user"])
+	assert.Equal(t, float64(3), typed["attempt"])
+	assert.Equal(t, true, typed["retry"])
+
+	db := typed["db"].(map[string]any)
+	assert.Equal(t, 1.5, db["duration"])
+	assert.Equal(t, []any{"a"}, db["tags"])
+	assert.Equal(t, "timeout", db["error"].(map[string]any)["errorString"])
+
+	assert.Equal(t, "john", parent["user"])
+	assert.NotContains(t, parent, "db")
+}
+
+func TestWithFInlined(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("X", 3600))
+
+	baseLogger.LogWith(LOG, "typed", String("s", "a\"<b>\n\u00e9"), Int64("i", -42), Float64("f", 1e-7), Float64("g", 1.5),
+		Bool("b", true), Duration("d", time.Second), Time("t", at))
+	baseLogger.With("s", "a\"<b>\n\u00e9").With("i", int64(-42)).With("f", 1e-7).With("g", 1.5).
+		With("b", true).With("d", time.Second).With("t", at).Log("typed")
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 2)
+	assert.True(t, json.Valid(logLines[0]), string(logLines[0]))
+	assert.Contains(t, string(logLines[0]), `"s":"a\"\u003cb\u003e\né"`)
+	assert.Contains(t, string(logLines[0]), `"f":1e-7`)
+
+	var inlined, boxed map[string]any
+	assert.NoError(t, json.Unmarshal(logLines[0], &inlined))
+	assert.NoError(t, json.Unmarshal(logLines[1], &boxed))
+	for _, key := range []string{"s", "i", "f", "g", "b", "d", "t"} {
+		assert.Equal(t, boxed[key], inlined[key], key)
+	}
+
+	buf.Reset()
+	baseLogger.DuplicateKeys = SuffixDuplicateKeys
+	baseLogger.LogWith(LOG, "own keys", String("message", "mine"), String("user", "u1"))
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "own keys", entry["message"])
+	assert.Equal(t, "mine", entry["message_1"])
+	assert.Equal(t, "u1", entry["user"])
+}
+
+func TestGenericWith(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	assert.Equal(t, StringType, TypedField("k", "v").Type)
+	assert.Equal(t, IntType, TypedField("k", int32(1)).Type)
+	assert.Equal(t, FloatType, TypedField("k", float32(1)).Type)
+	assert.Equal(t, ErrorType, TypedField[error]("k", fmt.Errorf("e")).Type)
+	assert.Equal(t, AnyType, TypedField("k", uint64(1)).Type)
+
+	WithTyped(WithTyped(baseLogger, "count", uint16(7)), "name", "job").Log("generic")
+
+	var entry map[string]any
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, float64(7), entry["count"])
+	assert.Equal(t, "job", entry["name"])
+
+	buf.Reset()
+	defer func(log Interface) { Logger = log }(Logger)
+	Logger = baseLogger
+
+	With("attempt", 3).Log("global")
+	entry = nil
+	assert.Nil(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, float64(3), entry["attempt"])
+	assert.Equal(t, "global", entry["message"])
+}
+
+func TestEntryID(t *testing.T) {
+	now := time.Now()
+	first, second := NewEntryID(now), NewEntryID(now.Add(time.Millisecond))
+	assert.Len(t, first, 26)
+	assert.NotEqual(t, first, NewEntryID(now))
+	assert.Less(t, first, second, "entry ids sort by time")
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.EntryID = true
+	baseLogger.Log("root")
+	baseLogger.With("k", "v").Log("child")
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		_ = json.Unmarshal(line, &entry)
+		assert.Len(t, entry[EntryIDField], 26)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Sequence = true
+
+	baseLogger.Log("first")
+	baseLogger.With("k", "v").Log("second")
+	baseLogger.WithScope("other").Log("third")
+
+	for idx, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		_ = json.Unmarshal(line, &entry)
+		assert.Equal(t, float64(idx+1), entry[SequenceField])
+	}
+}
+
+func TestHostFields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.HostFields = true
+	baseLogger.Log("with host")
+
+	host, _ := os.Hostname()
+	var entry map[string]any
+	_ = json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry)
+	assert.Equal(t, host, entry[HostField])
+	assert.Equal(t, float64(os.Getpid()), entry[PIDField])
+}
+
+func TestTimestampPrecision(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.TimestampPrecision = MicrosecondPrecision
+	baseLogger.Log("precise")
+	baseLogger.With("k", "v").Log("precise child")
+
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		ts, ok := entry["timestamp"].(string)
+		assert.True(t, ok)
+		_, err := time.Parse(MicrosecondPrecision.Layout(), ts)
+		assert.NoError(t, err)
+		assert.Regexp(t, `T\d{2}:\d{2}:\d{2}\.\d{6}`, ts)
+	}
+
+	assert.Equal(t, time.RFC3339, TimestampPrecision("").Layout())
+}
+
+func TestForceUTC(t *testing.T) {
+	t.Setenv(env.UTC, "true")
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	assert.True(t, baseLogger.UTC)
+	baseLogger.With("k", "v").Log("utc child")
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.True(t, strings.HasSuffix(entry["timestamp"].(string), "Z"))
+}
+
+func TestLocalTime(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.FixedZone("JST", 9*60*60)
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.LocalTime = true
+	baseLogger.Log("local")
+	baseLogger.UTC = true
+	baseLogger.Log("forced utc")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.True(t, strings.HasSuffix(entry["timestamp"].(string), "+09:00"), entry["timestamp"])
+	assert.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.True(t, strings.HasSuffix(entry["timestamp"].(string), "Z"), entry["timestamp"])
+}
+
+func TestStrictMode(t *testing.T) {
+	var reported []error
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Strict = true
+
+	baseLogger.With("user", "u1").Log("user created")
+	baseLogger.Log("100%% done")
+	baseLogger.Println("user", "u1", "50%s off")
+	NewTimer(baseLogger, "import").StopErr(errors.New("failed"))
+	NewProgress(baseLogger, "import", 10, 0).Done()
+	NewJSONAccessLogger(baseLogger).Log(AccessLogEntry{Method: "GET", URI: "/a%20b", Status: 200})
+	assert.Empty(t, reported, "the library's own helpers aren't reported")
+	assert.Contains(t, buf.String(), `"message":"user u1 50%s off"`)
+	assert.NotContains(t, buf.String(), FormatWarningField)
+
+	baseLogger.Log("user %s created", "u1")
+	baseLogger.With("k", "v").Warn("user %s created", "u1")
+	assert.Len(t, reported, 2)
+	assert.Equal(t, StrictModeError{Format: "user %s created"}, reported[0])
+
+	reported = nil
+	baseLogger.Scope = "production"
+	baseLogger.Log("user %s created", "u1")
+	assert.Empty(t, reported)
+}
+
+func TestEntrySize(t *testing.T) {
+	buf := new(bytes.Buffer)
+	stats := new(SizeStats)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.SizeField = true
+	baseLogger.SizeRecorder = stats.Record
+
+	baseLogger.Log("root entry")
+	baseLogger.With("k", strings.Repeat("v", 100)).Log("child entry")
+
+	var total int64
+	lines := bytes.SplitAfter(buf.Bytes(), []byte("\n"))
+	for _, line := range lines[:len(lines)-1] {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, float64(len(line)), entry[EntrySizeField])
+		total += int64(len(line))
+	}
+
+	var recorded int64
+	for _, v := range stats.Totals() {
+		recorded += v.Bytes
+	}
+	assert.Equal(t, total, recorded)
+}
+
+func TestRootAndChildShareCore(t *testing.T) {
+	defer func(local *time.Location) { time.Local = local }(time.Local)
+	time.Local = time.FixedZone("JST", 9*60*60)
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Log("root")
+	baseLogger.Err(fmt.Errorf("boom"), "root err")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.True(t, strings.HasSuffix(entry["timestamp"].(string), "Z"))
+		assert.Contains(t, entry, CallerField)
+		assert.Equal(t, "TestUID", entry["uid"])
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	previous := Logger
+	defer func() { Logger = previous }()
+	Logger = NewNopLogger()
+
+	assert.NotPanics(t, func() {
+		Logger.Clone().WithCtx(context.Background()).With("k", "v").WithGroup("g").Log("discarded")
+		Logger.Err(fmt.Errorf("boom"), "discarded")
+		StartTimer("op").Stop()
+	})
+}
+
+func TestEventBuilder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", LOG, nil)
+
+	child := baseLogger.With("request", "r1")
+	child.Event(ERROR).Str("user", "u1").Int("attempt", 2).Err(fmt.Errorf("boom")).Msg("failed")
+	child.Event(WARN).Msgf("retry %d", 3)
+	child.Event(DEBUG).Str("ignored", "x").Msg("disabled")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var first, second map[string]any
+	assert.NoError(t, json.Unmarshal(lines[0], &first))
+	assert.NoError(t, json.Unmarshal(lines[1], &second))
+
+	assert.Equal(t, "ERROR", first["level"])
+	assert.Equal(t, "u1", first["user"])
+	assert.Equal(t, float64(2), first["attempt"])
+	assert.Equal(t, "r1", first["request"])
+	assert.NotNil(t, first[ErrorField])
+	assert.Equal(t, "logger.TestEventBuilder", first[CallerField].(map[string]any)["Path"])
+
+	assert.Equal(t, "retry 3", second["message"])
+	assert.NotContains(t, second, "user")
+	assert.NotContains(t, second, ErrorField)
+}
+
+type writeRecorder struct {
+	writes [][]byte
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func TestAtomicLineWrites(t *testing.T) {
+	w := new(writeRecorder)
+	baseLogger, _ := NewJsonLogger(context.Background(), w, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	child := baseLogger.With("k", "v")
+
+	var wg sync.WaitGroup
+	for n := 0; n < 50; n++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); baseLogger.Log("root") }()
+		go func() { defer wg.Done(); child.Clone().Log("child") }()
+	}
+	wg.Wait()
+
+	assert.Len(t, w.writes, 100)
+	for _, p := range w.writes {
+		assert.Equal(t, 1, bytes.Count(p, []byte("\n")))
+		assert.True(t, json.Valid(p))
+	}
+}
+
+func TestLevelFormat(t *testing.T) {
+	levelOf := func(log *JsonLogger, level LogLevelEnum) any {
+		buf := new(bytes.Buffer)
+		log.WithWriter(buf).Event(level).Msg("level")
+		var entry map[string]any
+		_ = json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry)
+		return entry["level"]
+	}
+
+	baseLogger, _ := NewJsonLogger(context.Background(), os.Stdout, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	assert.Equal(t, "WARN", levelOf(baseLogger, WARN))
+
+	baseLogger.LevelFormat = LowerLevelFormat
+	assert.Equal(t, "warn", levelOf(baseLogger, WARN))
+
+	baseLogger.LevelFormat = NumericLevelFormat
+	assert.Equal(t, float64(ERROR), levelOf(baseLogger, ERROR))
+
+	baseLogger.LevelNames = map[LogLevelEnum]string{WARN: "warning"}
+	assert.Equal(t, "warning", levelOf(baseLogger, WARN))
+	assert.Equal(t, float64(DEBUG), levelOf(baseLogger, DEBUG))
+}
+
+func TestLevelNum(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.LevelNum = true
+	baseLogger.Warn("warn")
+	baseLogger.With("k", "v").Debug("debug")
+
+	var levels []float64
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		levels = append(levels, entry[LevelNumField].(float64))
+		assert.IsType(t, "", entry["level"])
+	}
+	assert.Equal(t, []float64{float64(WARN), float64(DEBUG)}, levels)
+}
+
+type marshalerUser struct {
+	ID       string
+	Password string
+	Address  marshalerAddress
+}
+
+func (u marshalerUser) MarshalLog(enc FieldEncoder) {
+	enc.AddString("id", u.ID)
+	enc.AddObject("address", u.Address)
+}
+
+type marshalerAddress struct {
+	City string
+}
+
+func (a marshalerAddress) MarshalLog(enc FieldEncoder) {
+	enc.AddString("city", a.City)
+}
+
+func TestLogMarshaler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	user := marshalerUser{ID: "u1", Password: "secret", Address: marshalerAddress{City: "Porto"}}
+
+	baseLogger.With("user", user).Log("with")
+	baseLogger.WithF(Object("user", user)).Log("typed")
+	baseLogger.Event(LOG).Object("user", user).Msg("event")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	for _, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, map[string]any{"id": "u1", "address": map[string]any{"city": "Porto"}}, entry["user"])
+	}
+}
+
+func TestTextEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "", DEBUG, nil)
+	baseLogger.Encoder = TextEncoder{}
+	baseLogger.WithF(String("user", "u1"), Int("n", 2)).Warn("slow request")
+
+	line := buf.String()
+	assert.True(t, strings.HasSuffix(line, " WARN  TestApp/TestScope  slow request  caller=logger.TestTextEncoder  ctx={}  n=2  user=u1\n"), line)
+}
+
+func TestTextEncoderGroups(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "", DEBUG, nil)
+	baseLogger.Encoder = TextEncoder{}
+	baseLogger.WithGroup("http").With("method", "GET").WithF(Int("status", 200)).WithGroup("tls").With("version", "1.3").Log("request")
+
+	line := buf.String()
+	assert.Contains(t, line, "  http.method=GET  http.status=200  http.tls.version=1.3\n", line)
+}
+
+func TestDefaultEncoder(t *testing.T) {
+	t.Setenv(env.Parser, "text")
+	assert.Equal(t, TextEncoder{Color: false}, defaultEncoder())
+
+	t.Setenv(env.Parser, "logfmt")
+	assert.Equal(t, LogfmtEncoder{}, defaultEncoder())
+
+	t.Setenv(env.Parser, "json")
+	assert.Nil(t, defaultEncoder())
+
+	t.Setenv(env.Parser, "")
+	assert.Nil(t, defaultEncoder(), "tests don't run on a terminal")
+}
+
+type levelRecorder struct {
+	levels []LogLevelEnum
+}
+
+func (w *levelRecorder) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *levelRecorder) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	w.levels = append(w.levels, level)
+	return len(p), nil
+}
+
+func TestLevelWriter(t *testing.T) {
+	w := new(levelRecorder)
+	baseLogger, _ := NewJsonLogger(context.Background(), w, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Error("error")
+	baseLogger.With("k", "v").Warn("warn")
+	baseLogger.Debug("debug")
+
+	assert.Equal(t, []LogLevelEnum{ERROR, WARN, DEBUG}, w.levels)
+}
+
+func TestFatal(t *testing.T) {
+	var codes []int
+	defer func(fn func(int)) { ExitFunc = fn }(ExitFunc)
+	ExitFunc = func(code int) { codes = append(codes, code) }
+
+	w := &syncWriter{}
+	baseLogger, _ := NewJsonLogger(context.Background(), w, "TestApp", "TestScope", "TestUID", ERROR, nil)
+	baseLogger.Fatal("fatal %d", 1)
+	baseLogger.With("k", "v").Fatal("fatal child")
+	baseLogger.Event(FATAL).Msg("fatal event")
+
+	assert.Equal(t, []int{1, 1, 1}, codes)
+	assert.GreaterOrEqual(t, w.flushed, 3)
+
+	lines := bytes.Split(bytes.TrimSpace(w.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	for _, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "FATAL", entry["level"])
+	}
+}
+
+func TestWithKV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.WithKV("user", "u1", "attempt", 2, 3, "bad key", "dangling").WithGroup("req").WithKV("id", "r1").Log("kv")
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "u1", entry["user"])
+	assert.Equal(t, float64(2), entry["attempt"])
+	assert.Equal(t, "bad key", entry[BadKey+"(3)"])
+	assert.Equal(t, "dangling", entry[BadKey])
+	assert.Equal(t, map[string]any{"id": "r1"}, entry["req"])
+}
+
+func TestWithError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.ErrorStack = true
+
+	root := fmt.Errorf("connection refused")
+	err := fmt.Errorf("query failed: %w", errors.Join(root, fmt.Errorf("retry exhausted")))
+	baseLogger.WithError(err).Error("failed")
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	value := entry[ErrorField].(map[string]any)
+	assert.Equal(t, err.Error(), value["errorString"])
+
+	chain := value[ErrorChainField].([]any)
+	assert.Len(t, chain, 4)
+	assert.Equal(t, "connection refused", chain[2].(map[string]any)["errorString"])
+	assert.Equal(t, "*errors.joinError", chain[1].(map[string]any)["type"])
+
+	stack := value[ErrorStackField].([]any)
+	assert.Contains(t, stack[0], "TestWithError")
+}
+
+func TestEnabled(t *testing.T) {
+	baseLogger, _ := NewJsonLogger(context.Background(), new(bytes.Buffer), "TestApp", "TestScope", "TestUID", WARN, nil)
+	child := baseLogger.With("k", "v")
+
+	assert.True(t, baseLogger.Enabled(ERROR))
+	assert.True(t, child.Enabled(WARN))
+	assert.False(t, child.Enabled(LOG))
+	assert.True(t, child.WithLevel(DEBUG).Enabled(DEBUG))
+	assert.False(t, NewNopLogger().Enabled(FATAL))
+}
+
+func TestLogAt(t *testing.T) {
+	var codes []int
+	defer func(fn func(int)) { ExitFunc = fn }(ExitFunc)
+	ExitFunc = func(code int) { codes = append(codes, code) }
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", WARN, nil)
+	baseLogger.LogAt(WARN, "warn %d", 1)
+	baseLogger.LogAt(DEBUG, "skipped")
+	baseLogger.With("k", "v").LogAt(ERROR, "error child")
+	baseLogger.LogAt(FATAL, "fatal")
+
+	assert.Equal(t, []int{1}, codes)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	var levels []any
+	for _, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "logger.TestLogAt", entry[CallerField].(map[string]any)["Path"])
+		levels = append(levels, entry["level"])
+	}
+	assert.Equal(t, []any{"WARN", "ERROR", "FATAL"}, levels)
+}
+
+func TestNamed(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Named("payments").With("k", "v").Named("refunds").Log("named")
+	baseLogger.Log("root")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "payments.refunds", entry[LoggerField])
+	assert.Equal(t, "TestScope.payments.refunds", entry["scope"])
+	assert.Equal(t, "v", entry["k"])
+
+	entry = nil
+	assert.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.NotContains(t, entry, LoggerField)
+	assert.Equal(t, "TestScope", entry["scope"])
+}
+
+func TestLazy(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", LOG, nil)
+
+	calls := 0
+	stats := Lazy(func() any {
+		calls++
+		return map[string]any{"open": 3}
+	})
+
+	log := baseLogger.With("stats", stats).WithF(Any("typed", stats))
+	log.Debug("suppressed")
+	assert.Equal(t, 0, calls)
+	assert.Zero(t, buf.Len())
+
+	log.Log("written")
+	assert.Equal(t, 2, calls)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, map[string]any{"open": float64(3)}, entry["stats"])
+	assert.Equal(t, map[string]any{"open": float64(3)}, entry["typed"])
+}
+
+func TestPrintf(t *testing.T) {
+	var codes []int
+	defer func(fn func(int)) { ExitFunc = fn }(ExitFunc)
+	ExitFunc = func(code int) { codes = append(codes, code) }
+
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.Printf("printf %d", 1)
+	baseLogger.With("k", "v").Println("println", 2, "100%")
+	baseLogger.Fatalf("fatalf %s", "x")
+
+	defer func(log Interface) { Logger = log }(Logger)
+	Logger = baseLogger
+	Println("global")
+
+	assert.Equal(t, []int{1}, codes)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 4)
+
+	expected := []struct{ level, message string }{
+		{"LOG", "printf 1"},
+		{"LOG", "println 2 100%"},
+		{"FATAL", "fatalf x"},
+		{"LOG", "global"},
+	}
+	for idx, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, expected[idx].level, entry["level"])
+		assert.Equal(t, expected[idx].message, entry["message"])
+		assert.Equal(t, "logger.TestPrintf", entry[CallerField].(map[string]any)["Path"])
+	}
+}
+
+func TestPanic(t *testing.T) {
+	w := &syncWriter{}
+	baseLogger, _ := NewJsonLogger(context.Background(), w, "TestApp", "TestScope", "TestUID", ERROR, nil)
+
+	assert.PanicsWithValue(t, "invariant 1 broken", func() { baseLogger.Panic("invariant %d broken", 1) })
+	assert.PanicsWithValue(t, "child broken", func() { baseLogger.With("k", "v").Panic("child broken") })
+
+	defer func(log Interface) { Logger = log }(Logger)
+	Logger = baseLogger
+	assert.PanicsWithValue(t, "global broken", func() { Panic("global broken") })
+	assert.PanicsWithValue(t, "nop broken", func() { NewNopLogger().Panic("nop broken") })
+
+	assert.GreaterOrEqual(t, w.flushed, 3)
+
+	lines := bytes.Split(bytes.TrimSpace(w.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 3)
+	for _, line := range lines {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "ERROR", entry["level"])
+		assert.Equal(t, true, entry[PanicField])
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	entryFor := func(policy DuplicateKeyPolicy) map[string]any {
+		buf := new(bytes.Buffer)
+		baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+		baseLogger.DuplicateKeys = policy
+		baseLogger.With("user", "u1").With("user", "u2").With(CallerField, "mine").WithF(String("user", "u3")).Log("dup")
+
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+		return entry
+	}
+
+	entry := entryFor("")
+	assert.Equal(t, "u3", entry["user"])
+	assert.Equal(t, "logger.TestDuplicateKeys.func1", entry[CallerField].(map[string]any)["Path"])
+
+	entry = entryFor(FirstKeyWins)
+	assert.Equal(t, "u1", entry["user"])
+	assert.Equal(t, "mine", entry[CallerField])
+
+	entry = entryFor(SuffixDuplicateKeys)
+	assert.Equal(t, "u1", entry["user"])
+	assert.Equal(t, "u2", entry["user_1"])
+	assert.Equal(t, "u3", entry["user_2"])
+	assert.Equal(t, "mine", entry[CallerField+"_1"])
+	assert.Equal(t, "logger.TestDuplicateKeys.func1", entry[CallerField].(map[string]any)["Path"])
+}
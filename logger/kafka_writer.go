@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"time"
+)
+
+// KafkaMessage record published by a KafkaWriter
+type KafkaMessage struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// KafkaProducer publishes a batch of messages, implemented by adapters over a kafka client, eg: franz-go or sarama.
+// it returns once the batch is delivered or failed
+type KafkaProducer interface {
+	Produce(ctx context.Context, messages []KafkaMessage) error
+}
+
+// KafkaWriterConfiguration kafka sink configuration
+type KafkaWriterConfiguration struct {
+	Producer KafkaProducer
+	Topic    string `toml:"topic" json:"topic" mapstructure:"topic"`
+
+	// KeyField dotted path of the entry field used as message key, eg: ctx.trace_id. no key when empty
+	KeyField string `toml:"keyField" json:"keyField" mapstructure:"keyField"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+	Timeout       time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// OnError receives delivery errors, defaults to the DiagnosticsHandler
+	OnError func(error) `toml:"-" json:"-" mapstructure:"onError"`
+
+	// DeadLetter spills the batches the producer failed to deliver to disk, replaying them once delivery recovers.
+	// unused with OnError
+	DeadLetter DeadLetterConfiguration `toml:"deadLetter" json:"deadLetter" mapstructure:"deadLetter"`
+}
+
+// KafkaLoggerConfiguration json logger publishing to kafka
+type KafkaLoggerConfiguration struct {
+	JSONLoggerConfiguration  `mapstructure:",squash"`
+	KafkaWriterConfiguration `mapstructure:",squash"`
+}
+
+// KafkaDeliveryError batch the producer failed to deliver
+type KafkaDeliveryError struct {
+	Topic    string
+	Messages int
+	Err      error
+}
+
+// Error returns the delivery failure description
+func (e KafkaDeliveryError) Error() string {
+	return fmt.Sprintf("kafka: %d messages to %s not delivered: %v", e.Messages, e.Topic, e.Err)
+}
+
+// Unwrap returns the producer error
+func (e KafkaDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// KafkaWriter batches entries by size and interval and publishes them to a topic
+type KafkaWriter struct {
+	cfg   KafkaWriterConfiguration
+	batch *batcher
+}
+
+// NewKafkaWriter returns a kafka writer publishing through cfg.Producer
+func NewKafkaWriter(cfg KafkaWriterConfiguration) (*KafkaWriter, error) {
+	if cfg.Producer == nil {
+		return nil, fmt.Errorf("kafka writer requires a Producer")
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("kafka writer requires a Topic")
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	w := &KafkaWriter{cfg: cfg}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.produce)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next batch
+func (w *KafkaWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush publishes the pending entries
+func (w *KafkaWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close publishes the pending entries, the producer is left open
+func (w *KafkaWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *KafkaWriter) produce(batch [][]byte) error {
+	messages := make([]KafkaMessage, len(batch))
+	for i, entry := range batch {
+		messages[i] = KafkaMessage{Topic: w.cfg.Topic, Value: bytes.TrimRight(entry, "\n")}
+		if key := entryField(entry, w.cfg.KeyField); key != "" {
+			messages[i].Key = []byte(key)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	err := w.cfg.Producer.Produce(ctx, messages)
+	if err == nil {
+		return nil
+	}
+
+	err = KafkaDeliveryError{Topic: w.cfg.Topic, Messages: len(messages), Err: err}
+	if w.cfg.OnError != nil {
+		w.cfg.OnError(err)
+		return nil
+	}
+
+	return err
+}
+
+func createKafkaLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg KafkaLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewKafkaWriter(cfg.KafkaWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingProducer struct {
+	mu       sync.Mutex
+	batches  [][]KafkaMessage
+	failWith error
+}
+
+func (p *recordingProducer) Produce(_ context.Context, messages []KafkaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.batches = append(p.batches, messages)
+	return p.failWith
+}
+
+func TestKafkaDriver(t *testing.T) {
+	producer := &recordingProducer{}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   KafkaLoggerDriver,
+		Values: map[string]any{
+			"Producer":      producer,
+			"topic":         "logs",
+			"keyField":      "trace_id",
+			"batchSize":     2,
+			"flushInterval": time.Hour,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.With("trace_id", "t1").Log("first")
+	log.Log("second")
+	log.Log("third")
+
+	assert.Len(t, producer.batches, 1, "published once the batch is full")
+	assert.Equal(t, "logs", producer.batches[0][0].Topic)
+	assert.Equal(t, []byte("t1"), producer.batches[0][0].Key)
+	assert.Nil(t, producer.batches[0][1].Key)
+	assert.Equal(t, byte('}'), producer.batches[0][0].Value[len(producer.batches[0][0].Value)-1])
+
+	assert.Nil(t, log.(Syncer).Close())
+	assert.Len(t, producer.batches, 2)
+	assert.Len(t, producer.batches[1], 1)
+}
+
+func TestKafkaWriterDeliveryErrors(t *testing.T) {
+	producer := &recordingProducer{failWith: errors.New("broker down")}
+
+	var delivery []error
+	writer, err := NewKafkaWriter(KafkaWriterConfiguration{
+		Producer: producer,
+		Topic:    "logs",
+		OnError:  func(err error) { delivery = append(delivery, err) },
+	})
+	assert.Nil(t, err)
+
+	_, _ = writer.Write([]byte(`{"message":"m"}` + "\n"))
+	assert.Nil(t, writer.Close())
+
+	assert.Len(t, delivery, 1)
+	var deliveryErr KafkaDeliveryError
+	assert.True(t, errors.As(delivery[0], &deliveryErr))
+	assert.Equal(t, 1, deliveryErr.Messages)
+	assert.ErrorIs(t, delivery[0], producer.failWith)
+
+	_, err = NewKafkaWriter(KafkaWriterConfiguration{Topic: "logs"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// KinesisService destination service of a KinesisWriter
+type KinesisService string
+
+// supported kinesis services
+const (
+	// KinesisStream data stream, records are partitioned by key
+	KinesisStream KinesisService = "stream"
+
+	// KinesisFirehose delivery stream, records keep the trailing newline so they land as json lines
+	KinesisFirehose KinesisService = "firehose"
+)
+
+// ErrKinesisThrottled error the KinesisClient adapters map throughput exceeded errors to
+var ErrKinesisThrottled = errors.New("kinesis: throttled")
+
+// KinesisRecord record of a PutRecords or PutRecordBatch call
+type KinesisRecord struct {
+	Data         []byte
+	PartitionKey string
+}
+
+// KinesisClient puts records to a kinesis data stream or firehose delivery stream,
+// implemented by adapters over the aws sdk. it returns the indexes of the records the service rejected
+type KinesisClient interface {
+	PutRecords(ctx context.Context, stream string, records []KinesisRecord) ([]int, error)
+}
+
+// KinesisWriterConfiguration kinesis and firehose sink configuration
+type KinesisWriterConfiguration struct {
+	Client  KinesisClient
+	Service KinesisService `toml:"service" json:"service" mapstructure:"service"`
+	Stream  string         `toml:"stream" json:"stream" mapstructure:"stream"`
+
+	// KeyField dotted path of the entry field used as partition key, eg: ctx.trace_id. random when empty or missing
+	KeyField string `toml:"keyField" json:"keyField" mapstructure:"keyField"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+	Timeout       time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// MaxRetries retries of throttled calls and rejected records, backing off from RetryBackoff
+	MaxRetries   int           `toml:"maxRetries" json:"maxRetries" mapstructure:"maxRetries"`
+	RetryBackoff time.Duration `toml:"retryBackoff" json:"retryBackoff" mapstructure:"retryBackoff"`
+}
+
+// KinesisLoggerConfiguration json logger shipping to kinesis or firehose
+type KinesisLoggerConfiguration struct {
+	JSONLoggerConfiguration    `mapstructure:",squash"`
+	KinesisWriterConfiguration `mapstructure:",squash"`
+}
+
+// KinesisRecordsError records still rejected once retries are exhausted
+type KinesisRecordsError struct {
+	Stream  string
+	Records int
+}
+
+// Error returns the rejection description
+func (e KinesisRecordsError) Error() string {
+	return fmt.Sprintf("kinesis: %d records to %s rejected", e.Records, e.Stream)
+}
+
+// kinesisLimits per call and per record limits of a service
+type kinesisLimits struct {
+	records     int
+	callBytes   int
+	recordBytes int
+}
+
+var kinesisServiceLimits = map[KinesisService]kinesisLimits{
+	KinesisStream:   {records: 500, callBytes: 5 << 20, recordBytes: 1 << 20},
+	KinesisFirehose: {records: 500, callBytes: 4 << 20, recordBytes: 1000 << 10},
+}
+
+// KinesisWriter batches entries into PutRecords calls within the service limits,
+// retrying throttled calls and rejected records
+type KinesisWriter struct {
+	cfg    KinesisWriterConfiguration
+	limits kinesisLimits
+	batch  *batcher
+}
+
+// NewKinesisWriter returns a writer shipping to the cfg.Stream through cfg.Client
+func NewKinesisWriter(cfg KinesisWriterConfiguration) (*KinesisWriter, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("kinesis writer requires a Client")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("kinesis writer requires a Stream")
+	}
+
+	if cfg.Service == "" {
+		cfg.Service = KinesisStream
+	}
+
+	limits, ok := kinesisServiceLimits[cfg.Service]
+	if !ok {
+		return nil, fmt.Errorf("unknown kinesis service %s", cfg.Service)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	w := &KinesisWriter{cfg: cfg, limits: limits}
+	w.batch = newBatcher(cfg.BatchSize, cfg.FlushInterval, w.ship)
+	return w, nil
+}
+
+// Write queues the entry for the next batch
+func (w *KinesisWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush ships the pending entries
+func (w *KinesisWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close ships the pending entries, the client is left open
+func (w *KinesisWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *KinesisWriter) ship(batch [][]byte) error {
+	var errs []error
+	for _, records := range w.split(w.records(batch)) {
+		if err := w.put(records); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// put sends records, resending the rejected ones
+func (w *KinesisWriter) put(records []KinesisRecord) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	var rejected KinesisRecordsError
+	retryable := func(err error) bool {
+		return errors.Is(err, ErrKinesisThrottled) || errors.As(err, &rejected)
+	}
+
+	return retry(ctx, w.cfg.MaxRetries, w.cfg.RetryBackoff, retryable, func() error {
+		failed, err := w.cfg.Client.PutRecords(ctx, w.cfg.Stream, records)
+		if err != nil {
+			return err
+		}
+
+		if len(failed) == 0 {
+			return nil
+		}
+
+		pending := make([]KinesisRecord, 0, len(failed))
+		for _, index := range failed {
+			if index >= 0 && index < len(records) {
+				pending = append(pending, records[index])
+			}
+		}
+		records = pending
+
+		return KinesisRecordsError{Stream: w.cfg.Stream, Records: len(records)}
+	})
+}
+
+// records converts entries to records, truncated to the record limit
+func (w *KinesisWriter) records(batch [][]byte) []KinesisRecord {
+	records := make([]KinesisRecord, len(batch))
+	for i, entry := range batch {
+		data := entry
+		if w.cfg.Service == KinesisStream {
+			data = bytes.TrimRight(entry, "\n")
+		}
+
+		if len(data) > w.limits.recordBytes {
+			data = data[:w.limits.recordBytes]
+		}
+
+		key := entryField(entry, w.cfg.KeyField)
+		if key == "" {
+			key = strconv.FormatUint(rand.Uint64(), 36)
+		}
+
+		records[i] = KinesisRecord{Data: data, PartitionKey: key}
+	}
+
+	return records
+}
+
+// split groups records by the per call count and size limits
+func (w *KinesisWriter) split(records []KinesisRecord) [][]KinesisRecord {
+	var calls [][]KinesisRecord
+	start, size := 0, 0
+	for i, record := range records {
+		recordSize := len(record.Data) + len(record.PartitionKey)
+		if i > start && (i-start >= w.limits.records || size+recordSize > w.limits.callBytes) {
+			calls = append(calls, records[start:i])
+			start, size = i, 0
+		}
+		size += recordSize
+	}
+
+	if start < len(records) {
+		calls = append(calls, records[start:])
+	}
+
+	return calls
+}
+
+func createKinesisLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg KinesisLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewKinesisWriter(cfg.KinesisWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingKinesis struct {
+	calls    [][]KinesisRecord
+	failures [][]int
+}
+
+func (c *recordingKinesis) PutRecords(_ context.Context, _ string, records []KinesisRecord) ([]int, error) {
+	c.calls = append(c.calls, records)
+	if len(c.failures) == 0 {
+		return nil, nil
+	}
+
+	failed := c.failures[0]
+	c.failures = c.failures[1:]
+	return failed, nil
+}
+
+func TestKinesisDriver(t *testing.T) {
+	client := &recordingKinesis{failures: [][]int{{1}}}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   KinesisLoggerDriver,
+		Values:   map[string]any{"Client": client, "stream": "logs", "keyField": "user", "retryBackoff": time.Millisecond},
+	})
+	assert.Nil(t, err)
+
+	log.With("user", "u1").Log("first")
+	log.Log("second")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, client.calls, 2)
+	assert.Len(t, client.calls[0], 2)
+	assert.Equal(t, "u1", client.calls[0][0].PartitionKey)
+	assert.NotEmpty(t, client.calls[0][1].PartitionKey)
+	assert.False(t, strings.HasSuffix(string(client.calls[0][0].Data), "\n"))
+	assert.Equal(t, client.calls[0][1:], client.calls[1])
+}
+
+func TestKinesisWriterFirehose(t *testing.T) {
+	client := &recordingKinesis{failures: [][]int{{0}, {0}, {0}, {0}}}
+	writer, err := NewKinesisWriter(KinesisWriterConfiguration{
+		Client: client, Service: KinesisFirehose, Stream: "logs", RetryBackoff: time.Millisecond,
+	})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte("{}\n"))
+	assert.Nil(t, err)
+
+	err = writer.Flush()
+	assert.ErrorAs(t, err, &KinesisRecordsError{})
+	assert.Len(t, client.calls, 4)
+	assert.Equal(t, "{}\n", string(client.calls[0][0].Data))
+
+	records := make([]KinesisRecord, 1001)
+	assert.Len(t, writer.split(records), 3)
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"fmt"
+)
+
+// BadKey key of values without a valid string key in WithKV
+const BadKey = "!BADKEY"
+
+// WithKV adds alternating key/value pairs, eg: WithKV("user", u, "attempt", 2), under a single lock.
+// non string keys and a trailing value without key are logged under BadKey
+func (i *innerJsonLog) WithKV(keyvals ...any) Interface {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	fields := i.groupFields()
+	for n := 0; n < len(keyvals); n += 2 {
+		if n+1 == len(keyvals) {
+			i.putField(fields, BadKey, keyvals[n])
+			break
+		}
+
+		key, ok := keyvals[n].(string)
+		if !ok {
+			key = fmt.Sprintf("%s(%v)", BadKey, keyvals[n])
+		}
+		i.putField(fields, key, keyvals[n+1])
+	}
+
+	return i
+}
+
+// WithKV adds alternating key/value pairs, eg: WithKV("user", u, "attempt", 2)
+func (i *JsonLogger) WithKV(keyvals ...any) Interface {
+	segment := i.segment(make(map[string]any, len(keyvals)/2+1))
+
+	return segment.WithKV(keyvals...)
+}
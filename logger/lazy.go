@@ -0,0 +1,7 @@
+package logger
+
+// Lazy field value computed only when an entry carrying it is written, so expensive values
+// aren't built for entries below the logger level. it is called once per written entry
+//
+//	log.With("stats", logger.Lazy(func() any { return db.Stats() })).Debug("pool")
+type Lazy func() any
@@ -0,0 +1,34 @@
+package logger
+
+import "strings"
+
+// LevelFormat how the level is written under the level field
+type LevelFormat string
+
+// supported level formats
+const (
+	// UpperLevelFormat default, eg: WARN
+	UpperLevelFormat LevelFormat = "upper"
+
+	// LowerLevelFormat eg: warn
+	LowerLevelFormat LevelFormat = "lower"
+
+	// NumericLevelFormat the level value, lower is more severe
+	NumericLevelFormat LevelFormat = "numeric"
+)
+
+// levelValue returns the level as configured, custom LevelNames take precedence over LevelFormat
+func (i *JsonLogger) levelValue(level LogLevelEnum) any {
+	if name, ok := i.LevelNames[level]; ok {
+		return name
+	}
+
+	switch i.LevelFormat {
+	case LowerLevelFormat:
+		return strings.ToLower(level.String())
+	case NumericLevelFormat:
+		return int(level)
+	default:
+		return level.String()
+	}
+}
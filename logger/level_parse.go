@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// levelAliases names accepted by ParseLogLevel besides the level names
+var levelAliases = map[string]LogLevelEnum{
+	"CRITICAL": FATAL,
+	"ERR":      ERROR,
+	"WARNING":  WARN,
+	"INFO":     LOG,
+	"TRACE":    DEBUG,
+}
+
+// ParseLogLevel returns the level named name, case insensitive. aliases like INFO (LOG) or WARNING (WARN)
+// and numeric levels are accepted too
+func ParseLogLevel(name string) (LogLevelEnum, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, level := range []LogLevelEnum{FATAL, ERROR, WARN, LOG, DEBUG} {
+		if name == level.String() {
+			return level, nil
+		}
+	}
+
+	if level, ok := levelAliases[name]; ok {
+		return level, nil
+	}
+
+	if n, err := strconv.Atoi(name); err == nil {
+		return LogLevelEnum(n), nil
+	}
+
+	return 0, fmt.Errorf("unknown log level %q", name)
+}
+
+// MarshalText writes the level name, or its number when the level is unknown
+func (l LogLevelEnum) MarshalText() ([]byte, error) {
+	if l < FATAL || l > DEBUG {
+		return []byte(strconv.Itoa(int(l))), nil
+	}
+
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText reads the level with ParseLogLevel
+func (l *LogLevelEnum) UnmarshalText(text []byte) error {
+	level, err := ParseLogLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = level
+	return nil
+}
+
+// UnmarshalJSON reads the level from its name or, as before names were supported, its number
+func (l *LogLevelEnum) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*l = LogLevelEnum(n)
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("log level must be a name or a number: %w", err)
+	}
+
+	return l.UnmarshalText([]byte(name))
+}
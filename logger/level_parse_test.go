@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"encoding/json"
+	"github.com/pixie-sh/logger-go/mapper"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	cases := map[string]LogLevelEnum{
+		"debug":    DEBUG,
+		"LOG":      LOG,
+		"info":     LOG,
+		"Warning":  WARN,
+		" error ":  ERROR,
+		"critical": FATAL,
+		"2":        LOG,
+	}
+	for name, expected := range cases {
+		level, err := ParseLogLevel(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, level, name)
+	}
+
+	_, err := ParseLogLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLogLevelMarshaling(t *testing.T) {
+	var cfg Configuration
+	assert.NoError(t, json.Unmarshal([]byte(`{"app":"a","level":"debug"}`), &cfg))
+	assert.Equal(t, DEBUG, cfg.LogLevel)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"level":1}`), &cfg))
+	assert.Equal(t, WARN, cfg.LogLevel)
+	assert.Error(t, json.Unmarshal([]byte(`{"level":"loud"}`), &cfg))
+
+	data, err := json.Marshal(Configuration{LogLevel: ERROR})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"level":"ERROR"`)
+
+	text, err := LogLevelEnum(9).MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "9", string(text))
+
+	assert.NoError(t, mapper.ObjectToStruct(map[string]any{"level": "info"}, &cfg))
+	assert.Equal(t, LOG, cfg.LogLevel)
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+	"reflect"
+)
+
+// LevelRoute destination of the entries of Levels: either Writer or the writer of a logger created
+// by Driver with Values, eg: error and fatal to file_logger_driver
+type LevelRoute struct {
+	Levels []LogLevelEnum `toml:"levels" json:"levels" mapstructure:"levels"`
+
+	// Min and Max route the levels between them by severity, on top of Levels, eg: Min WARN routes WARN,
+	// ERROR and FATAL. Min defaults to DEBUG and Max to FATAL when only one of them is set
+	Min *LogLevelEnum `toml:"min" json:"min" mapstructure:"min"`
+	Max *LogLevelEnum `toml:"max" json:"max" mapstructure:"max"`
+
+	Writer io.Writer
+	Driver string `toml:"driver" json:"driver" mapstructure:"driver"`
+	Values any    `toml:"values" json:"values" mapstructure:"values"`
+}
+
+// levels returns Levels plus the ones between Min and Max
+func (r LevelRoute) levels() ([]LogLevelEnum, error) {
+	levels := append([]LogLevelEnum{}, r.Levels...)
+	if r.Min == nil && r.Max == nil {
+		return levels, nil
+	}
+
+	least, most := DEBUG, FATAL
+	if r.Min != nil {
+		least = *r.Min
+	}
+	if r.Max != nil {
+		most = *r.Max
+	}
+
+	if most > least {
+		return nil, fmt.Errorf("level route min %s is more severe than max %s", least, most)
+	}
+
+	for level := most; level <= least; level++ {
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// LevelRouterWriter writes every entry to the writers routed for its level, eg: DEBUG to stdout,
+// ERROR to a file and a webhook. entries of levels without routes are dropped
+type LevelRouterWriter struct {
+	routes  map[LogLevelEnum][]io.Writer
+	writers []io.Writer
+}
+
+// NewLevelRouterWriter returns a level router without routes
+func NewLevelRouterWriter() *LevelRouterWriter {
+	return &LevelRouterWriter{routes: map[LogLevelEnum][]io.Writer{}}
+}
+
+// Route routes the entries of levels to w
+func (r *LevelRouterWriter) Route(w io.Writer, levels ...LogLevelEnum) *LevelRouterWriter {
+	for _, level := range levels {
+		r.routes[level] = append(r.routes[level], w)
+	}
+
+	for _, known := range r.writers {
+		if sameWriter(known, w) {
+			return r
+		}
+	}
+
+	r.writers = append(r.writers, w)
+	return r
+}
+
+// Write writes p as a LOG entry, the level being unknown. wrapping writers forward the level through WriteLevel
+func (r *LevelRouterWriter) Write(p []byte) (int, error) {
+	return r.WriteLevel(LOG, p)
+}
+
+// WriteLevel writes p to the writers routed for level
+func (r *LevelRouterWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	var errs []error
+	for _, w := range r.routes[level] {
+		if _, err := writeLevel(w, level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(p), errors.Join(errs...)
+}
+
+// MostVerbose returns the most verbose routed level, ERROR when there are no routes
+func (r *LevelRouterWriter) MostVerbose() LogLevelEnum {
+	verbose := ERROR
+	for level := range r.routes {
+		if level > verbose {
+			verbose = level
+		}
+	}
+
+	return verbose
+}
+
+// Flush flushes every routed writer
+func (r *LevelRouterWriter) Flush() error {
+	var errs []error
+	for _, w := range r.writers {
+		errs = append(errs, flushWriter(w))
+	}
+
+	return errors.Join(errs...)
+}
+
+// flushQueued drains the buffers of every routed writer, without syncing files
+func (r *LevelRouterWriter) flushQueued() {
+	for _, w := range r.writers {
+		flushBuffered(w)
+	}
+}
+
+// Close flushes and closes every routed writer once, standard streams are left open
+func (r *LevelRouterWriter) Close() error {
+	var errs []error
+	for _, w := range r.writers {
+		errs = append(errs, closeWriter(w))
+	}
+
+	return errors.Join(errs...)
+}
+
+// sameWriter reports whether a and b are the same writer, writers of non comparable types never are
+func sameWriter(a io.Writer, b io.Writer) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+
+	return a == b
+}
+
+// createRoutedLogger json logger over the routes of generic, configured by its JSONLoggerConfiguration values.
+// LogLevel caps the routed levels, the logger level being lowered to the most verbose route
+func (f *Factory) createRoutedLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	if generic.Driver != "" && generic.Driver != JSONLoggerDriver {
+		return nil, fmt.Errorf("level routes require the %s driver, got %s", JSONLoggerDriver, generic.Driver)
+	}
+
+	var cfg JSONLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	router := NewLevelRouterWriter()
+	for _, route := range generic.Routes {
+		levels, err := route.levels()
+		if err != nil {
+			_ = router.Close()
+			return nil, err
+		}
+
+		w := route.Writer
+		if w == nil {
+			destination := generic
+			destination.LogLevel = DEBUG
+			destination.Driver = route.Driver
+			destination.Values = route.Values
+			destination.Routes = nil
+
+			w, err = driverWriter(ctx, f.createMap, destination)
+			if err != nil {
+				_ = router.Close()
+				return nil, err
+			}
+		}
+
+		router.Route(w, levels...)
+	}
+
+	// entries no route accepts aren't encoded
+	cfg.Writer = router
+	generic.LogLevel = min(generic.LogLevel, router.MostVerbose())
+	return newConfiguredJsonLogger(ctx, generic, cfg)
+}
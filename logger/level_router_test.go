@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"context"
+	"github.com/pixie-sh/logger-go/mapper"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevelRouterWriter(t *testing.T) {
+	debug, errors := &syncWriter{}, &syncWriter{}
+	router := NewLevelRouterWriter().
+		Route(debug, DEBUG, LOG).
+		Route(errors, ERROR, FATAL).
+		Route(debug, FATAL)
+
+	_, _ = router.WriteLevel(DEBUG, []byte("debug\n"))
+	_, _ = router.WriteLevel(WARN, []byte("warn\n"))
+	_, _ = router.WriteLevel(FATAL, []byte("fatal\n"))
+
+	assert.Equal(t, "debug\nfatal\n", debug.String())
+	assert.Equal(t, "fatal\n", errors.String())
+
+	_, _ = router.Write([]byte("unleveled\n"))
+	assert.Equal(t, "debug\nfatal\nunleveled\n", debug.String(), "entries without level are routed as LOG")
+	assert.Equal(t, "fatal\n", errors.String())
+	assert.Equal(t, DEBUG, router.MostVerbose())
+
+	assert.Nil(t, router.Close())
+	assert.Equal(t, 1, debug.closed, "writers routed twice are closed once")
+	assert.Equal(t, 1, errors.closed)
+}
+
+func TestFactoryLevelRoutes(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	stdout, webhook := &syncWriter{}, &syncWriter{}
+	path := filepath.Join(t.TempDir(), "errors.log")
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: DEBUG,
+		Routes: []LevelRoute{
+			{Levels: []LogLevelEnum{DEBUG}, Writer: stdout},
+			{Levels: []LogLevelEnum{ERROR}, Driver: FileLoggerDriver, Values: map[string]any{"path": path}},
+			{Levels: []LogLevelEnum{ERROR}, Writer: webhook},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Debug("debug entry")
+	log.Warn("unrouted entry")
+	log.Error("error entry")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 1, strings.Count(stdout.String(), "\n"))
+	assert.Contains(t, stdout.String(), "debug entry")
+	assert.Equal(t, 1, strings.Count(webhook.String(), "\n"))
+	assert.Contains(t, webhook.String(), "error entry")
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "\n"))
+	assert.Contains(t, string(content), "error entry")
+
+	_, err = factory.Create(context.Background(), Configuration{
+		Driver: SQLLoggerDriver,
+		Routes: []LevelRoute{{Levels: []LogLevelEnum{ERROR}, Writer: webhook}},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestFactoryLevelRouteRanges(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	verbose, severe := &syncWriter{}, &syncWriter{}
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Routes: []LevelRoute{
+			{Max: levelPtr(LOG), Writer: verbose},
+			{Min: levelPtr(WARN), Writer: severe},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Debug("capped entry")
+	log.Log("log entry")
+	log.Warn("warn entry")
+	log.Error("error entry")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 1, strings.Count(verbose.String(), "\n"), "LogLevel caps the routes")
+	assert.Contains(t, verbose.String(), "log entry")
+	assert.Equal(t, 2, strings.Count(severe.String(), "\n"))
+	assert.NotContains(t, severe.String(), "log entry")
+
+	_, err = factory.Create(context.Background(), Configuration{
+		LogLevel: DEBUG,
+		Routes:   []LevelRoute{{Min: levelPtr(ERROR), Max: levelPtr(WARN), Writer: severe}},
+	})
+	assert.NotNil(t, err, "min more severe than max")
+
+	var route LevelRoute
+	assert.Nil(t, mapper.ObjectToStruct(map[string]any{"min": "error", "max": "fatal"}, &route))
+	levels, err := route.levels()
+	assert.Nil(t, err)
+	assert.Equal(t, []LogLevelEnum{FATAL, ERROR}, levels)
+}
+
+func levelPtr(level LogLevelEnum) *LogLevelEnum {
+	return &level
+}
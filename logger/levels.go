@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// LevelController runtime adjustable level, with per scope overrides, shared by every logger using it
+type LevelController struct {
+	level atomic.Int64
+
+	mu     sync.RWMutex
+	scopes map[string]LogLevelEnum
+}
+
+// NewLevelController returns a controller starting at level
+func NewLevelController(level LogLevelEnum) *LevelController {
+	c := &LevelController{scopes: map[string]LogLevelEnum{}}
+	c.level.Store(int64(level))
+	return c
+}
+
+// Level returns the level of scopes without override
+func (c *LevelController) Level() LogLevelEnum {
+	return LogLevelEnum(c.level.Load())
+}
+
+// SetLevel changes the level of scopes without override
+func (c *LevelController) SetLevel(level LogLevelEnum) {
+	c.level.Store(int64(level))
+}
+
+// SetScopeLevel overrides the level of scope
+func (c *LevelController) SetScopeLevel(scope string, level LogLevelEnum) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.scopes[scope] = level
+}
+
+// ResetScopeLevel removes the override of scope
+func (c *LevelController) ResetScopeLevel(scope string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.scopes, scope)
+}
+
+// ScopeLevels returns a copy of the scope overrides
+func (c *LevelController) ScopeLevels() map[string]LogLevelEnum {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	scopes := make(map[string]LogLevelEnum, len(c.scopes))
+	for scope, level := range c.scopes {
+		scopes[scope] = level
+	}
+	return scopes
+}
+
+// LevelFor returns the effective level of scope
+func (c *LevelController) LevelFor(scope string) LogLevelEnum {
+	c.mu.RLock()
+	level, ok := c.scopes[scope]
+	c.mu.RUnlock()
+
+	if ok {
+		return level
+	}
+	return c.Level()
+}
+
+// Enabled check if entries at level are logged, eg: to skip building expensive fields
+func (i *JsonLogger) Enabled(level LogLevelEnum) bool {
+	return i.level() >= level
+}
+
+// level returns the logger effective level, driven by Levels when set
+func (i *JsonLogger) level() LogLevelEnum {
+	if i.Levels != nil {
+		return i.Levels.LevelFor(i.Scope)
+	}
+
+	return i.LogLevel
+}
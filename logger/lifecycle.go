@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// Syncer optional interface for loggers holding buffered or closable outputs
+type Syncer interface {
+	Flush() error
+	Close() error
+}
+
+var (
+	syncersMu sync.Mutex
+	syncers   = map[Syncer]struct{}{}
+
+	// unkeyedSyncers registered syncers of non comparable types, they can't be set keys
+	unkeyedSyncers []Syncer
+)
+
+// RegisterSyncer registers s to be flushed and closed by FlushAll and CloseAll, once however many times
+// it's registered. syncers of non comparable types, eg: structs holding a slice, are registered on every call
+// and stay registered until CloseAll
+func RegisterSyncer(s Syncer) {
+	if s == nil {
+		return
+	}
+
+	syncersMu.Lock()
+	defer syncersMu.Unlock()
+
+	if !reflect.TypeOf(s).Comparable() {
+		unkeyedSyncers = append(unkeyedSyncers, s)
+		return
+	}
+
+	syncers[s] = struct{}{}
+}
+
+// registeredSyncers returns the registered syncers, callers must hold syncersMu
+func registeredSyncers() []Syncer {
+	registered := make([]Syncer, 0, len(syncers)+len(unkeyedSyncers))
+	for s := range syncers {
+		registered = append(registered, s)
+	}
+
+	return append(registered, unkeyedSyncers...)
+}
+
+// registerOwned registers the writer of log, created and owned by the factory, rather than log itself,
+// so the registry holds one entry per output however many loggers are created on it
+func registerOwned(log Interface) {
+	if w, ok := log.(interface{ Writer() io.Writer }); ok {
+		if s, ok := ownedWriterSyncer(w.Writer()); ok {
+			RegisterSyncer(s)
+			return
+		}
+	}
+
+	if s, ok := log.(Syncer); ok {
+		RegisterSyncer(s)
+	}
+}
+
+// ownedWriter registered output, unregistered once closed
+type ownedWriter struct {
+	writer io.Writer
+}
+
+// ownedWriterSyncer returns the registry entry of w, writers of non comparable types can't be registered
+func ownedWriterSyncer(w io.Writer) (Syncer, bool) {
+	if w == nil || !reflect.TypeOf(w).Comparable() {
+		return nil, false
+	}
+
+	return ownedWriter{writer: w}, true
+}
+
+// Flush flushes the output
+func (o ownedWriter) Flush() error {
+	return flushWriter(o.writer)
+}
+
+// Close flushes and closes the output, unregistering it
+func (o ownedWriter) Close() error {
+	unregisterSyncer(o)
+	return closeWriter(o.writer)
+}
+
+// Rotate rotates the output
+func (o ownedWriter) Rotate() error {
+	return rotateWriter(o.writer, Rotator.Rotate)
+}
+
+// Reopen reopens the output
+func (o ownedWriter) Reopen() error {
+	return rotateWriter(o.writer, Rotator.Reopen)
+}
+
+// FlushAll flushes every registered Syncer
+func FlushAll() error {
+	syncersMu.Lock()
+	defer syncersMu.Unlock()
+
+	var errs []error
+	for _, s := range registeredSyncers() {
+		errs = append(errs, s.Flush())
+	}
+
+	return errors.Join(errs...)
+}
+
+// CloseAll closes and unregisters every registered Syncer
+func CloseAll() error {
+	syncersMu.Lock()
+	closing := registeredSyncers()
+	syncers = map[Syncer]struct{}{}
+	unkeyedSyncers = nil
+	syncersMu.Unlock()
+
+	var errs []error
+	for _, s := range closing {
+		errs = append(errs, s.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// unregisterSyncer removes s from the registry, syncers of non comparable types are left to CloseAll
+func unregisterSyncer(s Syncer) {
+	if s == nil || !reflect.TypeOf(s).Comparable() {
+		return
+	}
+
+	syncersMu.Lock()
+	defer syncersMu.Unlock()
+
+	delete(syncers, s)
+}
+
+// flushWriter flushes w if it supports it. standard streams are never synced,
+// fsync on terminals and pipes fails
+func flushWriter(w io.Writer) error {
+	switch w := w.(type) {
+	case interface{ Flush() error }:
+		return w.Flush()
+	case *os.File:
+		if w == os.Stdout || w == os.Stderr {
+			return nil
+		}
+		return w.Sync()
+	case interface{ Sync() error }:
+		return w.Sync()
+	}
+
+	return nil
+}
+
+// flushBuffered flushes w only when it buffers entries in memory, without syncing files.
+// queued writers only wait for the entries queued so far, bounded by a timeout
+func flushBuffered(w io.Writer) {
+	switch w := w.(type) {
+	case interface{ flushQueued() }:
+		w.flushQueued()
+	case interface{ Flush() error }:
+		_ = w.Flush()
+	}
+}
+
+// closeWriter flushes and closes w if it supports it, standard streams are left open
+func closeWriter(w io.Writer) error {
+	err := flushWriter(w)
+	if w == os.Stdout || w == os.Stderr {
+		return err
+	}
+
+	if c, ok := w.(io.Closer); ok {
+		return errors.Join(err, c.Close())
+	}
+
+	return err
+}
+
+// Flush writes the pending error sampler summaries and flushes the underlying writer
+func (i *JsonLogger) Flush() error {
+	if i.ErrorSampler != nil {
+		i.ErrorSampler.Flush()
+	}
+
+	return flushWriter(i.writer)
+}
+
+// Close flushes and closes the underlying writer, unregistering the logger and its writer
+func (i *JsonLogger) Close() error {
+	if i.ErrorSampler != nil {
+		i.ErrorSampler.Flush()
+	}
+
+	unregisterSyncer(i)
+	if s, ok := ownedWriterSyncer(i.writer); ok {
+		unregisterSyncer(s)
+	}
+
+	return closeWriter(i.writer)
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// LevelWriter implemented by writers handling entries differently per level, eg: event log types.
+// the logger calls WriteLevel instead of Write when available
+type LevelWriter interface {
+	WriteLevel(level LogLevelEnum, p []byte) (int, error)
+}
+
+// orphanWriteMu serializes writes of loggers built without NewJsonLogger
+var orphanWriteMu sync.Mutex
+
+// writeLocks locks of the writers a logger tree writes to, shared by the logger NewJsonLogger creates and
+// every logger derived from it, so it's released with the tree instead of living for the process
+type writeLocks struct {
+	mu    sync.Mutex
+	locks map[io.Writer]*sync.Mutex
+}
+
+// newWriteLocks returns the locks of a new logger tree writing to w
+func newWriteLocks(w io.Writer) (*writeLocks, *sync.Mutex) {
+	l := &writeLocks{locks: map[io.Writer]*sync.Mutex{}}
+	return l, l.lock(w)
+}
+
+// lock returns the lock of w, writers of non comparable types can't be shared and get their own
+func (l *writeLocks) lock(w io.Writer) *sync.Mutex {
+	if l == nil || w == nil || !reflect.TypeOf(w).Comparable() {
+		return new(sync.Mutex)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	mu, ok := l.locks[w]
+	if !ok {
+		mu = new(sync.Mutex)
+		l.locks[w] = mu
+	}
+
+	return mu
+}
+
+// maxPooledLine lines above it aren't pooled, so a burst of huge entries doesn't pin memory
+const maxPooledLine = 64 * 1024
+
+// linePool reused entry buffers, writers must not retain the written slice, as io.Writer requires
+var linePool = sync.Pool{
+	New: func() any {
+		line := make([]byte, 0, 1024)
+		return &line
+	},
+}
+
+func getLine() *[]byte {
+	return linePool.Get().(*[]byte)
+}
+
+func putLine(line *[]byte) {
+	if cap(*line) > maxPooledLine {
+		return
+	}
+
+	*line = (*line)[:0]
+	linePool.Put(line)
+}
+
+// writeLine writes the encoded line, newline included, in a single Write call,
+// serialized with every logger sharing the writer lock, so lines never interleave
+func (i *JsonLogger) writeLine(level LogLevelEnum, line []byte) {
+	mu := i.writeMu
+	if mu == nil {
+		mu = &orphanWriteMu
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, _ = writeLevel(i.writer, level, line)
+}
+
+// writeLevel writes p through WriteLevel when w is a LevelWriter, through Write otherwise
+func writeLevel(w io.Writer, level LogLevelEnum, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.Write(p)
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"context"
+	"github.com/pixie-sh/logger-go/mapper"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// entry keys rendered first by the logfmt encoder, under their logfmt names
+var logfmtPrefixKeys = []struct{ entry, logfmt string }{
+	{"timestamp", "ts"},
+	{"level", "level"},
+	{"message", "msg"},
+}
+
+// LogfmtEncoder logfmt encoder: ts, level and msg followed by the other fields as sorted key=value pairs.
+// nested fields are flattened to dotted keys, e.g. http.method, and values quoted when needed
+type LogfmtEncoder struct{}
+
+// Encode renders the entry as a single logfmt line
+func (LogfmtEncoder) Encode(entry map[string]any) ([]byte, error) {
+	var b strings.Builder
+	fields := make(map[string]any, len(entry))
+	for key, value := range entry {
+		logfmtFlatten(fields, key, value)
+	}
+
+	for _, prefix := range logfmtPrefixKeys {
+		value, ok := fields[prefix.entry]
+		if !ok {
+			continue
+		}
+		delete(fields, prefix.entry)
+
+		if err := logfmtPair(&b, prefix.logfmt, value); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := logfmtPair(&b, key, fields[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// logfmtFlatten adds value to fields under key, groups and objects flattened to dotted keys
+func logfmtFlatten(fields map[string]any, key string, value any) {
+	var nested map[string]any
+	switch v := value.(type) {
+	case fieldGroup:
+		nested = v
+	case map[string]any:
+		nested = v
+	default:
+		fields[key] = value
+		return
+	}
+
+	for k, v := range nested {
+		logfmtFlatten(fields, key+"."+k, v)
+	}
+}
+
+func logfmtPair(b *strings.Builder, key string, value any) error {
+	text, err := textValue(value)
+	if err != nil {
+		return err
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(logfmtKey(key))
+	b.WriteByte('=')
+	b.WriteString(logfmtValue(text))
+	return nil
+}
+
+// logfmtKey replaces the characters logfmt keys don't allow with _
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// logfmtValue quotes value when empty or holding spaces, =, quotes or control characters
+func logfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == unicode.ReplacementChar || !unicode.IsPrint(r) {
+			return strconv.Quote(value)
+		}
+	}
+
+	return value
+}
+
+func createLogfmtLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg JSONLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Writer == nil {
+		cfg.Writer = os.Stdout //default
+	}
+
+	if cfg.Encoder == nil {
+		cfg.Encoder = LogfmtEncoder{}
+	}
+
+	return newConfiguredJsonLogger(ctx, generic, cfg)
+}
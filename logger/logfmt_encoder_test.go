@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	line, err := LogfmtEncoder{}.Encode(map[string]any{
+		"timestamp": "2024-01-02T03:04:05Z",
+		"level":     "WARN",
+		"message":   `slow "db" request`,
+		"app":       "App",
+		"empty":     "",
+		"n":         2,
+		"ok":        true,
+		"bad key":   "a=b",
+		"ctx":       map[string]any{"trace_id": "t1"},
+		"http":      fieldGroup{"method": "GET"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, `ts=2024-01-02T03:04:05Z level=WARN msg="slow \"db\" request" app=App bad_key="a=b" ctx.trace_id=t1 empty="" http.method=GET n=2 ok=true`, string(line))
+}
+
+func TestFactoryLogfmtDriver(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	buf := new(bytes.Buffer)
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   LogfmtLoggerDriver,
+		Values:   JSONLoggerConfiguration{Writer: buf},
+	})
+	assert.Nil(t, err)
+
+	log.With("user", "u1").Log("signed in")
+
+	line := buf.String()
+	assert.True(t, strings.HasPrefix(line, "ts="), line)
+	assert.Contains(t, line, " level=LOG msg=\"signed in\" app=App ")
+	assert.Contains(t, line, " scope=Scope ")
+	assert.True(t, strings.HasSuffix(line, " user=u1\n"), line)
+}
@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLokiLabels entry fields promoted to loki labels by default
+var DefaultLokiLabels = []string{"app", "scope", "level"}
+
+// LokiWriterConfiguration loki sink configuration, URL is the push endpoint, eg: http://loki:3100/loki/api/v1/push
+type LokiWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	// Labels dotted paths of the entry fields promoted to labels, removed from the line. defaults to DefaultLokiLabels
+	Labels []string `toml:"labels" json:"labels" mapstructure:"labels"`
+
+	// StaticLabels labels added to every stream, eg: env
+	StaticLabels map[string]string `toml:"staticLabels" json:"staticLabels" mapstructure:"staticLabels"`
+
+	// TenantID sent as X-Scope-OrgID on multi tenant deployments
+	TenantID string `toml:"tenantId" json:"tenantId" mapstructure:"tenantId"`
+}
+
+// LokiLoggerConfiguration json logger pushing to loki
+type LokiLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	LokiWriterConfiguration `mapstructure:",squash"`
+}
+
+// LokiWriter batches entries into loki push requests, grouped in streams by their label values
+type LokiWriter struct {
+	cfg    LokiWriterConfiguration
+	sender *httpSender
+	batch  *batcher
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewLokiWriter returns a loki writer pushing to cfg.URL
+func NewLokiWriter(cfg LokiWriterConfiguration) (*LokiWriter, error) {
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Labels) == 0 {
+		cfg.Labels = DefaultLokiLabels
+	}
+
+	w := &LokiWriter{cfg: cfg, sender: sender}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.push)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next push
+func (w *LokiWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush pushes the pending entries
+func (w *LokiWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close pushes the pending entries
+func (w *LokiWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *LokiWriter) push(batch [][]byte) error {
+	body, err := json.Marshal(map[string]any{"streams": w.streams(batch, time.Now())})
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	if w.cfg.TenantID != "" {
+		header.Set("X-Scope-OrgID", w.cfg.TenantID)
+	}
+
+	_, err = w.sender.send("", header, body)
+	return err
+}
+
+// streams groups the entries by labels, values in chronological order. entries that aren't json are pushed as is
+func (w *LokiWriter) streams(batch [][]byte, now time.Time) []*lokiStream {
+	var streams []*lokiStream
+	byLabels := map[string]*lokiStream{}
+	for _, entry := range batch {
+		labels := make(map[string]string, len(w.cfg.Labels)+len(w.cfg.StaticLabels))
+		for name, value := range w.cfg.StaticLabels {
+			labels[name] = value
+		}
+
+		timestamp := now
+		line := strings.TrimRight(string(entry), "\n")
+		if fields, err := decodeEntry(entry); err == nil {
+			timestamp = entryTime(fields, now)
+			for _, path := range w.cfg.Labels {
+				if value, ok := lookupField(fields, path); ok {
+					labels[lokiLabelName(path)] = fieldString(value)
+					removeField(fields, path)
+				}
+			}
+
+			if encoded, err := json.Marshal(fields); err == nil {
+				line = string(encoded)
+			}
+		}
+
+		key := lokiStreamKey(labels)
+		stream, ok := byLabels[key]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			byLabels[key] = stream
+			streams = append(streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(timestamp.UnixNano(), 10), line})
+	}
+
+	for _, stream := range streams {
+		sort.SliceStable(stream.Values, func(a, b int) bool {
+			return len(stream.Values[a][0]) < len(stream.Values[b][0]) ||
+				len(stream.Values[a][0]) == len(stream.Values[b][0]) && stream.Values[a][0] < stream.Values[b][0]
+		})
+	}
+
+	return streams
+}
+
+// lokiLabelName label name of a dotted path, invalid characters replaced with _
+func lokiLabelName(path string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, path)
+}
+
+// lokiStreamKey identifies a label set
+func lokiStreamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func createLokiLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg LokiLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewLokiWriter(cfg.LokiWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLokiDriver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+		pushes   []map[string][]lokiStream
+		tenants  []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var push map[string][]lokiStream
+		assert.Nil(t, json.Unmarshal(body, &push))
+		pushes = append(pushes, push)
+		tenants = append(tenants, r.Header.Get("X-Scope-OrgID"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   LokiLoggerDriver,
+		Values: map[string]any{
+			"url":          server.URL,
+			"tenantId":     "team",
+			"staticLabels": map[string]string{"env": "test"},
+			"retryBackoff": time.Millisecond,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("first")
+	log.Warn("second")
+	log.Log("third")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, []string{"team"}, tenants)
+
+	streams := pushes[0]["streams"]
+	assert.Len(t, streams, 2)
+	assert.Equal(t, map[string]string{"app": "App", "scope": "Scope", "level": "LOG", "env": "test"}, streams[0].Stream)
+	assert.Len(t, streams[0].Values, 2)
+	assert.Equal(t, "WARN", streams[1].Stream["level"])
+
+	var line map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(streams[0].Values[0][1]), &line))
+	assert.Equal(t, "first", line["message"])
+	assert.NotContains(t, line, "app")
+	assert.NotContains(t, line, "level")
+}
+
+func TestLokiLabelPaths(t *testing.T) {
+	writer, err := NewLokiWriter(LokiWriterConfiguration{
+		HTTPSinkConfiguration: HTTPSinkConfiguration{URL: "http://localhost"},
+		Labels:                []string{"ctx.tenant"},
+	})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	streams := writer.streams([][]byte{
+		[]byte(`{"message":"a","ctx":{"tenant":"t1"}}` + "\n"),
+		[]byte("plain\n"),
+	}, time.Unix(1, 0))
+
+	assert.Len(t, streams, 2)
+	assert.Equal(t, map[string]string{"ctx_tenant": "t1"}, streams[0].Stream)
+	assert.Equal(t, [2]string{"1000000000", `{"message":"a"}`}, streams[0].Values[0])
+	assert.Equal(t, "plain", streams[1].Values[0][1])
+}
@@ -0,0 +1,42 @@
+package logger
+
+// LogMarshaler implemented by types serializing themselves into log entries, without reflection.
+// only what MarshalLog adds to the encoder is logged
+type LogMarshaler interface {
+	MarshalLog(enc FieldEncoder)
+}
+
+// FieldEncoder receives the fields of a LogMarshaler
+type FieldEncoder interface {
+	AddString(key string, value string)
+	AddInt64(key string, value int64)
+	AddFloat64(key string, value float64)
+	AddBool(key string, value bool)
+	AddAny(key string, value any)
+	AddObject(key string, value LogMarshaler)
+}
+
+// Object returns a field logging value through its MarshalLog
+func Object(key string, value LogMarshaler) Field {
+	return Field{Key: key, Type: AnyType, Interface: value}
+}
+
+// mapFieldEncoder FieldEncoder building the entry object
+type mapFieldEncoder map[string]any
+
+func (m mapFieldEncoder) AddString(key string, value string)   { m[key] = value }
+func (m mapFieldEncoder) AddInt64(key string, value int64)     { m[key] = value }
+func (m mapFieldEncoder) AddFloat64(key string, value float64) { m[key] = value }
+func (m mapFieldEncoder) AddBool(key string, value bool)       { m[key] = value }
+func (m mapFieldEncoder) AddAny(key string, value any)         { m[key] = value }
+
+func (m mapFieldEncoder) AddObject(key string, value LogMarshaler) {
+	m[key] = marshalLog(value)
+}
+
+// marshalLog returns the object built by value MarshalLog
+func marshalLog(value LogMarshaler) map[string]any {
+	enc := mapFieldEncoder{}
+	value.MarshalLog(enc)
+	return enc
+}
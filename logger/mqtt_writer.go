@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"strings"
+	"time"
+)
+
+// MQTTPublisher publishes a message and waits for its delivery at qos, implemented by an adapter over
+// the application mqtt client, eg: paho waiting on the publish token, so no extra connection is opened
+type MQTTPublisher interface {
+	Publish(ctx context.Context, topic string, qos byte, retained bool, payload []byte) error
+}
+
+// MQTTWriterConfiguration mqtt sink configuration
+type MQTTWriterConfiguration struct {
+	Client MQTTPublisher
+	Topic  string `toml:"topic" json:"topic" mapstructure:"topic"`
+
+	// QoS 0 at most once, the default, 1 at least once or 2 exactly once
+	QoS      byte `toml:"qos" json:"qos" mapstructure:"qos"`
+	Retained bool `toml:"retained" json:"retained" mapstructure:"retained"`
+
+	// LevelTopics publishes to topic/level, eg: device/logs/error, so subscribers can filter with wildcards
+	LevelTopics bool `toml:"levelTopics" json:"levelTopics" mapstructure:"levelTopics"`
+
+	// Timeout delivery timeout
+	Timeout time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+}
+
+// MQTTLoggerConfiguration json logger publishing to mqtt
+type MQTTLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	MQTTWriterConfiguration `mapstructure:",squash"`
+}
+
+// MQTTWriter publishes every entry to an mqtt topic
+type MQTTWriter struct {
+	cfg MQTTWriterConfiguration
+}
+
+// NewMQTTWriter returns an mqtt writer publishing through cfg.Client
+func NewMQTTWriter(cfg MQTTWriterConfiguration) (*MQTTWriter, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("mqtt writer requires a Client")
+	}
+
+	if cfg.Topic == "" {
+		return nil, fmt.Errorf("mqtt writer requires a Topic")
+	}
+
+	if strings.ContainsAny(cfg.Topic, "+#") {
+		return nil, fmt.Errorf("mqtt topic %s can't hold wildcards", cfg.Topic)
+	}
+
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("unknown mqtt qos %d", cfg.QoS)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	return &MQTTWriter{cfg: cfg}, nil
+}
+
+// Write publishes p to the topic
+func (w *MQTTWriter) Write(p []byte) (int, error) {
+	return w.publish(w.cfg.Topic, p)
+}
+
+// WriteLevel publishes p to the topic of level
+func (w *MQTTWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	topic := w.cfg.Topic
+	if w.cfg.LevelTopics {
+		topic = strings.TrimRight(topic, "/") + "/" + strings.ToLower(level.String())
+	}
+
+	return w.publish(topic, p)
+}
+
+func (w *MQTTWriter) publish(topic string, p []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	if err := w.cfg.Client.Publish(ctx, topic, w.cfg.QoS, w.cfg.Retained, bytes.TrimRight(p, "\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func createMQTTLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg MQTTLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewMQTTWriter(cfg.MQTTWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type mqttMessage struct {
+	topic    string
+	qos      byte
+	retained bool
+	payload  string
+}
+
+type recordingMQTT struct {
+	messages []mqttMessage
+	err      error
+}
+
+func (c *recordingMQTT) Publish(_ context.Context, topic string, qos byte, retained bool, payload []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.messages = append(c.messages, mqttMessage{topic: topic, qos: qos, retained: retained, payload: string(payload)})
+	return nil
+}
+
+func TestMQTTDriver(t *testing.T) {
+	client := &recordingMQTT{}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   MQTTLoggerDriver,
+		Values:   map[string]any{"Client": client, "topic": "device/logs/", "qos": 1, "levelTopics": true},
+	})
+	assert.Nil(t, err)
+
+	log.Log("info")
+	log.Error("boom")
+
+	assert.Len(t, client.messages, 2)
+	assert.Equal(t, "device/logs/log", client.messages[0].topic)
+	assert.Equal(t, "device/logs/error", client.messages[1].topic)
+	assert.Equal(t, byte(1), client.messages[1].qos)
+	assert.False(t, client.messages[1].retained)
+	assert.Contains(t, client.messages[1].payload, `"message":"boom"`)
+	assert.NotContains(t, client.messages[1].payload, "\n")
+}
+
+func TestMQTTWriter(t *testing.T) {
+	client := &recordingMQTT{err: errors.New("disconnected")}
+	writer, err := NewMQTTWriter(MQTTWriterConfiguration{Client: client, Topic: "logs"})
+	assert.Nil(t, err)
+
+	n, err := writer.Write([]byte("entry\n"))
+	assert.Zero(t, n)
+	assert.NotNil(t, err)
+
+	_, err = NewMQTTWriter(MQTTWriterConfiguration{Client: client})
+	assert.NotNil(t, err)
+
+	_, err = NewMQTTWriter(MQTTWriterConfiguration{Client: client, Topic: "logs/#"})
+	assert.NotNil(t, err)
+
+	_, err = NewMQTTWriter(MQTTWriterConfiguration{Client: client, Topic: "logs", QoS: 3})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+)
+
+func init() {
+	DefaultFactoryConfiguration.Mapping[MultiLoggerDriver] = createMultiLogger
+}
+
+// MultiDestination MultiWriter destination, receiving entries up to Level
+type MultiDestination struct {
+	Writer io.Writer
+	Level  LogLevelEnum
+}
+
+// MultiWriter tees entries to several destinations, each one with its own level threshold,
+// eg: everything to stdout and ERROR entries to a file. writes are synchronous, in order
+type MultiWriter struct {
+	destinations []MultiDestination
+}
+
+// NewMultiWriter returns a multi writer over destinations
+func NewMultiWriter(destinations ...MultiDestination) *MultiWriter {
+	return &MultiWriter{destinations: destinations}
+}
+
+// Write writes p to every destination, the level being unknown
+func (w *MultiWriter) Write(p []byte) (int, error) {
+	var errs []error
+	for _, d := range w.destinations {
+		if _, err := d.Writer.Write(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(p), errors.Join(errs...)
+}
+
+// WriteLevel writes p to the destinations whose threshold allows level
+func (w *MultiWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	var errs []error
+	for _, d := range w.destinations {
+		if level > d.Level {
+			continue
+		}
+
+		var err error
+		if lw, ok := d.Writer.(LevelWriter); ok {
+			_, err = lw.WriteLevel(level, p)
+		} else {
+			_, err = d.Writer.Write(p)
+		}
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return len(p), errors.Join(errs...)
+}
+
+// Destinations returns the writer destinations
+func (w *MultiWriter) Destinations() []MultiDestination {
+	return w.destinations
+}
+
+// Flush flushes every destination
+func (w *MultiWriter) Flush() error {
+	var errs []error
+	for _, d := range w.destinations {
+		errs = append(errs, flushWriter(d.Writer))
+	}
+
+	return errors.Join(errs...)
+}
+
+// flushQueued drains the buffers of every destination, without syncing files
+func (w *MultiWriter) flushQueued() {
+	for _, d := range w.destinations {
+		flushBuffered(d.Writer)
+	}
+}
+
+// Close flushes and closes every destination, standard streams are left open
+func (w *MultiWriter) Close() error {
+	var errs []error
+	for _, d := range w.destinations {
+		errs = append(errs, closeWriter(d.Writer))
+	}
+
+	return errors.Join(errs...)
+}
+
+// MultiDestinationConfiguration destination of the multi logger driver: either Writer or
+// the writer of a logger created by Driver with Values, eg: file_logger_driver
+type MultiDestinationConfiguration struct {
+	Level  LogLevelEnum `toml:"level" json:"level" mapstructure:"level"`
+	Writer io.Writer
+	Driver string `toml:"driver" json:"driver" mapstructure:"driver"`
+	Values any    `toml:"values" json:"values" mapstructure:"values"`
+}
+
+// MultiLoggerConfiguration json logger writing to several destinations with their own levels.
+// the logger level must be at least as verbose as the most verbose destination
+type MultiLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	Destinations            []MultiDestinationConfiguration `toml:"destinations" json:"destinations" mapstructure:"destinations"`
+}
+
+func createMultiLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg MultiLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := NewMultiWriter()
+	for _, d := range cfg.Destinations {
+		dst, err := multiDestinationWriter(ctx, generic, d)
+		if err != nil {
+			_ = writer.Close()
+			return nil, err
+		}
+		writer.destinations = append(writer.destinations, MultiDestination{Writer: dst, Level: d.Level})
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
+
+// multiDestinationWriter returns the destination writer, creating its driver logger when needed
+func multiDestinationWriter(ctx context.Context, generic Configuration, d MultiDestinationConfiguration) (io.Writer, error) {
+	if d.Writer != nil {
+		return d.Writer, nil
+	}
+
+	generic.LogLevel = d.Level
+	generic.Driver = d.Driver
+	generic.Values = d.Values
+	return driverWriter(ctx, DefaultFactoryConfiguration.Mapping, generic)
+}
+
+// driverWriter creates the generic driver logger with mapping and returns its writer
+func driverWriter(ctx context.Context, mapping map[string]FactoryCreateFn, generic Configuration) (io.Writer, error) {
+	create, ok := mapping[generic.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown destination driver %s", generic.Driver)
+	}
+
+	log, err := create(ctx, generic)
+	if err != nil {
+		return nil, err
+	}
+
+	withWriter, ok := log.(interface{ Writer() io.Writer })
+	if !ok {
+		return nil, fmt.Errorf("destination driver %s doesn't expose its writer", generic.Driver)
+	}
+
+	return withWriter.Writer(), nil
+}
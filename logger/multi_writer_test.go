@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMultiWriter(t *testing.T) {
+	all, errors := new(bytes.Buffer), new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), NewMultiWriter(
+		MultiDestination{Writer: all, Level: DEBUG},
+		MultiDestination{Writer: errors, Level: ERROR},
+	), "TestApp", "TestScope", "TestUID", DEBUG, nil)
+
+	baseLogger.Debug("debug")
+	baseLogger.With("k", "v").Warn("warn")
+	baseLogger.Error("error")
+
+	assert.Equal(t, 3, strings.Count(all.String(), "\n"))
+	assert.Equal(t, 1, strings.Count(errors.String(), "\n"))
+	assert.Contains(t, errors.String(), `"message":"error"`)
+}
+
+func TestFactoryMultiDriver(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	all := &syncWriter{}
+	path := filepath.Join(t.TempDir(), "warn.log")
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: DEBUG,
+		Driver:   MultiLoggerDriver,
+		Values: map[string]any{
+			"destinations": []map[string]any{
+				{"level": "debug", "Writer": all},
+				{"level": "warn", "driver": FileLoggerDriver, "values": map[string]any{"path": path}},
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("info entry")
+	log.Warn("warn entry")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 2, strings.Count(all.String(), "\n"))
+	assert.Equal(t, 1, all.closed)
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "\n"))
+	assert.Contains(t, string(content), "warn entry")
+}
+
+func TestMultiWriterWrapped(t *testing.T) {
+	wrappers := map[string]func(io.Writer) (io.Writer, error){
+		"async": func(w io.Writer) (io.Writer, error) {
+			return NewAsyncWriter(w, AsyncWriterConfiguration{Policy: BlockPolicy})
+		},
+		"buffer": func(w io.Writer) (io.Writer, error) {
+			return NewBufferedWriter(w, BufferedWriterConfiguration{FlushInterval: time.Hour}), nil
+		},
+		"rate_limit": func(w io.Writer) (io.Writer, error) {
+			return NewRateLimitWriter(w, RateLimitWriterConfiguration{Rate: 100})
+		},
+		"fanout": func(w io.Writer) (io.Writer, error) {
+			return NewFanoutWriter(AsyncWriterConfiguration{Policy: BlockPolicy}, w)
+		},
+	}
+
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			all, errors := &syncWriter{}, &syncWriter{}
+			writer, err := wrap(NewMultiWriter(
+				MultiDestination{Writer: all, Level: DEBUG},
+				MultiDestination{Writer: errors, Level: ERROR},
+			))
+			assert.Nil(t, err)
+
+			baseLogger, _ := NewJsonLogger(context.Background(), writer, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+			baseLogger.Debug("debug")
+			baseLogger.Warn("warn")
+			baseLogger.Error("error")
+			assert.Nil(t, closeWriter(writer))
+
+			assert.Equal(t, 3, strings.Count(all.String(), "\n"))
+			assert.Equal(t, 1, strings.Count(errors.String(), "\n"), "the entry level reaches the destinations")
+			assert.Contains(t, errors.String(), `"message":"error"`)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"strings"
+	"time"
+)
+
+// NATSPublisher core nats publisher, eg: *nats.Conn
+type NATSPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// JetStreamPublisher publishes a message and waits for its acknowledgement, implemented by an adapter over
+// a jetstream context, discarding the ack
+type JetStreamPublisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// NATSWriterConfiguration nats sink configuration, JetStream takes precedence over Conn
+type NATSWriterConfiguration struct {
+	Conn      NATSPublisher
+	JetStream JetStreamPublisher
+	Subject   string `toml:"subject" json:"subject" mapstructure:"subject"`
+
+	// LevelSubjects publishes to subject.level, eg: logs.error, so subscribers can filter with wildcards
+	LevelSubjects bool `toml:"levelSubjects" json:"levelSubjects" mapstructure:"levelSubjects"`
+
+	// Timeout JetStream acknowledgement timeout
+	Timeout time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+}
+
+// NATSLoggerConfiguration json logger publishing to nats
+type NATSLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	NATSWriterConfiguration `mapstructure:",squash"`
+}
+
+// NATSWriter publishes every entry to a nats subject, with JetStream persistence when configured
+type NATSWriter struct {
+	cfg NATSWriterConfiguration
+}
+
+// NewNATSWriter returns a nats writer publishing through cfg.JetStream or cfg.Conn
+func NewNATSWriter(cfg NATSWriterConfiguration) (*NATSWriter, error) {
+	if cfg.Conn == nil && cfg.JetStream == nil {
+		return nil, fmt.Errorf("nats writer requires a Conn or JetStream")
+	}
+
+	if cfg.Subject == "" {
+		return nil, fmt.Errorf("nats writer requires a Subject")
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	return &NATSWriter{cfg: cfg}, nil
+}
+
+// Write publishes p to the subject
+func (w *NATSWriter) Write(p []byte) (int, error) {
+	return w.publish(w.cfg.Subject, p)
+}
+
+// WriteLevel publishes p to the subject of level
+func (w *NATSWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	subject := w.cfg.Subject
+	if w.cfg.LevelSubjects {
+		subject += "." + strings.ToLower(level.String())
+	}
+
+	return w.publish(subject, p)
+}
+
+// Flush flushes the connection buffer, when supported by Conn
+func (w *NATSWriter) Flush() error {
+	if f, ok := w.cfg.Conn.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close flushes the connection, which is left open
+func (w *NATSWriter) Close() error {
+	return w.Flush()
+}
+
+func (w *NATSWriter) publish(subject string, p []byte) (int, error) {
+	data := bytes.TrimRight(p, "\n")
+	if w.cfg.JetStream == nil {
+		if err := w.cfg.Conn.Publish(subject, data); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	if err := w.cfg.JetStream.Publish(ctx, subject, data); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func createNATSLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg NATSLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewNATSWriter(cfg.NATSWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
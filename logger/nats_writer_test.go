@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type natsMessage struct {
+	subject string
+	data    string
+}
+
+type recordingNATS struct {
+	messages []natsMessage
+	flushed  int
+}
+
+func (c *recordingNATS) Publish(subject string, data []byte) error {
+	c.messages = append(c.messages, natsMessage{subject: subject, data: string(data)})
+	return nil
+}
+
+func (c *recordingNATS) Flush() error {
+	c.flushed++
+	return nil
+}
+
+type recordingJetStream struct {
+	recordingNATS
+}
+
+func (js *recordingJetStream) Publish(_ context.Context, subject string, data []byte) error {
+	return js.recordingNATS.Publish(subject, data)
+}
+
+func TestNATSDriver(t *testing.T) {
+	conn := &recordingNATS{}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   NATSLoggerDriver,
+		Values:   map[string]any{"Conn": conn, "subject": "logs", "levelSubjects": true},
+	})
+	assert.Nil(t, err)
+
+	log.Log("info")
+	log.Error("boom")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, conn.messages, 2)
+	assert.Equal(t, "logs.log", conn.messages[0].subject)
+	assert.Equal(t, "logs.error", conn.messages[1].subject)
+	assert.Contains(t, conn.messages[1].data, `"message":"boom"`)
+	assert.NotContains(t, conn.messages[1].data, "\n")
+	assert.GreaterOrEqual(t, conn.flushed, 1)
+}
+
+func TestNATSWriterJetStream(t *testing.T) {
+	js := &recordingJetStream{}
+	writer, err := NewNATSWriter(NATSWriterConfiguration{JetStream: js, Subject: "logs"})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte("{}\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, []natsMessage{{subject: "logs", data: "{}"}}, js.messages)
+
+	_, err = NewNATSWriter(NATSWriterConfiguration{Subject: "logs"})
+	assert.Error(t, err)
+}
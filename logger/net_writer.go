@@ -0,0 +1,324 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// network writer defaults
+const (
+	DefaultNetBufferSize = 1000
+	DefaultNetMaxBackoff = 30 * time.Second
+)
+
+// NetWriterConfiguration tcp and udp sink configuration
+type NetWriterConfiguration struct {
+	// Address tcp://host:port or udp://host:port
+	Address string `toml:"address" json:"address" mapstructure:"address"`
+
+	// PoolSize connections entries are spread over, defaults to 1
+	PoolSize int `toml:"poolSize" json:"poolSize" mapstructure:"poolSize"`
+
+	// BufferSize entries kept while disconnected, the oldest are dropped beyond it. defaults to DefaultNetBufferSize
+	BufferSize int `toml:"bufferSize" json:"bufferSize" mapstructure:"bufferSize"`
+
+	Timeout time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// RetryBackoff first reconnect delay, doubled up to MaxBackoff
+	RetryBackoff time.Duration `toml:"retryBackoff" json:"retryBackoff" mapstructure:"retryBackoff"`
+	MaxBackoff   time.Duration `toml:"maxBackoff" json:"maxBackoff" mapstructure:"maxBackoff"`
+}
+
+// NetLoggerConfiguration json logger writing to a tcp or udp endpoint
+type NetLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	NetWriterConfiguration  `mapstructure:",squash"`
+}
+
+// netConn pooled connection, nil while disconnected
+type netConn struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NetWriter writes entries, newline delimited, over a pool of tcp or udp connections.
+// failed connections are redialed in the background with backoff, entries written meanwhile are buffered
+// in memory and sent in order once reconnected
+type NetWriter struct {
+	cfg     NetWriterConfiguration
+	network string
+	address string
+	conns   []*netConn
+
+	mu      sync.Mutex
+	next    int
+	pending [][]byte
+
+	// removed entries removed from the front of pending, sent or dropped
+	removed      uint64
+	dropped      int
+	reconnecting bool
+	closed       bool
+
+	drainMu sync.Mutex
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewNetWriter parses cfg.Address and dials the pool
+func NewNetWriter(cfg NetWriterConfiguration) (*NetWriter, error) {
+	u, err := url.Parse(cfg.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("net writer address %q must be tcp://host:port or udp://host:port", cfg.Address)
+	}
+
+	if cfg.PoolSize <= 0 {
+		cfg.PoolSize = 1
+	}
+
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultNetBufferSize
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultNetMaxBackoff
+	}
+
+	w := &NetWriter{cfg: cfg, network: u.Scheme, address: u.Host, stop: make(chan struct{})}
+	for i := 0; i < cfg.PoolSize; i++ {
+		conn, err := net.DialTimeout(w.network, w.address, cfg.Timeout)
+		if err != nil {
+			_ = w.closeConns()
+			return nil, err
+		}
+		w.conns = append(w.conns, &netConn{conn: conn})
+	}
+
+	return w, nil
+}
+
+// Write sends p on the next pooled connection, buffering it while disconnected or while older entries are pending
+func (w *NetWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+
+	if len(w.pending) > 0 {
+		w.buffer(entry)
+		w.mu.Unlock()
+		return len(p), nil
+	}
+
+	c := w.conns[w.next%len(w.conns)]
+	w.next++
+	w.mu.Unlock()
+
+	if err := w.send(c, entry); err != nil {
+		w.mu.Lock()
+		w.buffer(entry)
+		w.mu.Unlock()
+	}
+
+	return len(p), nil
+}
+
+// Flush sends the buffered entries, reconnecting when needed
+func (w *NetWriter) Flush() error {
+	if !w.drain() {
+		return fmt.Errorf("net writer %s://%s disconnected, %d entries buffered", w.network, w.address, w.Pending())
+	}
+	return nil
+}
+
+// Pending returns the count of buffered entries
+func (w *NetWriter) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.pending)
+}
+
+// Close stops reconnecting, sends the buffered entries when possible and closes the pool
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	w.wg.Wait()
+
+	err := w.Flush()
+	if closeErr := w.closeConns(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// buffer queues entry, dropping the oldest beyond BufferSize, and starts reconnecting. w.mu must be held
+func (w *NetWriter) buffer(entry []byte) {
+	w.pending = append(w.pending, entry)
+	if len(w.pending) > w.cfg.BufferSize {
+		w.pending = w.pending[1:]
+		w.removed++
+		w.dropped++
+	}
+
+	if !w.reconnecting && !w.closed {
+		w.reconnecting = true
+		w.wg.Add(1)
+		go w.reconnect()
+	}
+}
+
+// reconnect redials and drains with backoff until every buffered entry is sent
+func (w *NetWriter) reconnect() {
+	defer w.wg.Done()
+
+	backoff := w.cfg.RetryBackoff
+	for {
+		select {
+		case <-w.stop:
+			w.mu.Lock()
+			w.reconnecting = false
+			w.mu.Unlock()
+			return
+		case <-time.After(backoff):
+		}
+
+		if w.drain() {
+			w.mu.Lock()
+			if len(w.pending) == 0 {
+				w.reconnecting = false
+				dropped := w.dropped
+				w.dropped = 0
+				w.mu.Unlock()
+
+				if dropped > 0 {
+					reportDiagnostic(fmt.Errorf("net writer %s://%s dropped %d entries while disconnected", w.network, w.address, dropped))
+				}
+				return
+			}
+			w.mu.Unlock()
+			continue
+		}
+
+		backoff *= 2
+		if backoff > w.cfg.MaxBackoff {
+			backoff = w.cfg.MaxBackoff
+		}
+	}
+}
+
+// drain sends the buffered entries in order, false when a connection is still failing
+func (w *NetWriter) drain() bool {
+	w.drainMu.Lock()
+	defer w.drainMu.Unlock()
+
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.mu.Unlock()
+			return true
+		}
+		entry, removed := w.pending[0], w.removed
+		c := w.conns[w.next%len(w.conns)]
+		w.next++
+		w.mu.Unlock()
+
+		if err := w.send(c, entry); err != nil {
+			return false
+		}
+
+		w.mu.Lock()
+		if w.removed == removed {
+			w.pending = w.pending[1:]
+			w.removed++
+		}
+		w.mu.Unlock()
+	}
+}
+
+// send writes entry on c, dialing it when disconnected and dropping the connection on failure
+func (w *NetWriter) send(c *netConn, entry []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		conn, err := net.DialTimeout(w.network, w.address, w.cfg.Timeout)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+
+	err := c.conn.SetWriteDeadline(time.Now().Add(w.cfg.Timeout))
+	if err == nil {
+		_, err = c.conn.Write(entry)
+	}
+
+	if err != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	return err
+}
+
+func (w *NetWriter) closeConns() error {
+	var err error
+	for _, c := range w.conns {
+		c.mu.Lock()
+		if c.conn != nil {
+			if closeErr := c.conn.Close(); err == nil {
+				err = closeErr
+			}
+			c.conn = nil
+		}
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func createNetLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg NetLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewNetWriter(cfg.NetWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// acceptLines accepts connections on listener, sending every received line
+func acceptLines(listener net.Listener, lines chan<- string, conns chan<- net.Conn) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conns <- conn
+
+		go func() {
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+	}
+}
+
+func TestNetWriterReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	address := listener.Addr().String()
+
+	lines := make(chan string, 100)
+	conns := make(chan net.Conn, 10)
+	go acceptLines(listener, lines, conns)
+
+	writer, err := NewNetWriter(NetWriterConfiguration{Address: "tcp://" + address, RetryBackoff: 10 * time.Millisecond})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	_, err = writer.Write([]byte("first\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, "first", <-lines)
+
+	_ = listener.Close()
+	(<-conns).Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for writer.Pending() == 0 && time.Now().Before(deadline) {
+		_, err = writer.Write([]byte("lost\n"))
+		assert.Nil(t, err)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 1, writer.Pending())
+
+	_, _ = writer.Write([]byte("second\n"))
+	_, _ = writer.Write([]byte("third\n"))
+	assert.Equal(t, 3, writer.Pending())
+
+	listener, err = net.Listen("tcp", address)
+	assert.Nil(t, err)
+	defer listener.Close()
+	go acceptLines(listener, lines, conns)
+
+	var received []string
+	for len(received) < 3 {
+		select {
+		case line := <-lines:
+			received = append(received, line)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("buffered entries not sent, received %v", received)
+		}
+	}
+	assert.Equal(t, []string{"lost", "second", "third"}, received)
+	assert.Equal(t, 0, writer.Pending())
+}
+
+func TestNetDriverUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   NetLoggerDriver,
+		Values:   map[string]any{"address": "udp://" + conn.LocalAddr().String(), "poolSize": 2},
+	})
+	assert.Nil(t, err)
+	defer log.(Syncer).Close()
+
+	log.Log("over udp")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(string(buf[:n]), "}\n"))
+	assert.Contains(t, string(buf[:n]), `"message":"over udp"`)
+
+	_, err = NewNetWriter(NetWriterConfiguration{Address: "http://localhost"})
+	assert.Error(t, err)
+}
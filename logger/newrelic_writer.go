@@ -0,0 +1,221 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/env"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// new relic log api defaults and limits
+const (
+	DefaultNewRelicURL      = "https://log-api.newrelic.com/log/v1"
+	NewRelicEUURL           = "https://log-api.eu.newrelic.com/log/v1"
+	NewRelicMaxPayloadBytes = 1 << 20
+)
+
+// NewRelicWriterConfiguration new relic log api sink configuration, URL defaults to the api of Region
+type NewRelicWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	LicenseKey string `toml:"licenseKey" json:"licenseKey" mapstructure:"licenseKey"`
+
+	// Region us, the default, or eu
+	Region string `toml:"region" json:"region" mapstructure:"region"`
+
+	// Service service.name of the entries, defaults to the app of each entry, then to the APP_NAME env
+	Service string `toml:"service" json:"service" mapstructure:"service"`
+
+	// Attributes common attributes of every entry, added to hostname and the SCOPE env as environment
+	Attributes map[string]any `toml:"attributes" json:"attributes" mapstructure:"attributes"`
+}
+
+// NewRelicLoggerConfiguration json logger posting to the new relic log api
+type NewRelicLoggerConfiguration struct {
+	JSONLoggerConfiguration     `mapstructure:",squash"`
+	NewRelicWriterConfiguration `mapstructure:",squash"`
+}
+
+type newRelicPayload struct {
+	Common struct {
+		Attributes map[string]any `json:"attributes"`
+	} `json:"common"`
+	Logs []json.RawMessage `json:"logs"`
+}
+
+// NewRelicWriter batches entries into new relic detailed log api requests, within the payload limit.
+// entry fields are kept as attributes, with service.name added
+type NewRelicWriter struct {
+	cfg    NewRelicWriterConfiguration
+	sender *httpSender
+	header http.Header
+	common map[string]any
+	batch  *batcher
+}
+
+// NewNewRelicWriter returns a writer posting to the new relic log api
+func NewNewRelicWriter(cfg NewRelicWriterConfiguration) (*NewRelicWriter, error) {
+	if cfg.LicenseKey == "" {
+		return nil, fmt.Errorf("new relic writer requires a LicenseKey")
+	}
+
+	if cfg.URL == "" {
+		switch strings.ToLower(cfg.Region) {
+		case "", "us":
+			cfg.URL = DefaultNewRelicURL
+		case "eu":
+			cfg.URL = NewRelicEUURL
+		default:
+			return nil, fmt.Errorf("unknown new relic region %s", cfg.Region)
+		}
+	}
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	common := map[string]any{"hostname": hostname}
+	if scope := env.EnvScope(); scope != "" {
+		common["environment"] = scope
+	}
+	for key, value := range cfg.Attributes {
+		common[key] = value
+	}
+
+	w := &NewRelicWriter{
+		cfg:    cfg,
+		sender: sender,
+		header: http.Header{"Content-Type": {"application/json"}, "X-License-Key": {cfg.LicenseKey}},
+		common: common,
+	}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.post)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next request
+func (w *NewRelicWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush posts the pending entries
+func (w *NewRelicWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close posts the pending entries
+func (w *NewRelicWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *NewRelicWriter) post(batch [][]byte) error {
+	var (
+		logs []json.RawMessage
+		size int
+	)
+	flush := func() error {
+		if len(logs) == 0 {
+			return nil
+		}
+
+		payload := newRelicPayload{Logs: logs}
+		payload.Common.Attributes = w.common
+		logs, size = nil, 0
+
+		body, err := json.Marshal([]newRelicPayload{payload})
+		if err != nil {
+			return err
+		}
+
+		_, err = w.sender.send("", w.header, body)
+		return err
+	}
+
+	for _, entry := range batch {
+		log, err := w.log(entry)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+
+		if size+len(log)+1 > NewRelicMaxPayloadBytes {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+
+		logs = append(logs, log)
+		size += len(log) + 1
+	}
+
+	return flush()
+}
+
+// log converts an entry to a new relic log, entries that aren't json are sent as the message
+func (w *NewRelicWriter) log(entry []byte) ([]byte, error) {
+	fields, err := decodeEntry(entry)
+	if err != nil {
+		fields = map[string]any{"message": strings.TrimRight(string(entry), "\n")}
+	}
+
+	service := w.cfg.Service
+	if service == "" {
+		service = fieldString(fields["app"])
+	}
+	if service == "" {
+		service = env.EnvAppName()
+	}
+
+	timestamp := entryTime(fields, time.Now())
+	message := fieldString(fields["message"])
+	delete(fields, "timestamp")
+	delete(fields, "message")
+
+	if value, ok := fields[HostField]; ok {
+		fields["hostname"] = fieldString(value)
+	}
+	fields["service.name"] = service
+
+	log, err := json.Marshal(map[string]any{
+		"timestamp":  timestamp.UnixMilli(),
+		"message":    message,
+		"attributes": fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(log) > NewRelicMaxPayloadBytes {
+		return nil, fmt.Errorf("new relic log of %d bytes above the %d bytes limit", len(log), NewRelicMaxPayloadBytes)
+	}
+
+	return log, nil
+}
+
+func createNewRelicLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg NewRelicLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewNewRelicWriter(cfg.NewRelicWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
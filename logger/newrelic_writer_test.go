@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewRelicDriver(t *testing.T) {
+	t.Setenv("SCOPE", "staging")
+
+	var payloads []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "license", r.Header.Get("X-License-Key"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &payloads))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   NewRelicLoggerDriver,
+		Values: map[string]any{
+			"url":        server.URL,
+			"licenseKey": "license",
+			"attributes": map[string]any{"team": "payments"},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.With("order", 42).Warn("payment retried")
+	log.Log("done")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, payloads, 1)
+	common := payloads[0]["common"].(map[string]any)["attributes"].(map[string]any)
+	assert.Equal(t, hostname, common["hostname"])
+	assert.Equal(t, "staging", common["environment"])
+	assert.Equal(t, "payments", common["team"])
+
+	logs := payloads[0]["logs"].([]any)
+	assert.Len(t, logs, 2)
+
+	first := logs[0].(map[string]any)
+	assert.Equal(t, "payment retried", first["message"])
+	assert.NotZero(t, first["timestamp"])
+
+	attributes := first["attributes"].(map[string]any)
+	assert.Equal(t, "App", attributes["service.name"])
+	assert.Equal(t, "WARN", attributes["level"])
+	assert.Equal(t, float64(42), attributes["order"])
+	assert.NotContains(t, attributes, "message")
+}
+
+func TestNewRelicWriterConfiguration(t *testing.T) {
+	_, err := NewNewRelicWriter(NewRelicWriterConfiguration{})
+	assert.NotNil(t, err)
+
+	_, err = NewNewRelicWriter(NewRelicWriterConfiguration{LicenseKey: "license", Region: "mars"})
+	assert.NotNil(t, err)
+
+	w, err := NewNewRelicWriter(NewRelicWriterConfiguration{LicenseKey: "license", Region: "EU"})
+	assert.Nil(t, err)
+	assert.Equal(t, NewRelicEUURL, w.sender.cfg.URL)
+	assert.Nil(t, w.Close())
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"io"
+)
+
+// NopLogger Interface implementation discarding every entry.
+// reference for custom implementations, every method of Interface must be provided
+type NopLogger struct{}
+
+// compile time check NopLogger satisfies Interface
+var _ Interface = NopLogger{}
+
+// NewNopLogger returns a logger discarding every entry
+func NewNopLogger() Interface {
+	return NopLogger{}
+}
+
+// createNullLogger ignores the configuration, a NopLogger needs none
+func createNullLogger(_ context.Context, _ Configuration) (Interface, error) {
+	return NopLogger{}, nil
+}
+
+// Clone returns the logger itself, it holds no state
+func (n NopLogger) Clone() Interface { return n }
+
+// WithCtx returns the logger itself
+func (n NopLogger) WithCtx(_ context.Context) Interface { return n }
+
+// With returns the logger itself
+func (n NopLogger) With(_ string, _ any) Interface { return n }
+
+// WithKV returns the logger itself
+func (n NopLogger) WithKV(_ ...any) Interface { return n }
+
+// WithError returns the logger itself
+func (n NopLogger) WithError(_ error) Interface { return n }
+
+// WithF returns the logger itself
+func (n NopLogger) WithF(_ ...Field) Interface { return n }
+
+// WithGroup returns the logger itself
+func (n NopLogger) WithGroup(_ string) Interface { return n }
+
+// WithScope returns the logger itself
+func (n NopLogger) WithScope(_ string) Interface { return n }
+
+// WithSamplingHint returns the logger itself
+func (n NopLogger) WithSamplingHint(_ bool) Interface { return n }
+
+// Named returns the logger itself
+func (n NopLogger) Named(_ string) Interface { return n }
+
+// WithApp returns the logger itself
+func (n NopLogger) WithApp(_ string) Interface { return n }
+
+// WithUID returns the logger itself
+func (n NopLogger) WithUID(_ string) Interface { return n }
+
+// WithWriter returns the logger itself
+func (n NopLogger) WithWriter(_ io.Writer) Interface { return n }
+
+// WithLevel returns the logger itself
+func (n NopLogger) WithLevel(_ LogLevelEnum) Interface { return n }
+
+// Event returns a nil Event, discarding the entry
+func (NopLogger) Event(_ LogLevelEnum) *Event { return nil }
+
+// Enabled returns false, every entry is discarded
+func (NopLogger) Enabled(_ LogLevelEnum) bool { return false }
+
+// Log discards the entry
+func (NopLogger) Log(_ string, _ ...any) {}
+
+// LogAt discards the entry, FATAL still exits with ExitFunc(1)
+func (NopLogger) LogAt(level LogLevelEnum, _ string, _ ...any) {
+	if level == FATAL {
+		ExitFunc(1)
+	}
+}
+
+// LogWith discards the entry, FATAL still exits with ExitFunc(1)
+func (NopLogger) LogWith(level LogLevelEnum, _ string, _ ...Field) {
+	if level == FATAL {
+		ExitFunc(1)
+	}
+}
+
+// Printf discards the entry
+func (NopLogger) Printf(_ string, _ ...any) {}
+
+// Println discards the entry
+func (NopLogger) Println(_ ...any) {}
+
+// Error discards the entry
+func (NopLogger) Error(_ string, _ ...any) {}
+
+// Warn discards the entry
+func (NopLogger) Warn(_ string, _ ...any) {}
+
+// Debug discards the entry
+func (NopLogger) Debug(_ string, _ ...any) {}
+
+// Err discards the entry
+func (NopLogger) Err(_ error, _ string, _ ...any) {}
+
+// Fatal discards the entry and still exits with ExitFunc(1), as callers rely on it not returning
+func (NopLogger) Fatal(_ string, _ ...any) {
+	ExitFunc(1)
+}
+
+// Fatalf discards the entry and still exits with ExitFunc(1)
+func (NopLogger) Fatalf(_ string, _ ...any) {
+	ExitFunc(1)
+}
+
+// Panic discards the entry and still panics with the message
+func (NopLogger) Panic(format string, args ...any) {
+	panic(panicMessage(format, args...))
+}
+
+// Flush does nothing, there is nothing buffered
+func (NopLogger) Flush() error { return nil }
+
+// Close does nothing
+func (NopLogger) Close() error { return nil }
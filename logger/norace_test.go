@@ -0,0 +1,6 @@
+//go:build !race
+
+package logger
+
+// raceEnabled the race detector randomly drops pooled values, allocation counts aren't stable under it
+const raceEnabled = false
@@ -0,0 +1,325 @@
+package logger
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultOTLPURL otlp/http logs endpoint of a local collector
+const DefaultOTLPURL = "http://localhost:4318/v1/logs"
+
+// entry keys mapped to dedicated otlp record or resource fields, not repeated as attributes
+var otlpRecordKeys = map[string]bool{"timestamp": true, "level": true, "message": true, "app": true, "scope": true, HostField: true}
+
+// OTLPSeverity returns the otel severity number of level
+func OTLPSeverity(level LogLevelEnum) int {
+	switch level {
+	case FATAL:
+		return 21
+	case ERROR:
+		return 17
+	case WARN:
+		return 13
+	case DEBUG:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// OTLPLogsClient uploads an ExportLogsServiceRequest in its otlp json encoding over a transport provided
+// by the caller. the package itself only exports otlp/http json, other transports, eg: otlp/grpc,
+// aren't built in and need a client
+type OTLPLogsClient interface {
+	UploadLogs(ctx context.Context, request []byte) error
+}
+
+// OTLPWriterConfiguration otlp logs sink configuration. entries are sent with Client when set,
+// otherwise as otlp/http json to URL, defaulting to DefaultOTLPURL
+type OTLPWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	Client OTLPLogsClient
+
+	// ResourceAttributes added to service.name (the app) and host.name, eg: deployment.environment
+	ResourceAttributes map[string]string `toml:"resourceAttributes" json:"resourceAttributes" mapstructure:"resourceAttributes"`
+
+	// TraceIDField and SpanIDField dotted paths of the trace context, default to ctx.trace_id and ctx.span_id
+	TraceIDField string `toml:"traceIdField" json:"traceIdField" mapstructure:"traceIdField"`
+	SpanIDField  string `toml:"spanIdField" json:"spanIdField" mapstructure:"spanIdField"`
+}
+
+// OTLPLoggerConfiguration json logger exporting to an otel collector
+type OTLPLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	OTLPWriterConfiguration `mapstructure:",squash"`
+}
+
+// OTLPWriter batches entries into otlp log records: the level as severity, the message as body,
+// the other fields as attributes, the app and scope as resource and instrumentation scope.
+// records are exported as otlp/http json, or through Client
+type OTLPWriter struct {
+	cfg    OTLPWriterConfiguration
+	sender *httpSender
+	batch  *batcher
+}
+
+type otlpKeyValue struct {
+	Key   string         `json:"key"`
+	Value map[string]any `json:"value"`
+}
+
+type otlpRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText,omitempty"`
+	Body                 map[string]any `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpScopeLogs struct {
+	Scope      map[string]string `json:"scope"`
+	LogRecords []otlpRecord      `json:"logRecords"`
+}
+
+type otlpResourceLogs struct {
+	Resource  map[string][]otlpKeyValue `json:"resource"`
+	ScopeLogs []*otlpScopeLogs          `json:"scopeLogs"`
+}
+
+// NewOTLPWriter returns a writer exporting through cfg.Client or to cfg.URL
+func NewOTLPWriter(cfg OTLPWriterConfiguration) (*OTLPWriter, error) {
+	if cfg.URL == "" {
+		cfg.URL = DefaultOTLPURL
+	}
+
+	if cfg.TraceIDField == "" {
+		cfg.TraceIDField = "ctx." + TraceID
+	}
+
+	if cfg.SpanIDField == "" {
+		cfg.SpanIDField = "ctx.span_id"
+	}
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	cfg.HTTPSinkConfiguration = sender.cfg
+
+	w := &OTLPWriter{cfg: cfg, sender: sender}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.export)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next export
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush exports the pending entries
+func (w *OTLPWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close exports the pending entries
+func (w *OTLPWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *OTLPWriter) export(batch [][]byte) error {
+	request, err := json.Marshal(map[string]any{"resourceLogs": w.resourceLogs(batch, time.Now())})
+	if err != nil {
+		return err
+	}
+
+	if w.cfg.Client == nil {
+		_, err = w.sender.send("", http.Header{"Content-Type": {"application/json"}}, request)
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	return w.cfg.Client.UploadLogs(ctx, request)
+}
+
+// resourceLogs groups the records by resource, the app and host, and instrumentation scope
+func (w *OTLPWriter) resourceLogs(batch [][]byte, now time.Time) []*otlpResourceLogs {
+	var resources []*otlpResourceLogs
+	byResource := map[string]*otlpResourceLogs{}
+	byScope := map[string]*otlpScopeLogs{}
+	for _, entry := range batch {
+		fields, err := decodeEntry(entry)
+		if err != nil {
+			fields = map[string]any{"message": strings.TrimRight(string(entry), "\n")}
+		}
+
+		app, scope := fieldString(fields["app"]), fieldString(fields["scope"])
+		host := hostname
+		if value, ok := fields[HostField]; ok {
+			host = fieldString(value)
+		}
+
+		resourceKey := app + "\x00" + host
+		resource, ok := byResource[resourceKey]
+		if !ok {
+			resource = &otlpResourceLogs{Resource: map[string][]otlpKeyValue{"attributes": w.resourceAttributes(app, host)}}
+			byResource[resourceKey] = resource
+			resources = append(resources, resource)
+		}
+
+		scopeKey := resourceKey + "\x00" + scope
+		scopeLogs, ok := byScope[scopeKey]
+		if !ok {
+			scopeLogs = &otlpScopeLogs{Scope: map[string]string{"name": scope}}
+			byScope[scopeKey] = scopeLogs
+			resource.ScopeLogs = append(resource.ScopeLogs, scopeLogs)
+		}
+
+		scopeLogs.LogRecords = append(scopeLogs.LogRecords, w.record(fields, now))
+	}
+
+	return resources
+}
+
+func (w *OTLPWriter) resourceAttributes(app string, host string) []otlpKeyValue {
+	attributes := []otlpKeyValue{
+		{Key: "service.name", Value: otlpValue(app)},
+		{Key: "host.name", Value: otlpValue(host)},
+	}
+
+	keys := make([]string, 0, len(w.cfg.ResourceAttributes))
+	for key := range w.cfg.ResourceAttributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attributes = append(attributes, otlpKeyValue{Key: key, Value: otlpValue(w.cfg.ResourceAttributes[key])})
+	}
+
+	return attributes
+}
+
+// record converts the entry fields to a log record, trace context ids are kept when valid hex
+func (w *OTLPWriter) record(fields map[string]any, now time.Time) otlpRecord {
+	level := fieldString(fields["level"])
+	severity := OTLPSeverity(LOG)
+	if parsed, err := ParseLogLevel(level); err == nil {
+		severity = OTLPSeverity(parsed)
+	}
+
+	record := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(entryTime(fields, now).UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(now.UnixNano(), 10),
+		SeverityNumber:       severity,
+		SeverityText:         level,
+		Body:                 otlpValue(fieldString(fields["message"])),
+	}
+
+	for path, id := range map[string]*string{w.cfg.TraceIDField: &record.TraceID, w.cfg.SpanIDField: &record.SpanID} {
+		value, _ := lookupField(fields, path)
+		if text := strings.ToLower(fieldString(value)); otlpID(text) {
+			*id = text
+			removeField(fields, path)
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if !otlpRecordKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		record.Attributes = append(record.Attributes, otlpKeyValue{Key: key, Value: otlpValue(fields[key])})
+	}
+
+	return record
+}
+
+// otlpID true for the 16 and 32 hex characters span and trace ids
+func otlpID(text string) bool {
+	if len(text) != 16 && len(text) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(text)
+	return err == nil
+}
+
+// otlpValue converts a decoded json value to an otlp AnyValue
+func otlpValue(value any) map[string]any {
+	switch v := value.(type) {
+	case string:
+		return map[string]any{"stringValue": v}
+	case bool:
+		return map[string]any{"boolValue": v}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return map[string]any{"intValue": strconv.FormatInt(i, 10)}
+		}
+		f, _ := v.Float64()
+		return map[string]any{"doubleValue": f}
+	case []any:
+		values := make([]map[string]any, len(v))
+		for i, item := range v {
+			values[i] = otlpValue(item)
+		}
+		return map[string]any{"arrayValue": map[string]any{"values": values}}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		values := make([]otlpKeyValue, len(keys))
+		for i, key := range keys {
+			values[i] = otlpKeyValue{Key: key, Value: otlpValue(v[key])}
+		}
+		return map[string]any{"kvlistValue": map[string]any{"values": values}}
+	case nil:
+		return map[string]any{}
+	default:
+		return map[string]any{"stringValue": fmt.Sprint(v)}
+	}
+}
+
+func createOTLPLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg OTLPLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewOTLPWriter(cfg.OTLPWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type recordingOTLPClient struct {
+	requests []string
+}
+
+func (c *recordingOTLPClient) UploadLogs(_ context.Context, request []byte) error {
+	c.requests = append(c.requests, string(request))
+	return nil
+}
+
+func TestOTLPDriver(t *testing.T) {
+	var request struct {
+		ResourceLogs []struct {
+			Resource struct {
+				Attributes []otlpKeyValue `json:"attributes"`
+			} `json:"resource"`
+			ScopeLogs []struct {
+				Scope      map[string]string `json:"scope"`
+				LogRecords []otlpRecord      `json:"logRecords"`
+			} `json:"scopeLogs"`
+		} `json:"resourceLogs"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/logs", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.Nil(t, json.Unmarshal(body, &request))
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   OTLPLoggerDriver,
+		Values: map[string]any{
+			"url":                server.URL + "/v1/logs",
+			"resourceAttributes": map[string]string{"deployment.environment": "test"},
+		},
+	})
+	assert.Nil(t, err)
+
+	ctx := context.WithValue(context.Background(), TraceID, "4BF92F3577B34DA6A3CE929D0E0E4736")
+	log.WithCtx(ctx).With("rows", 3).With("db", map[string]any{"system": "postgres"}).Warn("slow query")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, request.ResourceLogs, 1)
+	resource := request.ResourceLogs[0]
+	assert.Equal(t, otlpKeyValue{Key: "service.name", Value: map[string]any{"stringValue": "App"}}, resource.Resource.Attributes[0])
+	assert.Equal(t, "deployment.environment", resource.Resource.Attributes[2].Key)
+	assert.Equal(t, "Scope", resource.ScopeLogs[0].Scope["name"])
+
+	record := resource.ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, 13, record.SeverityNumber)
+	assert.Equal(t, "WARN", record.SeverityText)
+	assert.Equal(t, map[string]any{"stringValue": "slow query"}, record.Body)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record.TraceID)
+
+	attributes := map[string]map[string]any{}
+	for _, attribute := range record.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+	assert.Equal(t, map[string]any{"intValue": "3"}, attributes["rows"])
+	assert.Contains(t, attributes["db"], "kvlistValue")
+	assert.NotContains(t, attributes, "ctx")
+	assert.NotContains(t, attributes, "message")
+}
+
+func TestOTLPWriterClient(t *testing.T) {
+	client := &recordingOTLPClient{}
+	writer, err := NewOTLPWriter(OTLPWriterConfiguration{Client: client})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte(`{"level":"ERROR","message":"boom","ctx":{"trace_id":"not-hex"}}` + "\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, writer.Close())
+
+	assert.Len(t, client.requests, 1)
+	assert.Contains(t, client.requests[0], `"severityNumber":17`)
+	assert.NotContains(t, client.requests[0], `"traceId"`)
+	assert.Contains(t, client.requests[0], `"key":"ctx"`)
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/env"
+	"io"
+)
+
+// withConfig returns a copy of the logger, with fields, using a copy of its configuration changed by fn
+func (i *innerJsonLog) withConfig(fn func(*JsonLogger)) Interface {
+	segment := i.clone()
+	cfg := *segment.JsonLogger
+	fn(&cfg)
+	segment.JsonLogger = &cfg
+	return segment
+}
+
+// withConfig returns a copy of the logger changed by fn
+func (i *JsonLogger) withConfig(fn func(*JsonLogger)) Interface {
+	cfg := *i
+	fn(&cfg)
+	return &cfg
+}
+
+// WithScope returns a copy of the logger logging under scope
+func (i *innerJsonLog) WithScope(scope string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.setScope(scope) })
+}
+
+// Named returns a copy of the logger with name appended to its Name and Scope, dot separated
+func (i *innerJsonLog) Named(name string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.named(name) })
+}
+
+// WithApp returns a copy of the logger logging under app
+func (i *innerJsonLog) WithApp(app string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.App = app })
+}
+
+// WithUID returns a copy of the logger logging with uid
+func (i *innerJsonLog) WithUID(uid string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.UID = uid })
+}
+
+// WithWriter returns a copy of the logger writing to writer
+func (i *innerJsonLog) WithWriter(writer io.Writer) Interface {
+	return i.withConfig(func(cfg *JsonLogger) {
+		cfg.writer = writer
+		cfg.writeMu = cfg.locks.lock(writer)
+	})
+}
+
+// WithLevel returns a copy of the logger using level as threshold
+func (i *innerJsonLog) WithLevel(level LogLevelEnum) Interface {
+	return i.withConfig(func(cfg *JsonLogger) {
+		cfg.LogLevel = level
+		cfg.Levels = nil
+	})
+}
+
+// WithScope returns a copy of the logger logging under scope
+func (i *JsonLogger) WithScope(scope string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.setScope(scope) })
+}
+
+// Named returns a copy of the logger with name appended to its Name and Scope, dot separated
+func (i *JsonLogger) Named(name string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.named(name) })
+}
+
+// WithApp returns a copy of the logger logging under app
+func (i *JsonLogger) WithApp(app string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.App = app })
+}
+
+// WithUID returns a copy of the logger logging with uid
+func (i *JsonLogger) WithUID(uid string) Interface {
+	return i.withConfig(func(cfg *JsonLogger) { cfg.UID = uid })
+}
+
+// WithWriter returns a copy of the logger writing to writer
+func (i *JsonLogger) WithWriter(writer io.Writer) Interface {
+	return i.withConfig(func(cfg *JsonLogger) {
+		cfg.writer = writer
+		cfg.writeMu = cfg.locks.lock(writer)
+	})
+}
+
+// WithLevel returns a copy of the logger using level as threshold
+func (i *JsonLogger) WithLevel(level LogLevelEnum) Interface {
+	return i.withConfig(func(cfg *JsonLogger) {
+		cfg.LogLevel = level
+		cfg.Levels = nil
+	})
+}
+
+// named appends name to Name and Scope
+func (i *JsonLogger) named(name string) {
+	if name == "" {
+		return
+	}
+
+	i.Name = joinName(i.Name, name)
+	i.setScope(joinName(i.Scope, name))
+}
+
+// setScope changes Scope, keeping the scope it derives from for isProdScope
+func (i *JsonLogger) setScope(scope string) {
+	if i.envScope == "" {
+		i.envScope = i.Scope
+	}
+	i.Scope = scope
+}
+
+// isProdScope reports whether the logger, or the one it derives from with Named or WithScope, has a prod scope
+func (i *JsonLogger) isProdScope() bool {
+	if i.envScope != "" {
+		return env.IsProdScope(i.envScope)
+	}
+	return env.IsProdScope(i.Scope)
+}
+
+func joinName(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
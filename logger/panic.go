@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/pixie-sh/logger-go/caller"
+)
+
+// panicMessage expands the message like the entry message
+func panicMessage(format string, args ...any) string {
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
+
+// Panic logs a message at ERROR level, flushes the writer and panics with the message.
+func (i *JsonLogger) Panic(format string, args ...any) {
+	segment := i.entrySegment(caller.Upper(), map[string]any{PanicField: true})
+	segment.log(ERROR, format, args...)
+	_ = i.Flush()
+	panic(panicMessage(format, args...))
+}
+
+// Panic logs a message at ERROR level, flushes the writer and panics with the message.
+func (i *innerJsonLog) Panic(format string, args ...any) {
+	segment := i.clone()
+	segment.setCaller(caller.Upper())
+	segment.set(PanicField, true)
+	segment.log(ERROR, format, args...)
+	_ = i.Flush()
+	panic(panicMessage(format, args...))
+}
+
+// Panic logs a message at ERROR level with the global Logger, flushes it and panics with the message
+func Panic(format string, args ...any) {
+	logWithCaller(Logger.Clone().With(PanicField, true), ERROR, caller.Upper(), format, args...)
+	if s, ok := Logger.(Syncer); ok {
+		_ = s.Flush()
+	}
+	panic(panicMessage(format, args...))
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"fmt"
+	"github.com/pixie-sh/logger-go/caller"
+	"strings"
+)
+
+// sprintln formats v like fmt.Println, without the trailing newline
+func sprintln(v ...any) string {
+	return strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+}
+
+// Printf logs a message at LOG level, like log.Logger Printf.
+func (i *JsonLogger) Printf(format string, args ...any) {
+	i.log(LOG, caller.Upper(), format, args...)
+}
+
+// Println logs v at LOG level, formatted like fmt.Println.
+func (i *JsonLogger) Println(v ...any) {
+	i.log(LOG, caller.Upper(), "%s", preformatted(sprintln(v...)))
+}
+
+// Fatalf logs a message at FATAL level and exits, like log.Logger Fatalf.
+func (i *JsonLogger) Fatalf(format string, args ...any) {
+	i.log(FATAL, caller.Upper(), format, args...)
+	i.exit()
+}
+
+// Printf logs a message at LOG level, like log.Logger Printf.
+func (i *innerJsonLog) Printf(format string, args ...any) {
+	i.setCaller(caller.Upper())
+	i.log(LOG, format, args...)
+}
+
+// Println logs v at LOG level, formatted like fmt.Println.
+func (i *innerJsonLog) Println(v ...any) {
+	i.setCaller(caller.Upper())
+	i.log(LOG, "%s", preformatted(sprintln(v...)))
+}
+
+// Fatalf logs a message at FATAL level and exits, like log.Logger Fatalf.
+func (i *innerJsonLog) Fatalf(format string, args ...any) {
+	segment := i.clone()
+	segment.setCaller(caller.Upper())
+	segment.log(FATAL, format, args...)
+	i.exit()
+}
+
+// Printf logs a message at LOG level with the global Logger, like log.Printf
+func Printf(format string, args ...any) {
+	logWithCaller(Logger, LOG, caller.Upper(), format, args...)
+}
+
+// Println logs v at LOG level with the global Logger, like log.Println
+func Println(v ...any) {
+	logPreformatted(Logger, LOG, caller.Upper(), sprintln(v...))
+}
+
+// Fatalf logs a message at FATAL level with the global Logger and exits, like log.Fatalf
+func Fatalf(format string, args ...any) {
+	fatal(caller.Upper(), format, args...)
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"sync"
+	"time"
+)
+
+// DefaultProgressInterval default interval between progress entries
+const DefaultProgressInterval = 10 * time.Second
+
+// Progress logs the progress of long-running jobs, at most once per interval
+type Progress struct {
+	log      Interface
+	name     string
+	total    int64
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	start     time.Time
+	lastLog   time.Time
+	processed int64
+}
+
+// NewProgress returns a progress logger for the job name with total items, 0 if unknown.
+// interval defaults to DefaultProgressInterval
+func NewProgress(log Interface, name string, total int64, interval time.Duration) *Progress {
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+
+	now := time.Now()
+	return &Progress{
+		log:      log,
+		name:     name,
+		total:    total,
+		interval: interval,
+		now:      time.Now,
+		start:    now,
+		lastLog:  now,
+	}
+}
+
+// Add adds n processed items, logging the progress if the interval elapsed
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.processed += n
+	now := p.now()
+	if now.Sub(p.lastLog) < p.interval {
+		return
+	}
+
+	p.lastLog = now
+	p.logProgress(caller.Upper(), now, "in progress")
+}
+
+// Done logs the final progress entry
+func (p *Progress) Done() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.logProgress(caller.Upper(), p.now(), "done")
+}
+
+func (p *Progress) logProgress(call caller.Ptr, now time.Time, state string) {
+	elapsed := now.Sub(p.start)
+
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(p.processed) / elapsed.Seconds()
+	}
+
+	log := p.log.Clone().
+		With(ProcessedField, p.processed).
+		With(RateField, rate)
+
+	if p.total > 0 {
+		log = log.With(TotalField, p.total)
+		if rate > 0 && p.processed < p.total {
+			eta := time.Duration(float64(p.total-p.processed) / rate * float64(time.Second))
+			log = log.With(ETAField, float64(eta)/float64(time.Millisecond))
+		}
+	}
+
+	logPreformatted(log, LOG, call, p.name+" "+state)
+}
@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitOverflow behavior of the rate limited writer for the entries over the limit
+type RateLimitOverflow string
+
+// supported overflow behaviors
+const (
+	// DropOverflow drops the entries, counting them in Dropped
+	DropOverflow RateLimitOverflow = "drop"
+	// SummarizeOverflow drops the entries and writes a WARN record "N entries dropped"
+	// ahead of the next admitted entry, or on Flush and Close
+	SummarizeOverflow RateLimitOverflow = "summarize"
+)
+
+// RateLimitWriterConfiguration token bucket configuration
+type RateLimitWriterConfiguration struct {
+	// Rate entries per second
+	Rate float64 `toml:"rate" json:"rate" mapstructure:"rate"`
+
+	// Burst entries admitted at once, defaults to Rate rounded up
+	Burst int `toml:"burst" json:"burst" mapstructure:"burst"`
+
+	// Overflow defaults to DropOverflow
+	Overflow RateLimitOverflow `toml:"overflow" json:"overflow" mapstructure:"overflow"`
+
+	// Encoder of the summary records, defaults to JSONEncoder. json loggers set their own
+	Encoder Encoder
+}
+
+// RateLimitWriter admits entries through a token bucket refilled at Rate, protecting the destination from log storms.
+// ERROR and FATAL entries written through WriteLevel are always admitted, without taking tokens
+type RateLimitWriter struct {
+	dst      io.Writer
+	rate     float64
+	burst    float64
+	overflow RateLimitOverflow
+	encoder  Encoder
+	now      func() time.Time
+
+	mu         sync.Mutex
+	tokens     float64
+	last       time.Time
+	dropped    uint64
+	summarized uint64
+}
+
+// NewRateLimitWriter returns a rate limited writer on top of dst
+func NewRateLimitWriter(dst io.Writer, cfg RateLimitWriterConfiguration) (*RateLimitWriter, error) {
+	if cfg.Rate <= 0 {
+		return nil, fmt.Errorf("rate limited writer requires a positive rate")
+	}
+
+	if cfg.Burst <= 0 {
+		cfg.Burst = int(math.Ceil(cfg.Rate))
+	}
+
+	if cfg.Overflow == "" {
+		cfg.Overflow = DropOverflow
+	}
+
+	switch cfg.Overflow {
+	case DropOverflow, SummarizeOverflow:
+	default:
+		return nil, fmt.Errorf("unknown rate limit overflow %s", cfg.Overflow)
+	}
+
+	return &RateLimitWriter{
+		dst:      dst,
+		rate:     cfg.Rate,
+		burst:    float64(cfg.Burst),
+		overflow: cfg.Overflow,
+		encoder:  cfg.Encoder,
+		now:      time.Now,
+		tokens:   float64(cfg.Burst),
+	}, nil
+}
+
+// Write writes p when a token is available, otherwise p is dropped
+func (w *RateLimitWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	admitted, err := w.admitLocked()
+	if err != nil {
+		return 0, err
+	}
+	if !admitted {
+		return len(p), nil
+	}
+
+	return w.dst.Write(p)
+}
+
+// WriteLevel writes p along with its level when a token is available or p is an ERROR or FATAL entry,
+// otherwise p is dropped
+func (w *RateLimitWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if level <= ERROR {
+		if err := w.summarizeLocked(); err != nil {
+			return 0, err
+		}
+
+		return writeLevel(w.dst, level, p)
+	}
+
+	admitted, err := w.admitLocked()
+	if err != nil {
+		return 0, err
+	}
+	if !admitted {
+		return len(p), nil
+	}
+
+	return writeLevel(w.dst, level, p)
+}
+
+// admitLocked takes a token for the next entry, writing the pending summary ahead of it
+func (w *RateLimitWriter) admitLocked() (bool, error) {
+	now := w.now()
+	if !w.last.IsZero() {
+		w.tokens = math.Min(w.burst, w.tokens+now.Sub(w.last).Seconds()*w.rate)
+	}
+	w.last = now
+
+	if w.tokens < 1 {
+		w.dropped++
+		return false, nil
+	}
+	w.tokens--
+
+	return true, w.summarizeLocked()
+}
+
+// Dropped returns the count of entries dropped since the writer creation
+func (w *RateLimitWriter) Dropped() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dropped
+}
+
+// Flush writes the pending summary and flushes the underlying writer
+func (w *RateLimitWriter) Flush() error {
+	w.mu.Lock()
+	err := w.summarizeLocked()
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return flushWriter(w.dst)
+}
+
+// flushQueued drains the buffers of the underlying writer, without syncing files. the pending summary
+// waits for its interval
+func (w *RateLimitWriter) flushQueued() {
+	flushBuffered(w.dst)
+}
+
+// Close writes the pending summary, flushes and closes the underlying writer
+func (w *RateLimitWriter) Close() error {
+	w.mu.Lock()
+	err := w.summarizeLocked()
+	w.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return closeWriter(w.dst)
+}
+
+// Unwrap returns the underlying writer
+func (w *RateLimitWriter) Unwrap() io.Writer {
+	return w.dst
+}
+
+// summarizeLocked writes the entries dropped since the last summary, when summarizing
+func (w *RateLimitWriter) summarizeLocked() error {
+	if w.overflow != SummarizeOverflow || w.dropped == w.summarized {
+		return nil
+	}
+
+	count := w.dropped - w.summarized
+	w.summarized = w.dropped
+
+	record, err := droppedRecord(w.encoder, "rate_limit_writer", w.now(), count)
+	if err != nil {
+		return err
+	}
+
+	_, err = writeLevel(w.dst, WARN, record)
+	return err
+}
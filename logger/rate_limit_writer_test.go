@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer, err := NewRateLimitWriter(buf, RateLimitWriterConfiguration{Rate: 2, Burst: 3})
+	assert.Nil(t, err)
+
+	now := time.Now()
+	writer.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		n, err := writer.Write([]byte("entry\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, 6, n)
+	}
+	assert.Equal(t, 3, strings.Count(buf.String(), "\n"), "burst admitted at once")
+	assert.Equal(t, uint64(2), writer.Dropped())
+
+	now = now.Add(time.Second)
+	for i := 0; i < 3; i++ {
+		_, _ = writer.Write([]byte("entry\n"))
+	}
+	assert.Equal(t, 5, strings.Count(buf.String(), "\n"), "refilled at rate")
+	assert.Equal(t, uint64(3), writer.Dropped())
+
+	assert.Nil(t, writer.Close())
+	assert.Equal(t, 5, strings.Count(buf.String(), "\n"), "dropped entries are not summarized")
+
+	_, err = NewRateLimitWriter(buf, RateLimitWriterConfiguration{})
+	assert.NotNil(t, err)
+
+	_, err = NewRateLimitWriter(buf, RateLimitWriterConfiguration{Rate: 1, Overflow: "yolo"})
+	assert.NotNil(t, err)
+}
+
+func TestRateLimitWriterSummarize(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer, err := NewRateLimitWriter(buf, RateLimitWriterConfiguration{Rate: 1, Overflow: SummarizeOverflow})
+	assert.Nil(t, err)
+
+	now := time.Now()
+	writer.now = func() time.Time { return now }
+
+	_, _ = writer.Write([]byte("first\n"))
+	_, _ = writer.Write([]byte("dropped\n"))
+	_, _ = writer.Write([]byte("dropped\n"))
+
+	now = now.Add(time.Second)
+	_, _ = writer.Write([]byte("second\n"))
+	_, _ = writer.Write([]byte("dropped\n"))
+	assert.Nil(t, writer.Flush())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "first", lines[0])
+	assert.Equal(t, "second", lines[2])
+
+	entry, err := ParseEntry([]byte(lines[1]), LenientCompatibility)
+	assert.Nil(t, err)
+	assert.Equal(t, "WARN", entry.Level)
+	assert.Equal(t, "2 entries dropped", entry.Message)
+	assert.Equal(t, float64(2), entry.Fields[DroppedField])
+
+	entry, err = ParseEntry([]byte(lines[3]), LenientCompatibility)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(1), entry.Fields[DroppedField])
+}
+
+func TestRateLimitWriterLevels(t *testing.T) {
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	buf := new(bytes.Buffer)
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   LogfmtLoggerDriver,
+		Values: JSONLoggerConfiguration{
+			Writer:    buf,
+			RateLimit: &RateLimitWriterConfiguration{Rate: 0.001, Burst: 1, Overflow: SummarizeOverflow},
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("admitted")
+	log.Log("dropped")
+	log.Error("failure")
+	log.Error("failure")
+	log.Warn("dropped")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 4, "ERROR entries are exempt")
+	assert.Contains(t, lines[0], "admitted")
+	assert.Contains(t, lines[1], "dropped=1", "summaries use the logger encoder")
+	assert.False(t, strings.HasPrefix(lines[1], "{"), lines[1])
+	assert.Contains(t, lines[2], "failure")
+	assert.Contains(t, lines[3], "failure")
+	assert.Equal(t, uint64(2), log.(*JsonLogger).Writer().(*RateLimitWriter).Dropped())
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"time"
+)
+
+// DefaultRedisEntryField stream entry field holding the encoded log entry
+const DefaultRedisEntryField = "entry"
+
+// RedisXAddArgs arguments of an XADD call: XADD Stream MAXLEN [~] MaxLen * Values...
+type RedisXAddArgs struct {
+	Stream string
+
+	// MaxLen stream length trimmed to, no trimming when 0
+	MaxLen int64
+
+	// Approx trims with ~, letting redis trim whole macro nodes
+	Approx bool
+	Values map[string]any
+}
+
+// RedisStreamClient appends to a redis stream, implemented by adapters over a redis client, eg: go-redis XAdd
+type RedisStreamClient interface {
+	XAdd(ctx context.Context, args RedisXAddArgs) error
+}
+
+// RedisWriterConfiguration redis streams sink configuration
+type RedisWriterConfiguration struct {
+	Client RedisStreamClient
+	Stream string `toml:"stream" json:"stream" mapstructure:"stream"`
+
+	// MaxLen stream length kept, approximately unless ExactTrim. unbounded when 0
+	MaxLen    int64 `toml:"maxLen" json:"maxLen" mapstructure:"maxLen"`
+	ExactTrim bool  `toml:"exactTrim" json:"exactTrim" mapstructure:"exactTrim"`
+
+	// Field stream entry field of the encoded entry, defaults to DefaultRedisEntryField
+	Field string `toml:"field" json:"field" mapstructure:"field"`
+
+	// Fields dotted paths of entry fields also added as stream entry fields, eg: level, so consumers can filter
+	Fields []string `toml:"fields" json:"fields" mapstructure:"fields"`
+
+	Timeout time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+}
+
+// RedisLoggerConfiguration json logger appending to a redis stream
+type RedisLoggerConfiguration struct {
+	JSONLoggerConfiguration  `mapstructure:",squash"`
+	RedisWriterConfiguration `mapstructure:",squash"`
+}
+
+// RedisWriter XADDs every entry to a redis stream, trimmed to MaxLen
+type RedisWriter struct {
+	cfg RedisWriterConfiguration
+}
+
+// NewRedisWriter returns a writer appending to cfg.Stream through cfg.Client
+func NewRedisWriter(cfg RedisWriterConfiguration) (*RedisWriter, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("redis writer requires a Client")
+	}
+
+	if cfg.Stream == "" {
+		return nil, fmt.Errorf("redis writer requires a Stream")
+	}
+
+	if cfg.Field == "" {
+		cfg.Field = DefaultRedisEntryField
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	return &RedisWriter{cfg: cfg}, nil
+}
+
+// Write appends p to the stream
+func (w *RedisWriter) Write(p []byte) (int, error) {
+	values := map[string]any{w.cfg.Field: string(bytes.TrimRight(p, "\n"))}
+	if len(w.cfg.Fields) > 0 {
+		if fields, err := decodeEntry(p); err == nil {
+			for _, path := range w.cfg.Fields {
+				if value, ok := lookupField(fields, path); ok {
+					values[path] = fieldString(value)
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	err := w.cfg.Client.XAdd(ctx, RedisXAddArgs{
+		Stream: w.cfg.Stream,
+		MaxLen: w.cfg.MaxLen,
+		Approx: !w.cfg.ExactTrim,
+		Values: values,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func createRedisLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg RedisLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewRedisWriter(cfg.RedisWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
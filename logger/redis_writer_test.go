@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type recordingRedis struct {
+	adds []RedisXAddArgs
+}
+
+func (c *recordingRedis) XAdd(_ context.Context, args RedisXAddArgs) error {
+	c.adds = append(c.adds, args)
+	return nil
+}
+
+func TestRedisDriver(t *testing.T) {
+	client := &recordingRedis{}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   RedisLoggerDriver,
+		Values:   map[string]any{"Client": client, "stream": "logs", "maxLen": 1000, "fields": []string{"level", "missing"}},
+	})
+	assert.Nil(t, err)
+
+	log.Warn("queued")
+
+	assert.Len(t, client.adds, 1)
+	add := client.adds[0]
+	assert.Equal(t, "logs", add.Stream)
+	assert.Equal(t, int64(1000), add.MaxLen)
+	assert.True(t, add.Approx)
+	assert.Equal(t, "WARN", add.Values["level"])
+	assert.NotContains(t, add.Values, "missing")
+	assert.Contains(t, add.Values[DefaultRedisEntryField], `"message":"queued"`)
+	assert.NotContains(t, add.Values[DefaultRedisEntryField], "\n")
+
+	_, err = NewRedisWriter(RedisWriterConfiguration{Client: client})
+	assert.Error(t, err)
+}
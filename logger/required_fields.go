@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequiredFieldsError entry of a scope missing some of its required fields
+type RequiredFieldsError struct {
+	Scope   string
+	Message string
+	Missing []string
+}
+
+// Error returns the violation description
+func (e RequiredFieldsError) Error() string {
+	return fmt.Sprintf("entry %q of scope %s missing required fields: %s", e.Message, e.Scope, strings.Join(e.Missing, ", "))
+}
+
+// validateRequired checks the entry carries the fields required for the logger scope.
+// violations go to the DiagnosticsHandler, validation is skipped in prod scopes
+func (i *JsonLogger) validateRequired(logEntry map[string]any, inlined []Field, msg string) {
+	required := i.RequiredFields[i.Scope]
+	if len(required) == 0 || i.isProdScope() {
+		return
+	}
+
+	var missing []string
+	for _, field := range required {
+		if _, ok := logEntry[field]; !ok && !hasInlined(inlined, field) {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) > 0 {
+		reportDiagnostic(RequiredFieldsError{Scope: i.Scope, Message: msg, Missing: missing})
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// sink retry defaults
+const (
+	DefaultMaxRetries   = 3
+	DefaultRetryBackoff = 200 * time.Millisecond
+)
+
+// retry calls fn until it succeeds, returns an error retryable rejects, or retries are exhausted,
+// doubling the backoff between attempts
+func retry(ctx context.Context, retries int, backoff time.Duration, retryable func(error) bool, fn func() error) error {
+	err := fn()
+	for attempt := 0; err != nil && attempt < retries && retryable(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff << attempt):
+		}
+
+		err = fn()
+	}
+
+	return err
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// DefaultRingSize entries kept by a ring writer when no size is given
+const DefaultRingSize = 1000
+
+// RingWriter keeps the last entries in memory, eg: to retrieve recent logs of a live instance
+// when remote shipping is down. it is also an http.Handler dumping them as ndjson,
+// limited to the last n entries with the n query parameter
+type RingWriter struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+// NewRingWriter returns a ring writer keeping the last size entries
+func NewRingWriter(size int) *RingWriter {
+	if size <= 0 {
+		size = DefaultRingSize
+	}
+
+	return &RingWriter{entries: make([][]byte, size)}
+}
+
+// Write keeps a copy of p, evicting the oldest entry when full
+func (w *RingWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries[w.next] = entry
+	w.next = (w.next + 1) % len(w.entries)
+	if w.next == 0 {
+		w.full = true
+	}
+
+	return len(p), nil
+}
+
+// Entries returns the kept entries, oldest first
+func (w *RingWriter) Entries() [][]byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.full {
+		return append([][]byte(nil), w.entries[:w.next]...)
+	}
+
+	entries := make([][]byte, 0, len(w.entries))
+	entries = append(entries, w.entries[w.next:]...)
+	return append(entries, w.entries[:w.next]...)
+}
+
+// Dump writes the kept entries to dst, oldest first
+func (w *RingWriter) Dump(dst io.Writer) error {
+	return dumpEntries(dst, w.Entries())
+}
+
+// ServeHTTP dumps the kept entries as ndjson
+func (w *RingWriter) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	entries := w.Entries()
+	if n, err := strconv.Atoi(r.URL.Query().Get("n")); err == nil && n >= 0 && n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+
+	rw.Header().Set("Content-Type", "application/x-ndjson")
+	_ = dumpEntries(rw, entries)
+}
+
+func dumpEntries(dst io.Writer, entries [][]byte) error {
+	for _, entry := range entries {
+		if _, err := dst.Write(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRingWriter(t *testing.T) {
+	ring := NewRingWriter(3)
+	for _, entry := range []string{"1\n", "2\n", "3\n", "4\n", "5\n"} {
+		_, _ = ring.Write([]byte(entry))
+	}
+
+	dump := new(bytes.Buffer)
+	assert.NoError(t, ring.Dump(dump))
+	assert.Equal(t, "3\n4\n5\n", dump.String())
+
+	rec := httptest.NewRecorder()
+	ring.ServeHTTP(rec, httptest.NewRequest("GET", "/?n=2", nil))
+	assert.Equal(t, "4\n5\n", rec.Body.String())
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+}
+
+func TestRingWriterSink(t *testing.T) {
+	ring := NewRingWriter(10)
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "app", LogLevel: DEBUG}, JSONLoggerConfiguration{
+		Writer: io.Discard,
+		Sinks:  []io.Writer{ring},
+	})
+	assert.NoError(t, err)
+
+	log.Log("kept in memory")
+	assert.NoError(t, log.Flush())
+	assert.Len(t, ring.Entries(), 1)
+	assert.True(t, strings.Contains(string(ring.Entries()[0]), "kept in memory"))
+}
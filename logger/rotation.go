@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Rotator implemented by outputs, and loggers writing to them, that can be rotated or reopened
+type Rotator interface {
+	Rotate() error
+	Reopen() error
+}
+
+// RotateAll rotates every registered output, and logger writing to one, supporting rotation
+func RotateAll() error {
+	syncersMu.Lock()
+	defer syncersMu.Unlock()
+
+	var errs []error
+	for _, s := range registeredSyncers() {
+		if r, ok := s.(Rotator); ok {
+			if err := r.Rotate(); err != nil && !errors.Is(err, errNotRotatable) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+var errNotRotatable = fmt.Errorf("logger output does not support rotation")
+
+// Rotate flushes pending entries and rotates the underlying output
+func (i *JsonLogger) Rotate() error {
+	return rotateWriter(i.writer, Rotator.Rotate)
+}
+
+// Reopen flushes pending entries and reopens the underlying output
+func (i *JsonLogger) Reopen() error {
+	return rotateWriter(i.writer, Rotator.Reopen)
+}
+
+// rotateWriter walks down the writer wrappers, flushing each, until a Rotator is found
+func rotateWriter(w io.Writer, fn func(Rotator) error) error {
+	for w != nil {
+		if r, ok := w.(Rotator); ok {
+			return fn(r)
+		}
+
+		if err := flushWriter(w); err != nil {
+			return err
+		}
+
+		u, ok := w.(interface{ Unwrap() io.Writer })
+		if !ok {
+			break
+		}
+		w = u.Unwrap()
+	}
+
+	return errNotRotatable
+}
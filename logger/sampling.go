@@ -0,0 +1,240 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"sync"
+	"time"
+)
+
+// maxSampledFingerprints bounds the sampler memory, the stalest fingerprints are evicted once reached
+const maxSampledFingerprints = 10000
+
+// evictionCandidates fingerprints looked at to pick the one evicted, the stalest of them
+const evictionCandidates = 16
+
+// ErrorSamplerConfiguration error sampling configuration
+type ErrorSamplerConfiguration struct {
+	First    int           `toml:"first" json:"first" mapstructure:"first"`
+	Interval time.Duration `toml:"interval" json:"interval" mapstructure:"interval"`
+}
+
+// ErrorSampler logs the first N occurrences of an error fingerprint and then
+// a single summarized entry per interval, carrying the suppressed count
+type ErrorSampler struct {
+	first    int
+	interval time.Duration
+	now      func() time.Time
+
+	mu           sync.Mutex
+	fingerprints map[string]*sampleState
+}
+
+type sampleState struct {
+	count       int
+	suppressed  int
+	windowStart time.Time
+
+	// summarize writes the summary of the suppressed entries when no occurrence carries it,
+	// once the interval elapses, on Flush or when the fingerprint is evicted
+	summarize func(suppressed int)
+	timer     *time.Timer
+}
+
+// NewErrorSampler returns a new error sampler
+func NewErrorSampler(cfg ErrorSamplerConfiguration) *ErrorSampler {
+	return &ErrorSampler{
+		first:        cfg.First,
+		interval:     cfg.Interval,
+		now:          time.Now,
+		fingerprints: make(map[string]*sampleState),
+	}
+}
+
+// Sample returns whether the entry with the given fingerprint must be written
+// and how many entries were suppressed since the previous written one
+func (s *ErrorSampler) Sample(fingerprint string) (bool, int) {
+	return s.sample(fingerprint, nil)
+}
+
+// sample samples like Sample, summarize, when set, writes the suppressed count once the interval
+// elapses if no later occurrence carries it
+func (s *ErrorSampler) sample(fingerprint string, summarize func(suppressed int)) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	state, ok := s.fingerprints[fingerprint]
+	if !ok {
+		if len(s.fingerprints) >= maxSampledFingerprints {
+			s.evictLocked()
+		}
+
+		state = &sampleState{windowStart: now}
+		s.fingerprints[fingerprint] = state
+	}
+
+	state.count++
+	if state.count <= s.first {
+		return true, 0
+	}
+
+	if now.Sub(state.windowStart) < s.interval {
+		state.suppressed++
+		if summarize != nil {
+			state.summarize = summarize
+			if state.timer == nil {
+				var timer *time.Timer
+				timer = time.AfterFunc(state.windowStart.Add(s.interval).Sub(now), func() {
+					s.summarizeElapsed(fingerprint, state, timer)
+				})
+				state.timer = timer
+			}
+		}
+		return false, 0
+	}
+
+	suppressed := state.suppressed
+	state.suppressed = 0
+	state.windowStart = now
+	state.stopTimer()
+	return true, suppressed
+}
+
+// summarizeElapsed writes the summary of fingerprint once its interval elapsed without occurrences
+func (s *ErrorSampler) summarizeElapsed(fingerprint string, state *sampleState, timer *time.Timer) {
+	s.mu.Lock()
+	if s.fingerprints[fingerprint] != state || state.timer != timer {
+		s.mu.Unlock()
+		return
+	}
+
+	suppressed, summarize := state.pending()
+	state.timer = nil
+	state.windowStart = s.now()
+	s.mu.Unlock()
+
+	if suppressed > 0 {
+		summarize(suppressed)
+	}
+}
+
+// Flush writes the summaries of the suppressed entries not carried by a later occurrence yet
+func (s *ErrorSampler) Flush() {
+	type summary struct {
+		suppressed int
+		summarize  func(int)
+	}
+
+	var summaries []summary
+	s.mu.Lock()
+	for _, state := range s.fingerprints {
+		if suppressed, summarize := state.pending(); suppressed > 0 {
+			summaries = append(summaries, summary{suppressed, summarize})
+		}
+		state.stopTimer()
+	}
+	s.mu.Unlock()
+
+	for _, sum := range summaries {
+		sum.summarize(sum.suppressed)
+	}
+}
+
+// evictLocked evicts the stalest of a few fingerprints, its pending summary is written in the background
+// as the caller may be logging. callers must hold mu
+func (s *ErrorSampler) evictLocked() {
+	var evicted string
+	var stalest *sampleState
+	candidates := 0
+	for fingerprint, state := range s.fingerprints {
+		if stalest == nil || state.windowStart.Before(stalest.windowStart) {
+			evicted, stalest = fingerprint, state
+		}
+
+		if candidates++; candidates == evictionCandidates {
+			break
+		}
+	}
+
+	if stalest == nil {
+		return
+	}
+
+	delete(s.fingerprints, evicted)
+	stalest.stopTimer()
+	if suppressed, summarize := stalest.pending(); suppressed > 0 {
+		go summarize(suppressed)
+	}
+}
+
+// pending takes the suppressed count to summarize, 0 when there's none or no way to summarize it
+func (state *sampleState) pending() (int, func(int)) {
+	if state.suppressed == 0 || state.summarize == nil {
+		return 0, nil
+	}
+
+	suppressed := state.suppressed
+	state.suppressed = 0
+	return suppressed, state.summarize
+}
+
+func (state *sampleState) stopTimer() {
+	if state.timer != nil {
+		state.timer.Stop()
+		state.timer = nil
+	}
+}
+
+// samplingHint per logger override of the ErrorSampler, see WithSamplingHint
+type samplingHint uint8
+
+const (
+	defaultSampling samplingHint = iota
+	neverSampled
+	allLevelsSampled
+)
+
+// WithSamplingHint returns a copy of the logger overriding the ErrorSampler: always true never samples
+// its entries, eg: billing events, always false samples its entries at every level, eg: chatty sites
+func (i *innerJsonLog) WithSamplingHint(always bool) Interface {
+	segment := i.clone()
+	segment.sampling = allLevelsSampled
+	if always {
+		segment.sampling = neverSampled
+	}
+
+	return segment
+}
+
+// WithSamplingHint returns a logger overriding the ErrorSampler, see innerJsonLog WithSamplingHint
+func (i *JsonLogger) WithSamplingHint(always bool) Interface {
+	segment := i.segment(nil)
+
+	return segment.WithSamplingHint(always)
+}
+
+// sample applies the error sampler, if any, to ERROR entries or, with allLevelsSampled, to every non FATAL entry.
+// callers must hold the read lock
+func (i *innerJsonLog) sample(level LogLevelEnum, format string) (bool, int) {
+	if i.ErrorSampler == nil || i.sampling == neverSampled || level == FATAL {
+		return true, 0
+	}
+
+	if level != ERROR && i.sampling != allLevelsSampled {
+		return true, 0
+	}
+
+	call := i.caller
+	return i.ErrorSampler.sample(callerPath(call)+"|"+format, func(suppressed int) {
+		i.summarize(level, call, format, suppressed)
+	})
+}
+
+// summarize writes the summary of suppressed entries, carrying the fields of the logger they were logged with
+func (i *innerJsonLog) summarize(level LogLevelEnum, call caller.Ptr, format string, suppressed int) {
+	segment := i.clone()
+	segment.caller = call
+	segment.sampling = neverSampled
+	segment.fields[SuppressedField] = suppressed
+	segment.log(level, "%s", preformatted(format))
+}
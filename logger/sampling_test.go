@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrorSampler(t *testing.T) {
+	now := time.Now()
+	sampler := NewErrorSampler(ErrorSamplerConfiguration{First: 2, Interval: time.Minute})
+	sampler.now = func() time.Time { return now }
+
+	for n := 0; n < 2; n++ {
+		write, suppressed := sampler.Sample("a")
+		assert.True(t, write)
+		assert.Equal(t, 0, suppressed)
+	}
+
+	for n := 0; n < 5; n++ {
+		write, _ := sampler.Sample("a")
+		assert.False(t, write)
+	}
+
+	write, _ := sampler.Sample("b")
+	assert.True(t, write, "fingerprints are sampled independently")
+
+	now = now.Add(time.Minute)
+	write, suppressed := sampler.Sample("a")
+	assert.True(t, write)
+	assert.Equal(t, 5, suppressed)
+
+	write, _ = sampler.Sample("a")
+	assert.False(t, write)
+}
+
+func TestJsonLoggerErrorSampling(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.ErrorSampler = NewErrorSampler(ErrorSamplerConfiguration{First: 1, Interval: time.Hour})
+
+	for n := 0; n < 3; n++ {
+		baseLogger.With("error", fmt.Errorf("boom")).Error("storm")
+		baseLogger.Log("not sampled")
+	}
+
+	logLines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, logLines, 4)
+
+	var entry map[string]any
+	_ = json.Unmarshal(logLines[0], &entry)
+	assert.Equal(t, "storm", entry["message"])
+}
+
+func TestSamplingHint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.ErrorSampler = NewErrorSampler(ErrorSamplerConfiguration{First: 1, Interval: time.Hour})
+
+	billing := baseLogger.WithSamplingHint(true)
+	chatty := baseLogger.WithSamplingHint(false)
+	for n := 0; n < 3; n++ {
+		billing.Error("charge failed")
+		chatty.Debug("poll")
+	}
+
+	var messages []any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(line, &entry))
+		messages = append(messages, entry["message"])
+	}
+	assert.Equal(t, []any{"charge failed", "poll", "charge failed", "charge failed"}, messages)
+}
+
+// lockedBuffer buffer read while written in the background
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func (b *lockedBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+func TestErrorSamplerSummaries(t *testing.T) {
+	buf := new(lockedBuffer)
+	baseLogger, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	baseLogger.ErrorSampler = NewErrorSampler(ErrorSamplerConfiguration{First: 1, Interval: 20 * time.Millisecond})
+
+	log := baseLogger.With("request", "r1")
+	for n := 0; n < 4; n++ {
+		log.Error("storm %d", n)
+	}
+
+	summary := func() map[string]any {
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		if len(lines) < 2 {
+			return nil
+		}
+
+		var entry map[string]any
+		assert.NoError(t, json.Unmarshal(lines[len(lines)-1], &entry))
+		return entry
+	}
+	assert.Eventually(t, func() bool { return summary() != nil }, time.Second, 5*time.Millisecond,
+		"the suppressed count is written once the interval elapses without occurrences")
+
+	entry := summary()
+	assert.Equal(t, float64(3), entry[SuppressedField])
+	assert.Equal(t, "storm %d", entry["message"])
+	assert.Equal(t, "r1", entry["request"])
+	assert.Equal(t, "ERROR", entry["level"])
+
+	baseLogger.ErrorSampler = NewErrorSampler(ErrorSamplerConfiguration{First: 1, Interval: time.Hour})
+	buf.Reset()
+	baseLogger.Error("flushed")
+	baseLogger.Error("flushed")
+	assert.Nil(t, baseLogger.Flush())
+	assert.Equal(t, float64(1), summary()[SuppressedField], "Flush writes the pending summaries")
+}
+
+func TestErrorSamplerEviction(t *testing.T) {
+	sampler := NewErrorSampler(ErrorSamplerConfiguration{First: 1, Interval: time.Hour})
+	summarized := make(chan int, 1)
+	sampler.sample("first", nil)
+	sampler.sample("first", func(suppressed int) { summarized <- suppressed })
+
+	for n := 0; n < maxSampledFingerprints; n++ {
+		sampler.Sample(fmt.Sprint(n))
+	}
+	assert.Len(t, sampler.fingerprints, maxSampledFingerprints, "fingerprints are evicted one at a time")
+
+	// first, the stalest, is evicted once picked among the eviction candidates
+	for n := maxSampledFingerprints; n < 100*maxSampledFingerprints; n++ {
+		select {
+		case suppressed := <-summarized:
+			assert.Equal(t, 1, suppressed)
+			return
+		default:
+			sampler.Sample(fmt.Sprint(n))
+		}
+	}
+	assert.Fail(t, "the evicted fingerprint summary is written")
+}
@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SchemaVersion version of the json output layout, written under the version field
+type SchemaVersion string
+
+// known schema versions
+const (
+	// SchemaV1 timestamp, level, app, scope, message and optional uid, caller plus free form fields
+	SchemaV1 SchemaVersion = "1"
+
+	// CurrentSchemaVersion version written by loggers with schema versioning enabled
+	CurrentSchemaVersion = SchemaV1
+)
+
+// CompatibilityMode how ParseEntry handles versions without a registered parser
+type CompatibilityMode string
+
+const (
+	// StrictCompatibility rejects entries with unknown versions
+	StrictCompatibility CompatibilityMode = "strict"
+
+	// LenientCompatibility parses entries with unknown versions using the current schema
+	LenientCompatibility CompatibilityMode = "lenient"
+)
+
+// ParsedEntry entry decoded according to its schema version
+type ParsedEntry struct {
+	Version   SchemaVersion
+	Timestamp time.Time
+	Level     string
+	App       string
+	Scope     string
+	UID       string
+	Message   string
+
+	// Fields every other key of the entry
+	Fields map[string]any
+}
+
+// SchemaParser decodes and validates a single json entry of a given version
+type SchemaParser func(entry map[string]any) (ParsedEntry, error)
+
+var (
+	schemasMu sync.RWMutex
+	schemas   = map[SchemaVersion]SchemaParser{
+		SchemaV1: parseV1,
+	}
+)
+
+// RegisterSchema registers the parser for a schema version, replacing any previous one
+func RegisterSchema(version SchemaVersion, parser SchemaParser) {
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+
+	schemas[version] = parser
+}
+
+// SchemaVersions returns the registered schema versions, sorted
+func SchemaVersions() []SchemaVersion {
+	schemasMu.RLock()
+	defer schemasMu.RUnlock()
+
+	versions := make([]SchemaVersion, 0, len(schemas))
+	for version := range schemas {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(a, b int) bool { return versions[a] < versions[b] })
+	return versions
+}
+
+// ParseEntry decodes a json line with the parser of its version.
+// entries without version are treated as SchemaV1
+func ParseEntry(line []byte, mode CompatibilityMode) (ParsedEntry, error) {
+	var entry map[string]any
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return ParsedEntry{}, err
+	}
+
+	version := SchemaV1
+	if raw, ok := entry[VersionField]; ok {
+		str, ok := raw.(string)
+		if !ok {
+			return ParsedEntry{}, fmt.Errorf("invalid schema version %v", raw)
+		}
+		version = SchemaVersion(str)
+	}
+
+	schemasMu.RLock()
+	parser, ok := schemas[version]
+	if !ok && mode == LenientCompatibility {
+		parser, ok = schemas[CurrentSchemaVersion]
+	}
+	schemasMu.RUnlock()
+
+	if !ok {
+		return ParsedEntry{}, fmt.Errorf("unsupported schema version %s", version)
+	}
+
+	parsed, err := parser(entry)
+	if err != nil {
+		return ParsedEntry{}, err
+	}
+
+	parsed.Version = version
+	return parsed, nil
+}
+
+// parseV1 validates the SchemaV1 required fields
+func parseV1(entry map[string]any) (ParsedEntry, error) {
+	var parsed ParsedEntry
+	switch level := entry["level"].(type) {
+	case string:
+		parsed.Level = level
+	case float64:
+		parsed.Level = LogLevelEnum(level).String()
+	default:
+		return ParsedEntry{}, fmt.Errorf("missing or invalid level field")
+	}
+
+	required := map[string]*string{
+		"app":     &parsed.App,
+		"scope":   &parsed.Scope,
+		"message": &parsed.Message,
+	}
+
+	for key, dst := range required {
+		value, ok := entry[key].(string)
+		if !ok {
+			return ParsedEntry{}, fmt.Errorf("missing or invalid %s field", key)
+		}
+		*dst = value
+	}
+
+	ts, ok := entry["timestamp"].(string)
+	if !ok {
+		return ParsedEntry{}, fmt.Errorf("missing or invalid timestamp field")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ParsedEntry{}, fmt.Errorf("invalid timestamp field: %w", err)
+	}
+	parsed.Timestamp = timestamp
+	parsed.UID, _ = entry["uid"].(string)
+
+	parsed.Fields = make(map[string]any, len(entry))
+	for key, value := range entry {
+		switch key {
+		case "timestamp", "level", "app", "scope", "message", "uid", VersionField:
+			continue
+		}
+		parsed.Fields[key] = value
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchemaVersionRoundTrip(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log, _ := NewJsonLogger(context.Background(), buf, "TestApp", "TestScope", "TestUID", DEBUG, nil)
+	log.SchemaVersion = CurrentSchemaVersion
+	log.TimestampPrecision = MillisecondPrecision
+	log.With("tenant_id", "t1").Log("hello")
+
+	entry, err := ParseEntry(bytes.TrimSpace(buf.Bytes()), StrictCompatibility)
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaV1, entry.Version)
+	assert.Equal(t, "LOG", entry.Level)
+	assert.Equal(t, "TestApp", entry.App)
+	assert.Equal(t, "TestUID", entry.UID)
+	assert.Equal(t, "hello", entry.Message)
+	assert.Equal(t, "t1", entry.Fields["tenant_id"])
+	assert.NotContains(t, entry.Fields, VersionField)
+}
+
+func TestSchemaCompatibility(t *testing.T) {
+	line := []byte(`{"version":"9","timestamp":"2024-01-01T00:00:00Z","level":"LOG","app":"a","scope":"s","message":"m"}`)
+
+	_, err := ParseEntry(line, StrictCompatibility)
+	assert.Error(t, err)
+
+	entry, err := ParseEntry(line, LenientCompatibility)
+	assert.NoError(t, err)
+	assert.Equal(t, SchemaVersion("9"), entry.Version)
+
+	RegisterSchema("9", func(map[string]any) (ParsedEntry, error) { return ParsedEntry{Message: "custom"}, nil })
+	defer func() {
+		schemasMu.Lock()
+		delete(schemas, "9")
+		schemasMu.Unlock()
+	}()
+
+	entry, err = ParseEntry(line, StrictCompatibility)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", entry.Message)
+	assert.Contains(t, SchemaVersions(), SchemaVersion("9"))
+
+	_, err = ParseEntry([]byte(`{"timestamp":"2024-01-01T00:00:00Z","level":"LOG"}`), StrictCompatibility)
+	assert.Error(t, err)
+}
+
+func TestRequiredFields(t *testing.T) {
+	var reported []error
+	defer func(handler func(error)) { DiagnosticsHandler = handler }(DiagnosticsHandler)
+	DiagnosticsHandler = func(err error) { reported = append(reported, err) }
+
+	buf := new(bytes.Buffer)
+	log, _ := NewJsonLogger(context.Background(), buf, "TestApp", "payments", "TestUID", DEBUG, nil)
+	log.RequiredFields = map[string][]string{"payments": {"tenant_id"}}
+
+	log.With("tenant_id", "t1").Log("valid")
+	assert.Empty(t, reported)
+
+	log.Log("missing")
+	log.With("other", 1).Log("missing child")
+	assert.Len(t, reported, 2)
+	assert.Equal(t, []string{"tenant_id"}, reported[0].(RequiredFieldsError).Missing)
+
+	reported = nil
+	log.Scope = "prod"
+	log.RequiredFields["prod"] = []string{"tenant_id"}
+	log.Log("prod is not validated")
+	assert.Empty(t, reported)
+}
@@ -0,0 +1,326 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sentryClient client name sent in the sentry auth header
+const sentryClient = "pixie-logger-go/1.0"
+
+// entry keys converted to dedicated sentry event attributes, not repeated in extra
+var sentryEventKeys = map[string]bool{
+	"timestamp": true, "level": true, "app": true, "scope": true, "message": true,
+	ErrorField: true, CallerField: true, HostField: true, LoggerField: true,
+}
+
+// SentryLevel returns the sentry level of level
+func SentryLevel(level LogLevelEnum) string {
+	switch level {
+	case FATAL:
+		return "fatal"
+	case ERROR:
+		return "error"
+	case WARN:
+		return "warning"
+	case DEBUG:
+		return "debug"
+	default:
+		return "info"
+	}
+}
+
+// SentryWriterConfiguration sentry sink configuration, the envelope endpoint is derived from DSN
+type SentryWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	DSN         string `toml:"dsn" json:"dsn" mapstructure:"dsn"`
+	Environment string `toml:"environment" json:"environment" mapstructure:"environment"`
+	Release     string `toml:"release" json:"release" mapstructure:"release"`
+
+	// Level most verbose level forwarded, ERROR when unset
+	Level LogLevelEnum `toml:"level" json:"level" mapstructure:"level"`
+
+	// Fingerprint dotted paths of the entry fields grouping events into issues,
+	// defaults to the scope, the caller and the error type or message
+	Fingerprint []string `toml:"fingerprint" json:"fingerprint" mapstructure:"fingerprint"`
+}
+
+// SentryLoggerConfiguration json logger writing every entry to Writer, stdout by default,
+// and forwarding the entries up to Level to sentry
+type SentryLoggerConfiguration struct {
+	JSONLoggerConfiguration   `mapstructure:",squash"`
+	SentryWriterConfiguration `mapstructure:",squash"`
+}
+
+// SentryWriter converts entries up to Level into sentry events, more verbose entries are ignored.
+// events are queued and sent on flush, which the logger triggers for ERROR entries
+type SentryWriter struct {
+	cfg    SentryWriterConfiguration
+	dsn    string
+	sender *httpSender
+	header http.Header
+	batch  *batcher
+}
+
+// NewSentryWriter returns a writer sending events to the project of cfg.DSN
+func NewSentryWriter(cfg SentryWriterConfiguration) (*SentryWriter, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil || dsn.User == nil || dsn.Host == "" {
+		return nil, fmt.Errorf("invalid sentry dsn %q", cfg.DSN)
+	}
+
+	path := strings.Trim(dsn.Path, "/")
+	project := path[strings.LastIndex(path, "/")+1:]
+	if project == "" {
+		return nil, fmt.Errorf("sentry dsn %q without project", cfg.DSN)
+	}
+
+	prefix := strings.TrimSuffix(path, project)
+	cfg.URL = fmt.Sprintf("%s://%s/%sapi/%s/envelope/", dsn.Scheme, dsn.Host, prefix, project)
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, dsn.User.Username())
+	w := &SentryWriter{
+		cfg:    cfg,
+		dsn:    cfg.DSN,
+		sender: sender,
+		header: http.Header{"Content-Type": {"application/x-sentry-envelope"}, "X-Sentry-Auth": {auth}},
+	}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.send)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues p when its level field is up to Level
+func (w *SentryWriter) Write(p []byte) (int, error) {
+	level, err := ParseLogLevel(entryField(p, "level"))
+	if err != nil {
+		return len(p), nil
+	}
+
+	return w.WriteLevel(level, p)
+}
+
+// WriteLevel queues p when level is up to Level
+func (w *SentryWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	if level > w.cfg.Level {
+		return len(p), nil
+	}
+
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends the pending events
+func (w *SentryWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close sends the pending events
+func (w *SentryWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *SentryWriter) send(batch [][]byte) error {
+	for _, entry := range batch {
+		fields, err := decodeEntry(entry)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+
+		event := w.event(fields, time.Now())
+		header, _ := json.Marshal(map[string]any{"event_id": event["event_id"], "dsn": w.dsn})
+		item, err := json.Marshal(event)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+
+		var body bytes.Buffer
+		body.Write(header)
+		body.WriteString("\n{\"type\":\"event\",\"length\":" + strconv.Itoa(len(item)) + "}\n")
+		body.Write(item)
+		body.WriteByte('\n')
+
+		if _, err := w.sender.send("", w.header, body.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// event converts the entry fields to a sentry event
+func (w *SentryWriter) event(fields map[string]any, now time.Time) map[string]any {
+	id := make([]byte, 16)
+	_, _ = io.ReadFull(rand.Reader, id)
+
+	level := ERROR
+	if parsed, err := ParseLogLevel(fieldString(fields["level"])); err == nil {
+		level = parsed
+	}
+
+	logger := fieldString(fields["scope"])
+	if name := fieldString(fields[LoggerField]); name != "" {
+		logger = name
+	}
+
+	host := hostname
+	if value, ok := fields[HostField]; ok {
+		host = fieldString(value)
+	}
+
+	tags := map[string]string{"app": fieldString(fields["app"]), "scope": fieldString(fields["scope"])}
+	callerPath, _ := lookupField(fields, CallerField+".Path")
+	if callerPath != nil {
+		tags[CallerField] = fieldString(callerPath)
+	}
+
+	extra := map[string]any{}
+	for key, value := range fields {
+		if !sentryEventKeys[key] {
+			extra[key] = value
+		}
+	}
+
+	event := map[string]any{
+		"event_id":    hex.EncodeToString(id),
+		"timestamp":   float64(entryTime(fields, now).UnixMilli()) / 1000,
+		"level":       SentryLevel(level),
+		"logger":      logger,
+		"platform":    "go",
+		"message":     map[string]string{"formatted": fieldString(fields["message"])},
+		"server_name": host,
+		"tags":        tags,
+		"extra":       extra,
+	}
+
+	if w.cfg.Environment != "" {
+		event["environment"] = w.cfg.Environment
+	}
+
+	if w.cfg.Release != "" {
+		event["release"] = w.cfg.Release
+	}
+
+	exceptions := sentryExceptions(fields[ErrorField])
+	if len(exceptions) > 0 {
+		event["exception"] = map[string]any{"values": exceptions}
+	}
+
+	event["fingerprint"] = w.fingerprint(fields, exceptions)
+	return event
+}
+
+// fingerprint values of the configured paths, or the scope, the caller and the error type or message
+func (w *SentryWriter) fingerprint(fields map[string]any, exceptions []map[string]any) []string {
+	if len(w.cfg.Fingerprint) > 0 {
+		fingerprint := make([]string, len(w.cfg.Fingerprint))
+		for i, path := range w.cfg.Fingerprint {
+			value, _ := lookupField(fields, path)
+			fingerprint[i] = fieldString(value)
+		}
+		return fingerprint
+	}
+
+	callerPath, _ := lookupField(fields, CallerField+".Path")
+	fingerprint := []string{fieldString(fields["scope"]), fieldString(callerPath)}
+	if len(exceptions) > 0 {
+		return append(fingerprint, fieldString(exceptions[len(exceptions)-1]["type"]))
+	}
+	return append(fingerprint, fieldString(fields["message"]))
+}
+
+// sentryExceptions converts the error field to sentry exceptions, innermost first as sentry expects,
+// the call site stack attached to the outermost one
+func sentryExceptions(value any) []map[string]any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []map[string]any{{"type": "error", "value": v}}
+	case map[string]any:
+		chain, _ := v[ErrorChainField].([]any)
+		exceptions := make([]map[string]any, 0, len(chain)+1)
+		for i := len(chain) - 1; i >= 0; i-- {
+			if link, ok := chain[i].(map[string]any); ok {
+				exceptions = append(exceptions, map[string]any{
+					"type":  fieldString(link["type"]),
+					"value": fieldString(link["errorString"]),
+				})
+			}
+		}
+
+		if len(exceptions) == 0 {
+			exceptions = append(exceptions, map[string]any{"type": "error", "value": fieldString(v["errorString"])})
+		}
+
+		if stack, ok := v[ErrorStackField].([]any); ok {
+			exceptions[len(exceptions)-1]["stacktrace"] = map[string]any{"frames": sentryFrames(stack)}
+		}
+
+		return exceptions
+	default:
+		return []map[string]any{{"type": "error", "value": fieldString(v)}}
+	}
+}
+
+// sentryFrames converts "function file:line" frames, innermost first, to sentry frames, outermost first
+func sentryFrames(stack []any) []map[string]any {
+	frames := make([]map[string]any, 0, len(stack))
+	for i := len(stack) - 1; i >= 0; i-- {
+		function, location, _ := strings.Cut(fieldString(stack[i]), " ")
+		file, line, _ := strings.Cut(location, ":")
+		lineno, _ := strconv.Atoi(line)
+		frames = append(frames, map[string]any{"function": function, "abs_path": file, "lineno": lineno})
+	}
+	return frames
+}
+
+func createSentryLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg SentryLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewSentryWriter(cfg.SentryWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	local := cfg.Writer
+	if local == nil {
+		local = os.Stdout
+	}
+
+	cfg.Writer = NewMultiWriter(
+		MultiDestination{Writer: local, Level: DEBUG},
+		MultiDestination{Writer: writer, Level: writer.cfg.Level},
+	)
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
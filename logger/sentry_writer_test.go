@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSentryDriver(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.Equal(t, "/api/42/envelope/", r.URL.Path)
+		assert.Contains(t, r.Header.Get("X-Sentry-Auth"), "sentry_key=public")
+
+		body, _ := io.ReadAll(r.Body)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		assert.Len(t, lines, 3)
+
+		var event map[string]any
+		assert.Nil(t, json.Unmarshal([]byte(lines[2]), &event))
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	var local bytes.Buffer
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   SentryLoggerDriver,
+		Values: map[string]any{
+			"Writer":      &local,
+			"dsn":         strings.Replace(server.URL, "://", "://public@", 1) + "/42",
+			"environment": "test",
+			"ErrorStack":  true,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("started")
+	log.Warn("slow")
+	log.WithError(fmt.Errorf("charge: %w", errors.New("card declined"))).With("order", 7).Error("payment failed")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 3, strings.Count(local.String(), "\n"))
+	assert.Len(t, events, 1)
+
+	event := events[0]
+	assert.Equal(t, "error", event["level"])
+	assert.Equal(t, "Scope", event["logger"])
+	assert.Equal(t, "test", event["environment"])
+	assert.Equal(t, map[string]any{"formatted": "payment failed"}, event["message"])
+	assert.Equal(t, float64(7), event["extra"].(map[string]any)["order"])
+	assert.Equal(t, []any{"Scope", "logger.TestSentryDriver", "*fmt.wrapError"}, event["fingerprint"])
+
+	exceptions := event["exception"].(map[string]any)["values"].([]any)
+	assert.Len(t, exceptions, 2)
+	assert.Equal(t, "card declined", exceptions[0].(map[string]any)["value"])
+	assert.Equal(t, "charge: card declined", exceptions[1].(map[string]any)["value"])
+	assert.NotEmpty(t, exceptions[1].(map[string]any)["stacktrace"])
+}
+
+func TestSentryWriterLevel(t *testing.T) {
+	_, err := NewSentryWriter(SentryWriterConfiguration{DSN: "https://sentry.io/42"})
+	assert.Error(t, err)
+
+	writer, err := NewSentryWriter(SentryWriterConfiguration{DSN: "https://key@sentry.example.com/prefix/42"})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	assert.Equal(t, "https://sentry.example.com/prefix/api/42/envelope/", writer.cfg.URL)
+
+	_, err = writer.Write([]byte(`{"level":"WARN","message":"skipped"}` + "\n"))
+	assert.Nil(t, err)
+	assert.Empty(t, writer.batch.pending)
+
+	event := writer.event(map[string]any{"scope": "s", "message": "m", "error": "boom"}, time.Now())
+	assert.Equal(t, []string{"s", "", "error"}, event["fingerprint"])
+}
@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// seq defaults
+const (
+	DefaultSeqURL  = "http://localhost:5341"
+	seqIngestPath  = "/api/events/raw?clef"
+	seqContentType = "application/vnd.serilog.clef"
+	clefPrefix     = "@"
+)
+
+// SeqLevel returns the seq level of level
+func SeqLevel(level LogLevelEnum) string {
+	switch level {
+	case FATAL:
+		return "Fatal"
+	case ERROR:
+		return "Error"
+	case WARN:
+		return "Warning"
+	case DEBUG:
+		return "Debug"
+	default:
+		return "Information"
+	}
+}
+
+// CLEFEncoder encodes entries as compact log event format events: timestamp as @t, message as the @mt template,
+// level as @l and the error as the @x exception. other fields are kept as properties, @ prefixed keys escaped to @@
+type CLEFEncoder struct{}
+
+// Encode renders the entry as a clef event
+func (CLEFEncoder) Encode(entry map[string]any) ([]byte, error) {
+	event := make(map[string]any, len(entry)+1)
+	for key, value := range entry {
+		switch key {
+		case "timestamp", "level", "message", ErrorField:
+			continue
+		}
+
+		if strings.HasPrefix(key, clefPrefix) {
+			key = clefPrefix + key
+		}
+		event[key] = value
+	}
+
+	event["@t"] = entryTime(entry, time.Now()).Format(time.RFC3339Nano)
+	event["@mt"] = clefTemplate(fieldString(entry["message"]))
+
+	if level, err := ParseLogLevel(fieldString(entry["level"])); err == nil {
+		event["@l"] = SeqLevel(level)
+	}
+
+	if value, ok := entry[ErrorField]; ok {
+		event["@x"] = clefException(value)
+	}
+
+	return json.Marshal(event)
+}
+
+// clefTemplate escapes the braces of an already rendered message, so seq doesn't read them as template holes
+func clefTemplate(message string) string {
+	return strings.NewReplacer("{", "{{", "}", "}}").Replace(message)
+}
+
+// clefException renders a logged error as the error string followed by its stack frames, one per line
+func clefException(value any) string {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return fieldString(value)
+	}
+
+	var exception strings.Builder
+	exception.WriteString(fieldString(fields["errorString"]))
+
+	switch stack := fields[ErrorStackField].(type) {
+	case []string:
+		for _, frame := range stack {
+			exception.WriteString("\n   at " + frame)
+		}
+	case []any:
+		for _, frame := range stack {
+			exception.WriteString("\n   at " + fieldString(frame))
+		}
+	}
+
+	return exception.String()
+}
+
+// SeqWriterConfiguration seq sink configuration, URL is the seq server, defaults to DefaultSeqURL
+type SeqWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	// APIKey sent as X-Seq-ApiKey, optional when the server accepts unauthenticated ingestion
+	APIKey string `toml:"apiKey" json:"apiKey" mapstructure:"apiKey"`
+}
+
+// SeqLoggerConfiguration json logger ingesting into seq
+type SeqLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	SeqWriterConfiguration  `mapstructure:",squash"`
+}
+
+// SeqWriter batches entries into seq raw ingestion requests of newline delimited CLEFEncoder events
+type SeqWriter struct {
+	sender *httpSender
+	header http.Header
+	url    string
+	batch  *batcher
+}
+
+// NewSeqWriter returns a writer ingesting into the seq server at cfg.URL
+func NewSeqWriter(cfg SeqWriterConfiguration) (*SeqWriter, error) {
+	if cfg.URL == "" {
+		cfg.URL = DefaultSeqURL
+	}
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{"Content-Type": {seqContentType}}
+	if cfg.APIKey != "" {
+		header.Set("X-Seq-ApiKey", cfg.APIKey)
+	}
+
+	w := &SeqWriter{
+		sender: sender,
+		header: header,
+		url:    strings.TrimRight(cfg.URL, "/") + seqIngestPath,
+	}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.ingest)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next request
+func (w *SeqWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush ingests the pending entries
+func (w *SeqWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close ingests the pending entries
+func (w *SeqWriter) Close() error {
+	return w.batch.close()
+}
+
+// ingest converts the batch to clef events, entries that aren't json are sent as the message
+func (w *SeqWriter) ingest(batch [][]byte) error {
+	var body bytes.Buffer
+	for _, entry := range batch {
+		fields, err := decodeEntry(entry)
+		if err != nil {
+			fields = map[string]any{"message": strings.TrimRight(string(entry), "\n")}
+		}
+
+		event, err := CLEFEncoder{}.Encode(fields)
+		if err != nil {
+			reportDiagnostic(err)
+			continue
+		}
+
+		body.Write(event)
+		body.WriteByte('\n')
+	}
+
+	if body.Len() == 0 {
+		return nil
+	}
+
+	_, err := w.sender.send(w.url, w.header, body.Bytes())
+	return err
+}
+
+func createSeqLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg SeqLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewSeqWriter(cfg.SeqWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
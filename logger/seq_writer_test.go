@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCLEFEncoder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log, err := NewJsonLogger(context.Background(), buf, "App", "Scope", "", DEBUG, nil)
+	assert.Nil(t, err)
+	log.Encoder = CLEFEncoder{}
+
+	log.With("@user", "jane").Err(errors.New("boom"), "failed {id}")
+
+	var event map[string]any
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &event))
+	assert.Equal(t, "failed {{id}}", event["@mt"])
+	assert.Equal(t, "Error", event["@l"])
+	assert.Equal(t, "boom", event["@x"])
+	assert.Equal(t, "jane", event["@@user"])
+	assert.Equal(t, "Scope", event["scope"])
+	assert.NotContains(t, event, "message")
+	assert.NotContains(t, event, ErrorField)
+
+	_, err = time.Parse(time.RFC3339Nano, event["@t"].(string))
+	assert.Nil(t, err)
+}
+
+func TestCLEFException(t *testing.T) {
+	assert.Equal(t, "boom\n   at main.run main.go:10", clefException(map[string]any{
+		"errorString":   "boom",
+		ErrorStackField: []any{"main.run main.go:10"},
+	}))
+}
+
+func TestSeqDriver(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		header http.Header
+		body   string
+		path   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		header = r.Header
+		path = r.URL.RequestURI()
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   SeqLoggerDriver,
+		Values:   map[string]any{"url": server.URL + "/", "apiKey": "key"},
+	})
+	assert.Nil(t, err)
+
+	log.Log("first")
+	log.Warn("second")
+	assert.Nil(t, log.(Syncer).Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "/api/events/raw?clef", path)
+	assert.Equal(t, "key", header.Get("X-Seq-ApiKey"))
+	assert.Equal(t, "application/vnd.serilog.clef", header.Get("Content-Type"))
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	assert.Len(t, lines, 2)
+
+	var event map[string]any
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "second", event["@mt"])
+	assert.Equal(t, "Warning", event["@l"])
+	assert.Equal(t, "App", event["app"])
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ExitFunc terminates the process once loggers are closed or after a Fatal entry, replaceable in tests
+var ExitFunc = os.Exit
+
+// HandleShutdownSignals flushes and closes all registered loggers on SIGTERM or SIGINT
+// and exits the process with the conventional 128+signal code.
+// The handling stops when ctx is done or the returned func is called.
+func HandleShutdownSignals(ctx context.Context) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer signal.Stop(signals)
+		handleShutdown(ctx, signals)
+	}()
+
+	return cancel
+}
+
+func handleShutdown(ctx context.Context, signals <-chan os.Signal) {
+	select {
+	case <-ctx.Done():
+	case sig := <-signals:
+		_ = CloseAll()
+
+		code := 1
+		if s, ok := sig.(syscall.Signal); ok {
+			code = 128 + int(s)
+		}
+		ExitFunc(code)
+	}
+}
@@ -2,8 +2,11 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/pixie-sh/logger-go/caller"
 	"github.com/pixie-sh/logger-go/env"
+	"io"
 	"os"
 )
 
@@ -12,25 +15,88 @@ var Logger Interface
 var JLogger *JsonLogger
 
 func init() {
+	var writer io.Writer = os.Stdout
+	if env.IsStderrSplit() {
+		writer = NewStdSplitWriter()
+	}
+
 	JLogger, _ = NewJsonLogger(
 		context.Background(),
-		os.Stdout,
+		writer,
 		fmt.Sprintf("%s-%s", env.EnvAppName(), env.EnvAppVersion()),
 		env.EnvScope(),
 		fmt.Sprintf("%s-%s", env.EnvAppName(), env.EnvAppVersion()),
 		func() LogLevelEnum {
-			switch env.EnvLogLevel() {
-			case "DEBUG":
-				return DEBUG
-			case "WARN":
-				return WARN
-			case "ERROR":
-				return ERROR
-			default:
+			level, err := ParseLogLevel(env.EnvLogLevel())
+			if err != nil {
 				return LOG
 			}
+			return level
 		}(),
 		[]string{TraceID})
 
+	JLogger.Encoder = defaultEncoder()
 	Logger = JLogger
+	RegisterSyncer(JLogger)
+}
+
+// Clone returns a copy of the global Logger
+func Clone() Interface {
+	return Logger.Clone()
+}
+
+// WithCtx returns a copy of the global Logger logging the ctx fields
+func WithCtx(ctx context.Context) Interface {
+	return Logger.WithCtx(ctx)
+}
+
+// With returns a copy of the global Logger with key set to value, primitive values are kept typed, see WithTyped
+func With[T any](key string, value T) Interface {
+	return WithTyped(Logger, key, value)
+}
+
+// WithLevel returns a copy of the global Logger using level as threshold, eg: to quiet down a noisy subsystem
+func WithLevel(level LogLevelEnum) Interface {
+	return Logger.WithLevel(level)
+}
+
+// Fatal logs a message at FATAL level with the global Logger, flushes every pending write and exits with ExitFunc(1)
+func Fatal(format string, args ...any) {
+	fatal(caller.Upper(), format, args...)
+}
+
+// fatal logs at FATAL level with the global Logger, using call as caller, then flushes and exits
+func fatal(call caller.Ptr, format string, args ...any) {
+	cl, ok := Logger.(callerLogger)
+	if !ok {
+		Logger.Fatal(format, args...)
+		return
+	}
+
+	cl.logWithCaller(FATAL, call, format, args...)
+	if s, ok := Logger.(Syncer); ok {
+		_ = s.Flush()
+	}
+	_ = FlushAll()
+	ExitFunc(1)
+}
+
+// Flush flushes the global Logger and every registered Syncer, draining buffered and async writers
+func Flush() error {
+	var err error
+	if s, ok := Logger.(Syncer); ok {
+		err = s.Flush()
+	}
+
+	return errors.Join(err, FlushAll())
+}
+
+// Close flushes and closes the global Logger and every registered Syncer, to be called on shutdown
+func Close() error {
+	var err error
+	if s, ok := Logger.(Syncer); ok {
+		err = s.Close()
+	}
+
+	return errors.Join(err, CloseAll())
 }
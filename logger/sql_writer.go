@@ -0,0 +1,235 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"strings"
+	"time"
+)
+
+// sql sink defaults
+const (
+	DefaultSQLTable             = "logs"
+	DefaultSQLRetentionInterval = time.Hour
+)
+
+// SQLPlaceholder bind parameter style of the database
+type SQLPlaceholder string
+
+// supported placeholder styles
+const (
+	// QuestionPlaceholder ?, used by sqlite and mysql
+	QuestionPlaceholder SQLPlaceholder = "question"
+
+	// DollarPlaceholder $1, used by postgres
+	DollarPlaceholder SQLPlaceholder = "dollar"
+)
+
+// SQLWriterConfiguration sql sink configuration, DB takes precedence over DriverName and DSN
+type SQLWriterConfiguration struct {
+	DB          *sql.DB
+	DriverName  string         `toml:"driverName" json:"driverName" mapstructure:"driverName"`
+	DSN         string         `toml:"dsn" json:"dsn" mapstructure:"dsn"`
+	Table       string         `toml:"table" json:"table" mapstructure:"table"`
+	Placeholder SQLPlaceholder `toml:"placeholder" json:"placeholder" mapstructure:"placeholder"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+
+	// Retention entries older than it are deleted every RetentionInterval, kept forever when 0
+	Retention         time.Duration `toml:"retention" json:"retention" mapstructure:"retention"`
+	RetentionInterval time.Duration `toml:"retentionInterval" json:"retentionInterval" mapstructure:"retentionInterval"`
+}
+
+// SQLLoggerConfiguration json logger writing to a database table
+type SQLLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	SQLWriterConfiguration  `mapstructure:",squash"`
+}
+
+// SQLWriter batches entries into a database table with the columns
+// created_at (unix nanoseconds), level, app, scope, message and entry (the encoded entry)
+type SQLWriter struct {
+	db      *sql.DB
+	ownsDB  bool
+	insert  string
+	cleanup string
+	batch   *batcher
+
+	stopRetention chan struct{}
+	retentionDone chan struct{}
+}
+
+// NewSQLWriter opens the database when needed, creates the table and starts the retention job
+func NewSQLWriter(cfg SQLWriterConfiguration) (*SQLWriter, error) {
+	w := &SQLWriter{db: cfg.DB}
+	if w.db == nil {
+		db, err := sql.Open(cfg.DriverName, cfg.DSN)
+		if err != nil {
+			return nil, err
+		}
+		w.db = db
+		w.ownsDB = true
+	}
+
+	if cfg.Table == "" {
+		cfg.Table = DefaultSQLTable
+	}
+
+	if cfg.Placeholder == "" {
+		cfg.Placeholder = QuestionPlaceholder
+		if strings.Contains(cfg.DriverName, "postgres") || strings.Contains(cfg.DriverName, "pgx") {
+			cfg.Placeholder = DollarPlaceholder
+		}
+	}
+
+	_, err := w.db.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (created_at BIGINT NOT NULL, level VARCHAR(16), app VARCHAR(255), scope VARCHAR(255), message TEXT, entry TEXT NOT NULL)",
+		cfg.Table))
+	if err != nil {
+		w.closeDB()
+		return nil, err
+	}
+
+	w.insert = fmt.Sprintf("INSERT INTO %s (created_at, level, app, scope, message, entry) VALUES (%s)", cfg.Table, placeholders(cfg.Placeholder, 6))
+	w.cleanup = fmt.Sprintf("DELETE FROM %s WHERE created_at < %s", cfg.Table, placeholders(cfg.Placeholder, 1))
+	w.batch = newBatcher(cfg.BatchSize, cfg.FlushInterval, w.write)
+
+	if cfg.Retention > 0 {
+		if cfg.RetentionInterval <= 0 {
+			cfg.RetentionInterval = DefaultSQLRetentionInterval
+		}
+
+		w.stopRetention = make(chan struct{})
+		w.retentionDone = make(chan struct{})
+		go w.retain(cfg.Retention, cfg.RetentionInterval)
+	}
+
+	return w, nil
+}
+
+// Write queues the entry for the next batch
+func (w *SQLWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush inserts the pending entries
+func (w *SQLWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close inserts the pending entries, stops the retention job and closes the database when opened by the writer
+func (w *SQLWriter) Close() error {
+	err := w.batch.close()
+	if w.stopRetention != nil {
+		close(w.stopRetention)
+		<-w.retentionDone
+		w.stopRetention = nil
+	}
+
+	w.closeDB()
+	return err
+}
+
+// Cleanup deletes entries created before older
+func (w *SQLWriter) Cleanup(older time.Time) error {
+	_, err := w.db.Exec(w.cleanup, older.UnixNano())
+	return err
+}
+
+// write inserts batch in a single transaction
+func (w *SQLWriter) write(batch [][]byte) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(w.insert)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	now := time.Now().UnixNano()
+	for _, entry := range batch {
+		entry = []byte(strings.TrimRight(string(entry), "\n"))
+
+		var columns struct {
+			Level   any    `json:"level"`
+			App     string `json:"app"`
+			Scope   string `json:"scope"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(entry, &columns)
+
+		var level string
+		if columns.Level != nil {
+			level = fmt.Sprint(columns.Level)
+		}
+
+		if _, err = stmt.Exec(now, level, columns.App, columns.Scope, columns.Message, string(entry)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (w *SQLWriter) retain(retention time.Duration, interval time.Duration) {
+	defer close(w.retentionDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stopRetention:
+			return
+		case <-ticker.C:
+			if err := w.Cleanup(time.Now().Add(-retention)); err != nil {
+				reportDiagnostic(err)
+			}
+		}
+	}
+}
+
+func (w *SQLWriter) closeDB() {
+	if w.ownsDB {
+		_ = w.db.Close()
+	}
+}
+
+func placeholders(style SQLPlaceholder, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = "?"
+		if style == DollarPlaceholder {
+			params[i] = fmt.Sprintf("$%d", i+1)
+		}
+	}
+	return strings.Join(params, ", ")
+}
+
+func createSQLLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg SQLLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewSQLWriter(cfg.SQLWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingDriver database/sql driver recording the executed statements
+type recordingDriver struct {
+	mu    sync.Mutex
+	execs []recordedExec
+	txs   int
+}
+
+type recordedExec struct {
+	query string
+	args  []driver.Value
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+func (d *recordingDriver) queries(prefix string) []recordedExec {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var found []recordedExec
+	for _, e := range d.execs {
+		if strings.HasPrefix(e.query, prefix) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{d: c.d, query: query}, nil
+}
+func (c *recordingConn) Close() error { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	c.d.txs++
+	c.d.mu.Unlock()
+	return recordingTx{}, nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error   { return nil }
+func (recordingTx) Rollback() error { return nil }
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.execs = append(s.d.execs, recordedExec{query: s.query, args: args})
+	return driver.RowsAffected(1), nil
+}
+func (s *recordingStmt) Query([]driver.Value) (driver.Rows, error) { return nil, io.EOF }
+
+func (d *recordingDriver) Connect(context.Context) (driver.Conn, error) { return d.Open("") }
+func (d *recordingDriver) Driver() driver.Driver                        { return d }
+
+func newRecordingDB() (*sql.DB, *recordingDriver) {
+	d := &recordingDriver{}
+	return sql.OpenDB(d), d
+}
+
+func TestSQLWriterBatches(t *testing.T) {
+	db, d := newRecordingDB()
+	defer func() { _ = db.Close() }()
+
+	log, err := newConfiguredJsonLogger(context.Background(), Configuration{App: "app", Scope: "scope", LogLevel: DEBUG}, JSONLoggerConfiguration{})
+	assert.NoError(t, err)
+
+	writer, err := NewSQLWriter(SQLWriterConfiguration{DB: db, BatchSize: 2, FlushInterval: time.Hour})
+	assert.NoError(t, err)
+	assert.Len(t, d.queries("CREATE TABLE IF NOT EXISTS logs"), 1)
+
+	sqlLog := log.WithWriter(writer)
+	sqlLog.Log("first")
+	assert.Empty(t, d.queries("INSERT"))
+
+	sqlLog.Warn("second")
+	inserts := d.queries("INSERT INTO logs")
+	assert.Len(t, inserts, 2)
+	assert.Equal(t, 1, d.txs)
+	assert.Equal(t, "WARN", inserts[1].args[1])
+	assert.Equal(t, "app", inserts[1].args[2])
+	assert.Equal(t, "second", inserts[1].args[4])
+	assert.Contains(t, inserts[1].query, "VALUES (?, ?, ?, ?, ?, ?)")
+
+	sqlLog.Log("third")
+	assert.NoError(t, writer.Close())
+	assert.Len(t, d.queries("INSERT"), 3)
+}
+
+func TestSQLWriterRetention(t *testing.T) {
+	db, d := newRecordingDB()
+	defer func() { _ = db.Close() }()
+
+	writer, err := NewSQLWriter(SQLWriterConfiguration{
+		DB:                db,
+		Table:             "app_logs",
+		Placeholder:       DollarPlaceholder,
+		Retention:         time.Hour,
+		RetentionInterval: time.Millisecond,
+	})
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return len(d.queries("DELETE FROM app_logs WHERE created_at < $1")) > 0 }, time.Second, time.Millisecond)
+	assert.NoError(t, writer.Close())
+
+	deleted := d.queries("DELETE")[0]
+	assert.Less(t, deleted.args[0].(int64), time.Now().Add(-59*time.Minute).UnixNano())
+}
@@ -0,0 +1,282 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SendMessageBatch limits
+const (
+	SQSMaxBatchMessages = 10
+	SQSMaxBatchBytes    = 262144
+	SQSMaxAttributes    = 10
+)
+
+// SQSMessage message of a SendMessageBatch call, GroupID and DeduplicationID are only set for fifo queues
+type SQSMessage struct {
+	ID              string
+	Body            string
+	Attributes      map[string]string
+	GroupID         string
+	DeduplicationID string
+}
+
+// SQSClient sends a message batch, implemented by adapters over the aws sdk.
+// it returns the ids of the messages the service failed to enqueue
+type SQSClient interface {
+	SendMessageBatch(ctx context.Context, queueURL string, messages []SQSMessage) ([]string, error)
+}
+
+// SQSWriterConfiguration sqs sink configuration
+type SQSWriterConfiguration struct {
+	Client   SQSClient
+	QueueURL string `toml:"queueUrl" json:"queueUrl" mapstructure:"queueUrl"`
+
+	// Attributes dotted paths of the entry fields sent as string message attributes, up to SQSMaxAttributes
+	Attributes []string `toml:"attributes" json:"attributes" mapstructure:"attributes"`
+
+	// GroupIDField dotted path of the fifo message group id, defaults to the app.
+	// DeduplicationIDField dotted path of the fifo deduplication id, defaults to a hash of the entry
+	GroupIDField         string `toml:"groupIdField" json:"groupIdField" mapstructure:"groupIdField"`
+	DeduplicationIDField string `toml:"deduplicationIdField" json:"deduplicationIdField" mapstructure:"deduplicationIdField"`
+
+	// Sync sends every entry as it's written and returns delivery errors to the caller, for audit logs
+	Sync bool `toml:"sync" json:"sync" mapstructure:"sync"`
+
+	BatchSize     int           `toml:"batchSize" json:"batchSize" mapstructure:"batchSize"`
+	FlushInterval time.Duration `toml:"flushInterval" json:"flushInterval" mapstructure:"flushInterval"`
+	Timeout       time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// MaxRetries retries of the messages the service failed to enqueue, backing off from RetryBackoff
+	MaxRetries   int           `toml:"maxRetries" json:"maxRetries" mapstructure:"maxRetries"`
+	RetryBackoff time.Duration `toml:"retryBackoff" json:"retryBackoff" mapstructure:"retryBackoff"`
+}
+
+// SQSLoggerConfiguration json logger sending to an sqs queue
+type SQSLoggerConfiguration struct {
+	JSONLoggerConfiguration `mapstructure:",squash"`
+	SQSWriterConfiguration  `mapstructure:",squash"`
+}
+
+// SQSDeliveryError messages still not enqueued once retries are exhausted
+type SQSDeliveryError struct {
+	QueueURL string
+	Messages int
+}
+
+// Error returns the delivery failure description
+func (e SQSDeliveryError) Error() string {
+	return fmt.Sprintf("sqs: %d messages to %s not delivered", e.Messages, e.QueueURL)
+}
+
+// SQSWriter sends entries to an sqs queue in batches of up to 10 messages, retrying the failed ones
+type SQSWriter struct {
+	cfg   SQSWriterConfiguration
+	fifo  bool
+	batch *batcher
+}
+
+// NewSQSWriter returns a writer sending to cfg.QueueURL through cfg.Client
+func NewSQSWriter(cfg SQSWriterConfiguration) (*SQSWriter, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("sqs writer requires a Client")
+	}
+
+	if cfg.QueueURL == "" {
+		return nil, fmt.Errorf("sqs writer requires a QueueURL")
+	}
+
+	if len(cfg.Attributes) > SQSMaxAttributes {
+		return nil, fmt.Errorf("sqs messages carry up to %d attributes, %d configured", SQSMaxAttributes, len(cfg.Attributes))
+	}
+
+	if cfg.BatchSize <= 0 || cfg.BatchSize > SQSMaxBatchMessages {
+		cfg.BatchSize = SQSMaxBatchMessages
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+
+	w := &SQSWriter{cfg: cfg, fifo: strings.HasSuffix(cfg.QueueURL, ".fifo")}
+	if !cfg.Sync {
+		w.batch = newBatcher(cfg.BatchSize, cfg.FlushInterval, w.send)
+	}
+	return w, nil
+}
+
+// Write sends p right away in sync mode, queues it for the next batch otherwise
+func (w *SQSWriter) Write(p []byte) (int, error) {
+	var err error
+	if w.cfg.Sync {
+		err = w.send([][]byte{p})
+	} else {
+		err = w.batch.add(p)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush sends the pending entries
+func (w *SQSWriter) Flush() error {
+	if w.batch == nil {
+		return nil
+	}
+	return w.batch.flush()
+}
+
+// Close sends the pending entries, the client is left open
+func (w *SQSWriter) Close() error {
+	if w.batch == nil {
+		return nil
+	}
+	return w.batch.close()
+}
+
+func (w *SQSWriter) send(batch [][]byte) error {
+	messages := make([]SQSMessage, len(batch))
+	for i, entry := range batch {
+		messages[i] = w.message(strconv.Itoa(i), entry)
+	}
+
+	var errs []error
+	for _, messages := range sqsBatches(messages) {
+		if err := w.sendBatch(messages); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendBatch sends messages, resending the ones the service failed to enqueue
+func (w *SQSWriter) sendBatch(messages []SQSMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout*time.Duration(w.cfg.MaxRetries+1))
+	defer cancel()
+
+	var undelivered SQSDeliveryError
+	retryable := func(err error) bool {
+		return errors.As(err, &undelivered)
+	}
+
+	return retry(ctx, w.cfg.MaxRetries, w.cfg.RetryBackoff, retryable, func() error {
+		failed, err := w.cfg.Client.SendMessageBatch(ctx, w.cfg.QueueURL, messages)
+		if err != nil {
+			return err
+		}
+
+		if len(failed) == 0 {
+			return nil
+		}
+
+		failedIDs := make(map[string]bool, len(failed))
+		for _, id := range failed {
+			failedIDs[id] = true
+		}
+
+		pending := messages[:0:0]
+		for _, message := range messages {
+			if failedIDs[message.ID] {
+				pending = append(pending, message)
+			}
+		}
+		messages = pending
+
+		return SQSDeliveryError{QueueURL: w.cfg.QueueURL, Messages: len(messages)}
+	})
+}
+
+// message converts an entry to a message with the configured attributes and fifo ids
+func (w *SQSWriter) message(id string, entry []byte) SQSMessage {
+	message := SQSMessage{ID: id, Body: string(bytes.TrimRight(entry, "\n"))}
+	fields, err := decodeEntry(entry)
+	if err != nil {
+		fields = map[string]any{}
+	}
+
+	for _, path := range w.cfg.Attributes {
+		if value, ok := lookupField(fields, path); ok && fieldString(value) != "" {
+			if message.Attributes == nil {
+				message.Attributes = map[string]string{}
+			}
+			message.Attributes[path] = fieldString(value)
+		}
+	}
+
+	if !w.fifo {
+		return message
+	}
+
+	message.GroupID = fieldString(fields["app"])
+	if value, ok := lookupField(fields, w.cfg.GroupIDField); ok && w.cfg.GroupIDField != "" {
+		message.GroupID = fieldString(value)
+	}
+
+	if value, ok := lookupField(fields, w.cfg.DeduplicationIDField); ok && w.cfg.DeduplicationIDField != "" {
+		message.DeduplicationID = fieldString(value)
+	} else {
+		sum := sha256.Sum256([]byte(message.Body))
+		message.DeduplicationID = hex.EncodeToString(sum[:])
+	}
+
+	return message
+}
+
+// sqsBatches splits messages by the SendMessageBatch count and payload size limits
+func sqsBatches(messages []SQSMessage) [][]SQSMessage {
+	var batches [][]SQSMessage
+	start, size := 0, 0
+	for i, message := range messages {
+		messageSize := len(message.Body)
+		for name, value := range message.Attributes {
+			messageSize += len(name) + len(value)
+		}
+
+		if i > start && (i-start >= SQSMaxBatchMessages || size+messageSize > SQSMaxBatchBytes) {
+			batches = append(batches, messages[start:i])
+			start, size = i, 0
+		}
+		size += messageSize
+	}
+
+	if start < len(messages) {
+		batches = append(batches, messages[start:])
+	}
+
+	return batches
+}
+
+func createSQSLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg SQSLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewSQSWriter(cfg.SQSWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
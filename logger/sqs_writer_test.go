@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingSQS struct {
+	batches  [][]SQSMessage
+	failures [][]string
+	err      error
+}
+
+func (c *recordingSQS) SendMessageBatch(_ context.Context, _ string, messages []SQSMessage) ([]string, error) {
+	c.batches = append(c.batches, append([]SQSMessage(nil), messages...))
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	if len(c.failures) == 0 {
+		return nil, nil
+	}
+
+	failed := c.failures[0]
+	c.failures = c.failures[1:]
+	return failed, nil
+}
+
+func TestSQSDriver(t *testing.T) {
+	client := &recordingSQS{failures: [][]string{{"1"}}}
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   SQSLoggerDriver,
+		Values: map[string]any{
+			"Client":       client,
+			"queueUrl":     "https://sqs.eu-west-1.amazonaws.com/1/audit.fifo",
+			"attributes":   []string{"level", "ctx.user"},
+			"groupIdField": "actor",
+			"retryBackoff": time.Millisecond,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.With("actor", "admin").Warn("role granted")
+	log.Log("login")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Len(t, client.batches, 2)
+	assert.Len(t, client.batches[0], 2)
+	first := client.batches[0][0]
+	assert.Equal(t, map[string]string{"level": "WARN"}, first.Attributes)
+	assert.Equal(t, "admin", first.GroupID)
+	assert.Len(t, first.DeduplicationID, 64)
+	assert.Equal(t, "App", client.batches[0][1].GroupID)
+	assert.Equal(t, client.batches[0][1:], client.batches[1])
+}
+
+func TestSQSWriterSync(t *testing.T) {
+	client := &recordingSQS{err: errors.New("access denied")}
+	writer, err := NewSQSWriter(SQSWriterConfiguration{Client: client, QueueURL: "https://sqs/queue", Sync: true})
+	assert.Nil(t, err)
+
+	_, err = writer.Write([]byte(`{"message":"audit"}` + "\n"))
+	assert.EqualError(t, err, "access denied")
+	assert.Len(t, client.batches, 1)
+	assert.Empty(t, client.batches[0][0].GroupID)
+
+	messages := make([]SQSMessage, 25)
+	assert.Len(t, sqsBatches(messages), 3)
+
+	large := []SQSMessage{{Body: strings.Repeat("a", SQSMaxBatchBytes)}, {Body: "b"}}
+	assert.Len(t, sqsBatches(large), 2)
+
+	_, err = NewSQSWriter(SQSWriterConfiguration{Client: client, QueueURL: "q", Attributes: make([]string, 11)})
+	assert.Error(t, err)
+}
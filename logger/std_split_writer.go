@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"io"
+	"os"
+)
+
+// StdSplitWriter sends ERROR and FATAL entries to Stderr and the others to Stdout, following 12-factor conventions.
+// entries written without level go to Stdout
+type StdSplitWriter struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewStdSplitWriter returns a split writer over os.Stdout and os.Stderr
+func NewStdSplitWriter() *StdSplitWriter {
+	return &StdSplitWriter{Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+// Write writes p to Stdout
+func (w *StdSplitWriter) Write(p []byte) (int, error) {
+	return w.Stdout.Write(p)
+}
+
+// WriteLevel writes p to Stderr for ERROR and FATAL, to Stdout otherwise
+func (w *StdSplitWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	if level <= ERROR {
+		return w.Stderr.Write(p)
+	}
+	return w.Stdout.Write(p)
+}
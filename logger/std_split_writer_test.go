@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestStdSplitWriter(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	log, err := NewJsonLogger(context.Background(), &StdSplitWriter{Stdout: &stdout, Stderr: &stderr}, "App", "Scope", "uid", DEBUG, nil)
+	assert.Nil(t, err)
+
+	log.Debug("debug")
+	log.Log("log")
+	log.Warn("warn")
+	log.Error("error")
+
+	assert.Equal(t, 3, strings.Count(stdout.String(), "\n"))
+	assert.NotContains(t, stdout.String(), `"message":"error"`)
+	assert.Equal(t, 1, strings.Count(stderr.String(), "\n"))
+	assert.Contains(t, stderr.String(), `"message":"error"`)
+}
@@ -0,0 +1,320 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SyslogFormat syslog message format
+type SyslogFormat string
+
+// supported syslog formats
+const (
+	// RFC5424 default, octet counted over stream transports
+	RFC5424 SyslogFormat = "rfc5424"
+
+	// RFC3164 bsd syslog, newline terminated over stream transports
+	RFC3164 SyslogFormat = "rfc3164"
+)
+
+// SyslogFraming framing of the messages over stream transports, see RFC6587
+type SyslogFraming string
+
+// supported syslog framings
+const (
+	// OctetCounting prefixes every message with its length, default of RFC5424
+	OctetCounting SyslogFraming = "octet_counting"
+
+	// NonTransparentFraming terminates every message with a newline, default of RFC3164
+	NonTransparentFraming SyslogFraming = "non_transparent"
+)
+
+// syslog severities
+const (
+	syslogCrit    = 2
+	syslogErr     = 3
+	syslogWarning = 4
+	syslogInfo    = 6
+	syslogDebug   = 7
+)
+
+// syslogFacilities facility codes by name
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// local syslog sockets, tried in order when no network is configured
+var syslogLocalAddresses = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogSeverity returns the syslog severity of level: FATAL crit, ERROR err, WARN warning, LOG info and DEBUG debug
+func SyslogSeverity(level LogLevelEnum) int {
+	switch level {
+	case FATAL:
+		return syslogCrit
+	case ERROR:
+		return syslogErr
+	case WARN:
+		return syslogWarning
+	case DEBUG:
+		return syslogDebug
+	default:
+		return syslogInfo
+	}
+}
+
+// SyslogWriterConfiguration syslog sink configuration. without Network, the local syslog socket is used
+type SyslogWriterConfiguration struct {
+	// Network udp, tcp, tls, unix or unixgram. tls is tcp with TLS, eg: hosted syslog providers
+	Network  string        `toml:"network" json:"network" mapstructure:"network"`
+	Address  string        `toml:"address" json:"address" mapstructure:"address"`
+	Facility string        `toml:"facility" json:"facility" mapstructure:"facility"`
+	Tag      string        `toml:"tag" json:"tag" mapstructure:"tag"`
+	Format   SyslogFormat  `toml:"format" json:"format" mapstructure:"format"`
+	Hostname string        `toml:"hostname" json:"hostname" mapstructure:"hostname"`
+	Timeout  time.Duration `toml:"timeout" json:"timeout" mapstructure:"timeout"`
+
+	// Framing over stream transports, defaults to OctetCounting for RFC5424 and NonTransparentFraming for RFC3164
+	Framing SyslogFraming `toml:"framing" json:"framing" mapstructure:"framing"`
+
+	// TLS of the tls network
+	TLS TLSConfiguration `toml:"tls" json:"tls" mapstructure:"tls"`
+}
+
+// SyslogLoggerConfiguration json logger writing to syslog
+type SyslogLoggerConfiguration struct {
+	JSONLoggerConfiguration   `mapstructure:",squash"`
+	SyslogWriterConfiguration `mapstructure:",squash"`
+}
+
+// SyslogWriter writes every entry as a syslog message, with the severity of its level.
+// the connection is redialed once when a write fails
+type SyslogWriter struct {
+	cfg       SyslogWriterConfiguration
+	facility  int
+	pid       string
+	tlsConfig *tls.Config
+
+	mu     sync.Mutex
+	conn   net.Conn
+	stream bool
+}
+
+// NewSyslogWriter connects to the syslog server of cfg
+func NewSyslogWriter(cfg SyslogWriterConfiguration) (*SyslogWriter, error) {
+	if cfg.Facility == "" {
+		cfg.Facility = "user"
+	}
+
+	facility, ok := syslogFacilities[cfg.Facility]
+	if !ok {
+		return nil, fmt.Errorf("unknown syslog facility %s", cfg.Facility)
+	}
+
+	switch cfg.Format {
+	case "":
+		cfg.Format = RFC5424
+	case RFC5424, RFC3164:
+	default:
+		return nil, fmt.Errorf("unknown syslog format %s", cfg.Format)
+	}
+
+	switch cfg.Framing {
+	case "":
+		cfg.Framing = OctetCounting
+		if cfg.Format == RFC3164 {
+			cfg.Framing = NonTransparentFraming
+		}
+	case OctetCounting, NonTransparentFraming:
+	default:
+		return nil, fmt.Errorf("unknown syslog framing %s", cfg.Framing)
+	}
+
+	if cfg.Tag == "" {
+		cfg.Tag = os.Args[0]
+	}
+
+	if cfg.Hostname == "" {
+		cfg.Hostname = hostname
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWriteTimeout
+	}
+
+	w := &SyslogWriter{cfg: cfg, facility: facility, pid: strconv.Itoa(os.Getpid())}
+	if cfg.Network == "tls" {
+		tlsConfig, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		w.tlsConfig = tlsConfig
+	}
+
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Write writes p with the LOG severity
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(LOG, p)
+}
+
+// WriteLevel writes p with the severity of level
+func (w *SyslogWriter) WriteLevel(level LogLevelEnum, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := w.format(SyslogSeverity(level), bytes.TrimRight(p, "\n"), time.Now())
+	err := w.write(msg)
+	if err != nil {
+		if err = w.connect(); err == nil {
+			err = w.write(msg)
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+func (w *SyslogWriter) write(msg []byte) error {
+	if w.conn == nil {
+		return net.ErrClosed
+	}
+
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.cfg.Timeout)); err != nil {
+		return err
+	}
+
+	_, err := w.conn.Write(msg)
+	return err
+}
+
+// format builds the syslog message, framed for stream transports
+func (w *SyslogWriter) format(severity int, msg []byte, now time.Time) []byte {
+	pri := w.facility*8 + severity
+
+	var b bytes.Buffer
+	if w.cfg.Format == RFC3164 {
+		_, _ = fmt.Fprintf(&b, "<%d>%s %s %s[%s]: ", pri, now.Format(time.Stamp), w.cfg.Hostname, w.cfg.Tag, w.pid)
+	} else {
+		_, _ = fmt.Fprintf(&b, "<%d>1 %s %s %s %s - - ", pri, now.Format("2006-01-02T15:04:05.000000Z07:00"),
+			syslogHeaderField(w.cfg.Hostname, 255), syslogHeaderField(w.cfg.Tag, 48), w.pid)
+	}
+	b.Write(msg)
+
+	if !w.stream {
+		return b.Bytes()
+	}
+
+	if w.cfg.Framing == NonTransparentFraming {
+		b.WriteByte('\n')
+		return b.Bytes()
+	}
+
+	return append([]byte(strconv.Itoa(b.Len())+" "), b.Bytes()...)
+}
+
+// connect (re)dials the configured server, or the first local syslog socket available
+func (w *SyslogWriter) connect() error {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if w.cfg.Network == "tls" {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: w.cfg.Timeout}, "tcp", w.cfg.Address, w.tlsConfig)
+		if err != nil {
+			return err
+		}
+
+		w.conn = conn
+		w.stream = true
+		return nil
+	}
+
+	if w.cfg.Network != "" {
+		conn, err := net.DialTimeout(w.cfg.Network, w.cfg.Address, w.cfg.Timeout)
+		if err != nil {
+			return err
+		}
+
+		w.conn = conn
+		w.stream = w.cfg.Network != "udp" && w.cfg.Network != "udp4" && w.cfg.Network != "udp6" && w.cfg.Network != "unixgram"
+		return nil
+	}
+
+	for _, network := range []string{"unixgram", "unix"} {
+		for _, address := range syslogLocalAddresses {
+			if conn, err := net.DialTimeout(network, address, w.cfg.Timeout); err == nil {
+				w.conn = conn
+				w.stream = network == "unix"
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("unable to connect to the local syslog")
+}
+
+// syslogHeaderField returns value truncated to max, or the nil value - when empty
+func syslogHeaderField(value string, max int) string {
+	if value == "" {
+		return "-"
+	}
+
+	if len(value) > max {
+		return value[:max]
+	}
+
+	return value
+}
+
+func createSyslogLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg SyslogLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Tag == "" {
+		cfg.Tag = generic.App
+	}
+
+	writer, err := NewSyslogWriter(cfg.SyslogWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
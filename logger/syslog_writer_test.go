@@ -0,0 +1,166 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSeverity(t *testing.T) {
+	assert.Equal(t, 2, SyslogSeverity(FATAL))
+	assert.Equal(t, 3, SyslogSeverity(ERROR))
+	assert.Equal(t, 4, SyslogSeverity(WARN))
+	assert.Equal(t, 6, SyslogSeverity(LOG))
+	assert.Equal(t, 7, SyslogSeverity(DEBUG))
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   SyslogLoggerDriver,
+		Values:   map[string]any{"network": "udp", "address": conn.LocalAddr().String(), "facility": "local0"},
+	})
+	assert.Nil(t, err)
+	defer log.(Syncer).Close()
+
+	log.Warn("disk almost full")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	assert.Nil(t, err)
+
+	msg := string(buf[:n])
+	assert.True(t, strings.HasPrefix(msg, "<132>1 "), msg)
+	assert.Contains(t, msg, " App ")
+	assert.True(t, strings.HasSuffix(msg, `}`), msg)
+	assert.Contains(t, msg, `"message":"disk almost full"`)
+}
+
+func TestSyslogWriterTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 2)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			received <- line
+		}
+	}()
+
+	writer, err := NewSyslogWriter(SyslogWriterConfiguration{Network: "tcp", Address: listener.Addr().String(), Format: RFC3164, Tag: "app"})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	_, err = writer.WriteLevel(ERROR, []byte("{\"message\":\"boom\"}\n"))
+	assert.Nil(t, err)
+
+	select {
+	case msg := <-received:
+		assert.True(t, strings.HasPrefix(msg, "<11>"), msg)
+		assert.Contains(t, msg, " app[")
+		assert.True(t, strings.HasSuffix(msg, "]: {\"message\":\"boom\"}\n"), msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	assert.Equal(t, "45 <11>1 2024-01-02T03:04:05.000000Z h a 1 - - x", string(syslogMessage(RFC5424, true)))
+}
+
+func TestSyslogWriterTLS(t *testing.T) {
+	cert, certFile, keyFile := testCertificate(t)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	assert.Nil(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	})
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// octet counted frame: length, space, message
+		reader := bufio.NewReader(conn)
+		length, err := reader.ReadString(' ')
+		if err != nil {
+			return
+		}
+
+		size, _ := strconv.Atoi(strings.TrimSpace(length))
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(reader, msg); err == nil {
+			received <- string(msg)
+		}
+	}()
+
+	writer, err := NewSyslogWriter(SyslogWriterConfiguration{
+		Network: "tls",
+		Address: listener.Addr().String(),
+		Format:  RFC3164,
+		Framing: OctetCounting,
+		Tag:     "app",
+		TLS:     TLSConfiguration{CAFile: certFile, CertFile: certFile, KeyFile: keyFile},
+	})
+	assert.Nil(t, err)
+	defer writer.Close()
+
+	_, err = writer.WriteLevel(WARN, []byte("{\"message\":\"over tls\"}\n"))
+	assert.Nil(t, err)
+
+	select {
+	case msg := <-received:
+		assert.True(t, strings.HasPrefix(msg, "<12>"), msg)
+		assert.True(t, strings.HasSuffix(msg, "]: {\"message\":\"over tls\"}"), msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	_, err = NewSyslogWriter(SyslogWriterConfiguration{Network: "udp", Address: "127.0.0.1:1", Framing: "yolo"})
+	assert.NotNil(t, err)
+}
+
+func syslogMessage(format SyslogFormat, stream bool) []byte {
+	w := &SyslogWriter{cfg: SyslogWriterConfiguration{Format: format, Hostname: "h", Tag: "a"}, facility: 1, pid: "1", stream: stream}
+	return w.format(syslogErr, []byte("x"), time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+}
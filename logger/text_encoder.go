@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pixie-sh/logger-go/env"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ansi colors per level
+var levelColors = map[string]string{
+	"FATAL": "\033[35m",
+	"ERROR": "\033[31m",
+	"WARN":  "\033[33m",
+	"LOG":   "\033[32m",
+	"DEBUG": "\033[36m",
+}
+
+const (
+	colorReset = "\033[0m"
+	colorDim   = "\033[2m"
+)
+
+// entry keys rendered in the text line prefix
+var textPrefixKeys = map[string]bool{"timestamp": true, "level": true, "app": true, "scope": true, "message": true}
+
+// TextEncoder human readable encoder: timestamp, level, app/scope and message followed by sorted key=value fields
+type TextEncoder struct {
+	Color bool
+}
+
+// Encode renders the entry as a single text line
+func (e TextEncoder) Encode(entry map[string]any) ([]byte, error) {
+	level := fmt.Sprint(entry["level"])
+	paddedLevel := fmt.Sprintf("%-5s", level)
+	if color, ok := levelColors[strings.ToUpper(level)]; ok && e.Color {
+		paddedLevel = color + paddedLevel + colorReset
+	}
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "%v %s %v/%v  %v", entry["timestamp"], paddedLevel, entry["app"], entry["scope"], entry["message"])
+
+	fields := make(map[string]any, len(entry))
+	for key, value := range entry {
+		if !textPrefixKeys[key] {
+			flattenGroup(fields, key, value)
+		}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, err := textValue(fields[key])
+		if err != nil {
+			return nil, err
+		}
+
+		pair := key + "=" + value
+		if e.Color {
+			pair = colorDim + pair + colorReset
+		}
+		b.WriteString("  ")
+		b.WriteString(pair)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// flattenGroup adds value to fields under key, WithGroup groups are flattened to dotted keys, e.g. http.method
+func flattenGroup(fields map[string]any, key string, value any) {
+	group, ok := value.(fieldGroup)
+	if !ok {
+		fields[key] = value
+		return
+	}
+
+	for k, v := range group {
+		flattenGroup(fields, key+"."+k, v)
+	}
+}
+
+func textValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
+
+// IsTerminal check if f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// defaultEncoder encoder for loggers writing to stdout without explicit encoding:
+// LOG_PARSER text, logfmt or json when set, colored text on terminals, json otherwise
+func defaultEncoder() Encoder {
+	switch strings.ToLower(env.EnvParser()) {
+	case "text":
+		return TextEncoder{Color: IsTerminal(os.Stdout)}
+	case "logfmt":
+		return LogfmtEncoder{}
+	case "json":
+		return nil
+	}
+
+	if IsTerminal(os.Stdout) {
+		return TextEncoder{Color: true}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"github.com/pixie-sh/logger-go/caller"
+	"time"
+)
+
+// timer outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Timer measures an operation and logs its duration and outcome when stopped
+type Timer struct {
+	log   Interface
+	op    string
+	start time.Time
+}
+
+// StartTimer starts timing op, logging through the global Logger
+func StartTimer(op string) *Timer {
+	return NewTimer(Logger, op)
+}
+
+// NewTimer starts timing op, logging through log
+func NewTimer(log Interface, op string) *Timer {
+	return &Timer{log: log, op: op, start: time.Now()}
+}
+
+// Stop logs the operation as successful and returns the elapsed time
+func (t *Timer) Stop() time.Duration {
+	return t.stop(caller.Upper(), nil)
+}
+
+// StopErr logs the operation outcome based on err and returns the elapsed time.
+// failures are logged at ERROR with err attached
+func (t *Timer) StopErr(err error) time.Duration {
+	return t.stop(caller.Upper(), err)
+}
+
+func (t *Timer) stop(call caller.Ptr, err error) time.Duration {
+	elapsed := time.Since(t.start)
+
+	log := t.log.Clone().
+		With(OperationField, t.op).
+		With(DurationField, float64(elapsed)/float64(time.Millisecond))
+
+	if err != nil {
+		log = log.With(OutcomeField, OutcomeFailure).With(ErrorField, err)
+		logPreformatted(log, ERROR, call, t.op+" failed")
+		return elapsed
+	}
+
+	log = log.With(OutcomeField, OutcomeSuccess)
+	logPreformatted(log, LOG, call, t.op+" completed")
+	return elapsed
+}
@@ -0,0 +1,37 @@
+package logger
+
+import "time"
+
+// TimestampPrecision precision of the entries timestamp
+type TimestampPrecision string
+
+// supported timestamp precisions, fractions have fixed width so timestamps sort as text
+const (
+	SecondPrecision      TimestampPrecision = "s"
+	MillisecondPrecision TimestampPrecision = "ms"
+	MicrosecondPrecision TimestampPrecision = "us"
+	NanosecondPrecision  TimestampPrecision = "ns"
+)
+
+// Layout returns the RFC3339 based time layout for the precision, seconds by default
+func (p TimestampPrecision) Layout() string {
+	switch p {
+	case MillisecondPrecision:
+		return "2006-01-02T15:04:05.000Z07:00"
+	case MicrosecondPrecision:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	case NanosecondPrecision:
+		return "2006-01-02T15:04:05.000000000Z07:00"
+	default:
+		return time.RFC3339
+	}
+}
+
+// timestamp formats t with the logger precision, in utc unless LocalTime is set and UTC isn't forced
+func (i *JsonLogger) timestamp(t time.Time) string {
+	if i.UTC || !i.LocalTime {
+		t = t.UTC()
+	}
+
+	return t.Format(i.TimestampPrecision.Layout())
+}
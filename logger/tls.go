@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfiguration tls of the network sinks: custom CA, client certificate for mutual tls and server name
+type TLSConfiguration struct {
+	// Config used as is when set, the other fields are ignored
+	Config *tls.Config
+
+	// CAFile pem bundle of the trusted CAs, defaults to the system pool
+	CAFile string `toml:"caFile" json:"caFile" mapstructure:"caFile"`
+
+	// CertFile and KeyFile pem client certificate and key
+	CertFile string `toml:"certFile" json:"certFile" mapstructure:"certFile"`
+	KeyFile  string `toml:"keyFile" json:"keyFile" mapstructure:"keyFile"`
+
+	// ServerName verified against the server certificate, defaults to the host of the address
+	ServerName         string `toml:"serverName" json:"serverName" mapstructure:"serverName"`
+	InsecureSkipVerify bool   `toml:"insecureSkipVerify" json:"insecureSkipVerify" mapstructure:"insecureSkipVerify"`
+}
+
+// newTLSConfig loads the certificates of cfg
+func newTLSConfig(cfg TLSConfiguration) (*tls.Config, error) {
+	if cfg.Config != nil {
+		return cfg.Config.Clone(), nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/pixie-sh/logger-go/mapper"
+	"net/http"
+)
+
+// WebhookFormat request body format of a WebhookWriter
+type WebhookFormat string
+
+// supported webhook formats
+const (
+	// NDJSONWebhook one entry per line, the default
+	NDJSONWebhook WebhookFormat = "ndjson"
+
+	// ArrayWebhook json array of the entries
+	ArrayWebhook WebhookFormat = "array"
+)
+
+// WebhookWriterConfiguration http webhook sink configuration
+type WebhookWriterConfiguration struct {
+	HTTPSinkConfiguration `mapstructure:",squash"`
+
+	Format WebhookFormat `toml:"format" json:"format" mapstructure:"format"`
+}
+
+// WebhookLoggerConfiguration json logger posting to a webhook
+type WebhookLoggerConfiguration struct {
+	JSONLoggerConfiguration    `mapstructure:",squash"`
+	WebhookWriterConfiguration `mapstructure:",squash"`
+}
+
+// WebhookWriter posts batches of entries to an http endpoint, retrying 429, 5xx and transport errors
+// with exponential backoff
+type WebhookWriter struct {
+	format WebhookFormat
+	header http.Header
+	sender *httpSender
+	batch  *batcher
+}
+
+// NewWebhookWriter returns a writer posting to cfg.URL
+func NewWebhookWriter(cfg WebhookWriterConfiguration) (*WebhookWriter, error) {
+	header := http.Header{}
+	switch cfg.Format {
+	case "", NDJSONWebhook:
+		cfg.Format = NDJSONWebhook
+		header.Set("Content-Type", "application/x-ndjson")
+	case ArrayWebhook:
+		header.Set("Content-Type", "application/json")
+	default:
+		return nil, fmt.Errorf("unknown webhook format %s", cfg.Format)
+	}
+
+	sender, err := newHTTPSender(cfg.HTTPSinkConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WebhookWriter{format: cfg.Format, header: header, sender: sender}
+	batch, err := newSinkBatcher(cfg.BatchSize, cfg.FlushInterval, cfg.DeadLetter, w.post)
+	if err != nil {
+		return nil, err
+	}
+
+	w.batch = batch
+	return w, nil
+}
+
+// Write queues the entry for the next request
+func (w *WebhookWriter) Write(p []byte) (int, error) {
+	if err := w.batch.add(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush posts the pending entries
+func (w *WebhookWriter) Flush() error {
+	return w.batch.flush()
+}
+
+// Close posts the pending entries
+func (w *WebhookWriter) Close() error {
+	return w.batch.close()
+}
+
+func (w *WebhookWriter) post(batch [][]byte) error {
+	var body bytes.Buffer
+	if w.format == ArrayWebhook {
+		body.WriteByte('[')
+	}
+
+	for i, entry := range batch {
+		if w.format == NDJSONWebhook {
+			body.Write(entry)
+			continue
+		}
+
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		body.Write(bytes.TrimRight(entry, "\n"))
+	}
+
+	if w.format == ArrayWebhook {
+		body.WriteByte(']')
+	}
+
+	_, err := w.sender.send("", w.header, body.Bytes())
+	return err
+}
+
+func createWebhookLogger(ctx context.Context, generic Configuration) (Interface, error) {
+	var cfg WebhookLoggerConfiguration
+	err := mapper.ObjectToStruct(generic.Values, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := NewWebhookWriter(cfg.WebhookWriterConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Writer = writer
+	return newConfiguredJsonLogger(ctx, generic, cfg.JSONLoggerConfiguration)
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebhookDriver(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		bodies   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		assert.Equal(t, "token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	factory, err := NewFactory(context.Background(), DefaultFactoryConfiguration)
+	assert.Nil(t, err)
+
+	log, err := factory.Create(context.Background(), Configuration{
+		App:      "App",
+		Scope:    "Scope",
+		LogLevel: LOG,
+		Driver:   WebhookLoggerDriver,
+		Values: map[string]any{
+			"url":          server.URL,
+			"headers":      map[string]string{"Authorization": "token"},
+			"batchSize":    2,
+			"retryBackoff": time.Millisecond,
+		},
+	})
+	assert.Nil(t, err)
+
+	log.Log("first")
+	log.Log("second")
+	log.Log("third")
+	assert.Nil(t, log.(Syncer).Close())
+
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, 2, strings.Count(bodies[0], "\n"))
+	assert.Contains(t, bodies[1], `"message":"third"`)
+}
+
+func TestWebhookWriterArray(t *testing.T) {
+	var entries []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		gz, err := gzip.NewReader(r.Body)
+		assert.Nil(t, err)
+		body, _ := io.ReadAll(gz)
+		assert.Nil(t, json.Unmarshal(body, &entries))
+	}))
+	defer server.Close()
+
+	writer, err := NewWebhookWriter(WebhookWriterConfiguration{
+		HTTPSinkConfiguration: HTTPSinkConfiguration{URL: server.URL, Gzip: true},
+		Format:                ArrayWebhook,
+	})
+	assert.Nil(t, err)
+
+	_, _ = writer.Write([]byte(`{"message":"a"}` + "\n"))
+	_, _ = writer.Write([]byte(`{"message":"b"}` + "\n"))
+	assert.Nil(t, writer.Close())
+	assert.Equal(t, []map[string]any{{"message": "a"}, {"message": "b"}}, entries)
+
+	_, err = NewWebhookWriter(WebhookWriterConfiguration{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = writer.Write([]byte("x"))
+	assert.Error(t, err)
+}
@@ -5,9 +5,18 @@ import (
 	"reflect"
 )
 
-// ObjectToStruct map from interface{} map[string]interface{} to respective struct
+// ObjectToStruct map from interface{} map[string]interface{} to respective struct,
+// strings are decoded into encoding.TextUnmarshaler fields through UnmarshalText
 func ObjectToStruct(from interface{}, to interface{}) error {
-	return mapstructure.Decode(from, to)
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.TextUnmarshallerHookFunc(),
+		Result:     to,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(from)
 }
 
 // IsComplexType checks if the value is a complex type that should be JSON marshaled.
@@ -0,0 +1,130 @@
+// Package otelbridge implements the OpenTelemetry Logs Bridge API on top of the logger,
+// so libraries emitting logs through OpenTelemetry share its encoders, sinks and sampling
+package otelbridge
+
+import (
+	"context"
+	"github.com/pixie-sh/logger-go/logger"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/embedded"
+)
+
+// field keys added by the bridge
+const (
+	ScopeNameField    = "otel_scope"
+	ScopeVersionField = "otel_scope_version"
+	SeverityTextField = "otel_severity"
+)
+
+// LoggerProvider otel LoggerProvider handing out loggers backed by a logger.Interface
+type LoggerProvider struct {
+	embedded.LoggerProvider
+
+	log logger.Interface
+}
+
+// compile time check of the bridge implementations
+var (
+	_ otellog.LoggerProvider = (*LoggerProvider)(nil)
+	_ otellog.Logger         = (*Logger)(nil)
+)
+
+// NewLoggerProvider returns a provider logging through log
+func NewLoggerProvider(log logger.Interface) *LoggerProvider {
+	return &LoggerProvider{log: log}
+}
+
+// Logger returns a logger tagging its entries with the instrumentation scope name and version
+func (p *LoggerProvider) Logger(name string, options ...otellog.LoggerOption) otellog.Logger {
+	cfg := otellog.NewLoggerConfig(options...)
+
+	fields := []logger.Field{logger.String(ScopeNameField, name)}
+	if version := cfg.InstrumentationVersion(); version != "" {
+		fields = append(fields, logger.String(ScopeVersionField, version))
+	}
+
+	return &Logger{log: p.log.Clone().WithF(fields...)}
+}
+
+// Logger otel Logger writing records as logger entries
+type Logger struct {
+	embedded.Logger
+
+	log logger.Interface
+}
+
+// Emit logs record with the level matching its severity and its attributes as fields
+func (l *Logger) Emit(ctx context.Context, record otellog.Record) {
+	event := l.log.Clone().WithCtx(ctx).Event(Level(record.Severity()))
+	if event == nil {
+		return
+	}
+
+	if text := record.SeverityText(); text != "" {
+		event.Str(SeverityTextField, text)
+	}
+
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		event.Any(kv.Key, value(kv.Value))
+		return true
+	})
+
+	body := record.Body()
+	if body.Kind() == otellog.KindString {
+		event.Msg(body.AsString())
+		return
+	}
+	event.Msg(body.String())
+}
+
+// Enabled check if records of the record severity are logged
+func (l *Logger) Enabled(_ context.Context, record otellog.Record) bool {
+	return l.log.Enabled(Level(record.Severity()))
+}
+
+// Level maps an otel severity to the logger level, undefined severities are logged as LOG
+func Level(severity otellog.Severity) logger.LogLevelEnum {
+	switch {
+	case severity >= otellog.SeverityFatal1:
+		return logger.FATAL
+	case severity >= otellog.SeverityError1:
+		return logger.ERROR
+	case severity >= otellog.SeverityWarn1:
+		return logger.WARN
+	case severity >= otellog.SeverityInfo1, severity == otellog.SeverityUndefined:
+		return logger.LOG
+	default:
+		return logger.DEBUG
+	}
+}
+
+// value converts an otel value into its logged representation
+func value(v otellog.Value) any {
+	switch v.Kind() {
+	case otellog.KindBool:
+		return v.AsBool()
+	case otellog.KindFloat64:
+		return v.AsFloat64()
+	case otellog.KindInt64:
+		return v.AsInt64()
+	case otellog.KindString:
+		return v.AsString()
+	case otellog.KindBytes:
+		return v.AsBytes()
+	case otellog.KindSlice:
+		values := v.AsSlice()
+		items := make([]any, len(values))
+		for i, item := range values {
+			items[i] = value(item)
+		}
+		return items
+	case otellog.KindMap:
+		entries := map[string]any{}
+		for _, kv := range v.AsMap() {
+			entries[kv.Key] = value(kv.Value)
+		}
+		return entries
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,46 @@
+package otelbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/pixie-sh/logger-go/logger"
+	"github.com/stretchr/testify/assert"
+	otellog "go.opentelemetry.io/otel/log"
+	"testing"
+)
+
+func TestBridgeEmit(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log, _ := logger.NewJsonLogger(context.Background(), buf, "app", "scope", "uid", logger.LOG, nil)
+	otelLogger := NewLoggerProvider(log).Logger("lib", otellog.WithInstrumentationVersion("v1"))
+
+	var record otellog.Record
+	record.SetSeverity(otellog.SeverityWarn1)
+	record.SetSeverityText("warning")
+	record.SetBody(otellog.StringValue("slow query"))
+	record.AddAttributes(
+		otellog.Int("rows", 3),
+		otellog.Map("db", otellog.String("system", "postgres")),
+	)
+	otelLogger.Emit(context.Background(), record)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "slow query", entry["message"])
+	assert.Equal(t, "lib", entry[ScopeNameField])
+	assert.Equal(t, "v1", entry[ScopeVersionField])
+	assert.Equal(t, "warning", entry[SeverityTextField])
+	assert.Equal(t, float64(3), entry["rows"])
+	assert.Equal(t, map[string]any{"system": "postgres"}, entry["db"])
+
+	var debug otellog.Record
+	debug.SetSeverity(otellog.SeverityDebug1)
+	assert.False(t, otelLogger.Enabled(context.Background(), debug))
+	assert.True(t, otelLogger.Enabled(context.Background(), record))
+
+	buf.Reset()
+	otelLogger.Emit(context.Background(), debug)
+	assert.Empty(t, buf.String())
+}